@@ -10,6 +10,7 @@ import (
 	"github.com/thecodingmachine/gotenberg/internal/pkg/chrome"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xcontext"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
 )
 
@@ -26,6 +27,20 @@ func main() {
 	}
 	systemLogger.InfofOp(op, "Gotenberg %s", version)
 	systemLogger.DebugfOp(op, "configuration: %+v", config)
+	// fail early if an external tool is too old.
+	versions := []struct {
+		binary  string
+		minimum string
+	}{
+		{"pdftk", config.MinimumPDFtkVersion()},
+		{"qpdf", config.MinimumQPDFVersion()},
+		{"gs", config.MinimumGhostscriptVersion()},
+	}
+	for _, v := range versions {
+		if err := xexec.CheckVersion(systemLogger, v.binary, v.minimum); err != nil {
+			systemLogger.FatalOp(op, err)
+		}
+	}
 	if !config.DisableGoogleChrome() {
 		// start Google Chrome headless.
 		if err := chrome.Start(systemLogger); err != nil {
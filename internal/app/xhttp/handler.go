@@ -22,6 +22,8 @@ const (
 	htmlEndpoint         string = "/html"
 	urlEndpoint          string = "/url"
 	markdownEndpoint     string = "/markdown"
+	svgEndpoint          string = "/svg"
+	epubEndpoint         string = "/epub"
 	officeEndpoint       string = "/office"
 )
 
@@ -34,6 +36,8 @@ func isMultipartFormDataEndpoint(config conf.Config, path string) bool {
 			fmt.Sprintf("%s%s", convertGroupEndpoint, htmlEndpoint),
 			fmt.Sprintf("%s%s", convertGroupEndpoint, urlEndpoint),
 			fmt.Sprintf("%s%s", convertGroupEndpoint, markdownEndpoint),
+			fmt.Sprintf("%s%s", convertGroupEndpoint, svgEndpoint),
+			fmt.Sprintf("%s%s", convertGroupEndpoint, epubEndpoint),
 		)
 	}
 	if !config.DisableUnoconv() {
@@ -135,7 +139,10 @@ func urlHandler(c echo.Context) error {
 		if err != nil {
 			return err
 		}
-		p := printer.NewURLPrinter(logger, remoteURL, opts)
+		p, err := printer.NewURLPrinter(logger, remoteURL, opts)
+		if err != nil {
+			return err
+		}
 		return convert(ctx, p)
 	}
 	if err := resolver(); err != nil {
@@ -173,6 +180,83 @@ func markdownHandler(c echo.Context) error {
 	return nil
 }
 
+// svgHandler is the handler for converting
+// an SVG file to PDF.
+func svgHandler(c echo.Context) error {
+	const op string = "xhttp.svgHandler"
+	resolver := func() error {
+		ctx := context.MustCastFromEchoContext(c)
+		logger := ctx.XLogger()
+		logger.DebugOp(op, "handling SVG request...")
+		r := ctx.MustResource()
+		opts, err := chromePrinterOptions(r, ctx.Config())
+		if err != nil {
+			return err
+		}
+		svgOpts, err := svgPrinterOptions(r)
+		if err != nil {
+			return err
+		}
+		fpaths, err := r.Fpaths(".svg")
+		if err != nil {
+			return err
+		}
+		if len(fpaths) > 1 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("expected one SVG file, got '%d'", len(fpaths)),
+				nil,
+			)
+		}
+		p, err := printer.NewSVGPrinter(logger, fpaths[0], opts, svgOpts)
+		if err != nil {
+			return err
+		}
+		return convert(ctx, p)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+// epubHandler is the handler for converting
+// an EPUB file to PDF.
+func epubHandler(c echo.Context) error {
+	const op string = "xhttp.epubHandler"
+	resolver := func() error {
+		ctx := context.MustCastFromEchoContext(c)
+		logger := ctx.XLogger()
+		logger.DebugOp(op, "handling EPUB request...")
+		r := ctx.MustResource()
+		opts, err := chromePrinterOptions(r, ctx.Config())
+		if err != nil {
+			return err
+		}
+		epubOpts, err := epubPrinterOptions(r, opts)
+		if err != nil {
+			return err
+		}
+		fpaths, err := r.Fpaths(".epub")
+		if err != nil {
+			return err
+		}
+		if len(fpaths) > 1 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("expected one EPUB file, got '%d'", len(fpaths)),
+				nil,
+			)
+		}
+		p := printer.NewEPUBPrinter(logger, fpaths[0], opts, epubOpts)
+		return convert(ctx, p)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
 // officeHandler is the handler for converting
 // Office documents to PDF.
 func officeHandler(c echo.Context) error {
@@ -186,20 +270,7 @@ func officeHandler(c echo.Context) error {
 		if err != nil {
 			return err
 		}
-		fpaths, err := r.Fpaths(
-			".txt",
-			".rtf",
-			".fodt",
-			".doc",
-			".docx",
-			".odt",
-			".xls",
-			".xlsx",
-			".ods",
-			".ppt",
-			".pptx",
-			".odp",
-		)
+		fpaths, err := r.Fpaths(printer.OfficeExtensions()...)
 		if err != nil {
 			return err
 		}
@@ -59,6 +59,12 @@ func TestMergeHandler(t *testing.T) {
 	req = httptest.NewRequest(http.MethodPost, mergeEndpoint, body)
 	req.Header.Set(echo.HeaderContentType, contentType)
 	test.AssertStatusCode(t, http.StatusBadRequest, srv, req)
+	// should return 400 as "permissions" form field
+	// value is invalid.
+	body, contentType = test.MergeMultipartForm(t, map[string]string{string(resource.PermissionsArgKey): "Printing,foo"})
+	req = httptest.NewRequest(http.MethodPost, mergeEndpoint, body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	test.AssertStatusCode(t, http.StatusBadRequest, srv, req)
 	// should return 504.
 	body, contentType = test.MergeMultipartForm(t, map[string]string{string(resource.WaitTimeoutArgKey): "0"})
 	req = httptest.NewRequest(http.MethodPost, mergeEndpoint, body)
@@ -531,6 +537,75 @@ func TestMarkdownHandler(t *testing.T) {
 	test.AssertStatusCode(t, http.StatusBadRequest, srv, req)
 }
 
+func TestSVGHandler(t *testing.T) {
+	config := conf.DefaultConfig()
+	srv := New(config)
+	endpoint := fmt.Sprintf("%s%s", convertGroupEndpoint, svgEndpoint)
+	// should return 200.
+	body, contentType := test.SVGMultipartForm(t, nil)
+	req := httptest.NewRequest(http.MethodPost, endpoint, body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	test.AssertStatusCode(t, http.StatusOK, srv, req)
+	// should return 200 with a scale and a padding.
+	body, contentType = test.SVGMultipartForm(t, map[string]string{
+		string(resource.SVGScaleArgKey):   "2",
+		string(resource.SVGPaddingArgKey): "0.5",
+	})
+	req = httptest.NewRequest(http.MethodPost, endpoint, body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	test.AssertStatusCode(t, http.StatusOK, srv, req)
+	// should return 405 as Method is wrong.
+	req = httptest.NewRequest(http.MethodGet, endpoint, nil)
+	test.AssertStatusCode(t, http.StatusMethodNotAllowed, srv, req)
+	// should return 400 as "svgScale" form field
+	// value is < 0.01.
+	body, contentType = test.SVGMultipartForm(t, map[string]string{string(resource.SVGScaleArgKey): "0"})
+	req = httptest.NewRequest(http.MethodPost, endpoint, body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	test.AssertStatusCode(t, http.StatusBadRequest, srv, req)
+	// should return 400 as "svgPadding" form field
+	// value is < 0.
+	body, contentType = test.SVGMultipartForm(t, map[string]string{string(resource.SVGPaddingArgKey): "-1"})
+	req = httptest.NewRequest(http.MethodPost, endpoint, body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	test.AssertStatusCode(t, http.StatusBadRequest, srv, req)
+	// should return 504.
+	body, contentType = test.SVGMultipartForm(t, map[string]string{string(resource.WaitTimeoutArgKey): "0"})
+	req = httptest.NewRequest(http.MethodPost, endpoint, body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	test.AssertStatusCode(t, http.StatusGatewayTimeout, srv, req)
+}
+
+func TestEPUBHandler(t *testing.T) {
+	config := conf.DefaultConfig()
+	srv := New(config)
+	endpoint := fmt.Sprintf("%s%s", convertGroupEndpoint, epubEndpoint)
+	// should return 200.
+	body, contentType := test.EPUBMultipartForm(t, nil)
+	req := httptest.NewRequest(http.MethodPost, endpoint, body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	test.AssertStatusCode(t, http.StatusOK, srv, req)
+	// should return 200 without the EPUB stylesheet.
+	body, contentType = test.EPUBMultipartForm(t, map[string]string{string(resource.EPUBHonorStylesheetArgKey): "false"})
+	req = httptest.NewRequest(http.MethodPost, endpoint, body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	test.AssertStatusCode(t, http.StatusOK, srv, req)
+	// should return 405 as Method is wrong.
+	req = httptest.NewRequest(http.MethodGet, endpoint, nil)
+	test.AssertStatusCode(t, http.StatusMethodNotAllowed, srv, req)
+	// should return 400 as "epubHonorStylesheet"
+	// form field value is invalid.
+	body, contentType = test.EPUBMultipartForm(t, map[string]string{string(resource.EPUBHonorStylesheetArgKey): "foo"})
+	req = httptest.NewRequest(http.MethodPost, endpoint, body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	test.AssertStatusCode(t, http.StatusBadRequest, srv, req)
+	// should return 504.
+	body, contentType = test.EPUBMultipartForm(t, map[string]string{string(resource.WaitTimeoutArgKey): "0"})
+	req = httptest.NewRequest(http.MethodPost, endpoint, body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	test.AssertStatusCode(t, http.StatusGatewayTimeout, srv, req)
+}
+
 func TestOfficeHandler(t *testing.T) {
 	config := conf.DefaultConfig()
 	srv := New(config)
@@ -1,6 +1,8 @@
 package xhttp
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -8,6 +10,7 @@ import (
 	"github.com/thecodingmachine/gotenberg/internal/app/xhttp/pkg/context"
 	"github.com/thecodingmachine/gotenberg/internal/app/xhttp/pkg/resource"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/printer"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
@@ -139,11 +142,18 @@ func doErr(ctx context.Context, err error) error {
 	var httpErr error
 	errCode := xerror.Code(err)
 	errMessage := xerror.Message(err)
+	// surface the console messages of the page, if captured.
+	var consoleErr *printer.ConsoleError
+	if errors.As(err, &consoleErr) && len(consoleErr.Messages) > 0 {
+		errMessage = fmt.Sprintf("%s (console: %s)", errMessage, strings.Join(consoleErr.Messages, "; "))
+	}
 	switch errCode {
 	case xerror.InvalidCode:
 		httpErr = echo.NewHTTPError(http.StatusBadRequest, errMessage)
 	case xerror.TimeoutCode:
 		httpErr = echo.NewHTTPError(http.StatusGatewayTimeout, errMessage)
+	case xerror.CanceledCode:
+		httpErr = echo.NewHTTPError(http.StatusServiceUnavailable, errMessage)
 	default:
 		httpErr = echo.NewHTTPError(http.StatusInternalServerError, errMessage)
 	}
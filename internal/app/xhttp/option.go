@@ -1,21 +1,133 @@
 package xhttp
 
 import (
+	"fmt"
+
 	"github.com/thecodingmachine/gotenberg/internal/app/xhttp/pkg/resource"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/printer"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xassert"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
 )
 
 func mergePrinterOptions(r resource.Resource, config conf.Config) (printer.MergePrinterOptions, error) {
 	const op string = "xhttp.mergePrinterOptions"
-	waitTimeout, err := resource.WaitTimeoutArg(r, config)
+	resolver := func() (printer.MergePrinterOptions, error) {
+		waitTimeout, err := resource.WaitTimeoutArg(r, config)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		permissions, err := resource.PermissionsArg(r)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		sanitizeJS, err := r.BoolArg(resource.SanitizeJSArgKey, false)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		normalizePageSize, err := r.StringArg(
+			resource.NormalizePageSizeArgKey,
+			"",
+			xassert.StringOneOf(printer.PageSizes()),
+		)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		interleave, err := r.BoolArg(resource.InterleaveArgKey, false)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		grayscale, err := r.BoolArg(resource.GrayscaleArgKey, false)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		metadataPolicy, err := r.StringArg(
+			resource.MetadataPolicyArgKey,
+			printer.FirstMetadataPolicy,
+			xassert.StringOneOf(printer.MetadataPolicies()),
+		)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		metadata, err := resource.MetadataArg(r)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		cropRegions, err := resource.CropRegionsArg(r)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		alternateBackgrounds, err := r.BoolArg(resource.AlternateBackgroundsArgKey, false)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		oddPageBackground, err := r.StringArg(resource.OddPageBackgroundArgKey, printer.DefaultOddPageBackground)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		evenPageBackground, err := r.StringArg(resource.EvenPageBackgroundArgKey, printer.DefaultEvenPageBackground)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		mergeTool, err := r.StringArg(
+			resource.MergeToolArgKey,
+			printer.PDFtkMergeTool,
+			xassert.StringOneOf(printer.MergeTools()),
+		)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		userPassword, err := r.StringArg(resource.UserPasswordArgKey, "")
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		ownerPassword, err := r.StringArg(resource.OwnerPasswordArgKey, "")
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		indexPage, err := r.BoolArg(resource.IndexPageArgKey, false)
+		if err != nil {
+			return printer.MergePrinterOptions{}, err
+		}
+		var index *printer.ChromePrinterOptions
+		if indexPage {
+			// the index page is rendered thanks to Google Chrome.
+			if config.DisableGoogleChrome() {
+				return printer.MergePrinterOptions{}, xerror.Invalid(
+					op,
+					fmt.Sprintf("'%s' requires Google Chrome, which is disabled", resource.IndexPageArgKey),
+					nil,
+				)
+			}
+			opts := printer.DefaultChromePrinterOptions(config)
+			index = &opts
+		}
+		return printer.MergePrinterOptions{
+			WaitTimeout:          waitTimeout,
+			Permissions:          permissions,
+			SanitizeJS:           sanitizeJS,
+			Index:                index,
+			NormalizePageSize:    normalizePageSize,
+			Interleave:           interleave,
+			MaxProcesses:         config.MaximumConcurrentProcesses(),
+			Grayscale:            grayscale,
+			MetadataPolicy:       metadataPolicy,
+			Metadata:             metadata,
+			CropRegions:          cropRegions,
+			AlternateBackgrounds: alternateBackgrounds,
+			OddPageBackground:    oddPageBackground,
+			EvenPageBackground:   evenPageBackground,
+			WriteRateLimit:       config.OutputWriteRateLimit(),
+			Tool:                 mergeTool,
+			UserPassword:         userPassword,
+			OwnerPassword:        ownerPassword,
+		}, nil
+	}
+	opts, err := resolver()
 	if err != nil {
-		return printer.MergePrinterOptions{}, xerror.New(op, err)
+		return opts, xerror.New(op, err)
 	}
-	return printer.MergePrinterOptions{
-		WaitTimeout: waitTimeout,
-	}, nil
+	return opts, nil
 }
 
 func chromePrinterOptions(r resource.Resource, config conf.Config) (printer.ChromePrinterOptions, error) {
@@ -52,19 +164,467 @@ func chromePrinterOptions(r resource.Resource, config conf.Config) (printer.Chro
 		if err != nil {
 			return printer.ChromePrinterOptions{}, err
 		}
+		waitForSelector, err := r.StringArg(resource.WaitForSelectorArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		pollInterval, err := resource.PollIntervalArg(r, config)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		failOnHTTPError, err := r.BoolArg(resource.FailOnHTTPErrorArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		acceptableStatusCodes, err := resource.AcceptableStatusCodesArg(r)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		failOnSubresourceError, err := r.BoolArg(resource.FailOnSubresourceErrorArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		subresourceErrorTypes, err := resource.SubresourceErrorTypesArg(r)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		blockedResourceTypes, err := resource.BlockedResourceTypesArg(r)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		exactColors, err := r.BoolArg(resource.ExactColorsArgKey, true)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		requiredFonts, err := r.StringSliceArg(resource.RequiredFontsArgKey, nil)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		defaultFooterElements, err := resource.DefaultFooterElementsArg(r)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		autoLandscape, err := r.BoolArg(resource.AutoLandscapeArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		maxDOMNodes, err := r.Int64Arg(
+			resource.MaxDOMNodesArgKey,
+			0,
+			xassert.Int64NotInferiorTo(0),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		waitForReadyStateComplete, err := r.BoolArg(resource.WaitForReadyStateCompleteArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		waitForFrameCount, err := r.Int64Arg(
+			resource.WaitForFrameCountArgKey,
+			0,
+			xassert.Int64NotInferiorTo(0),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		documentLanguage, err := r.StringArg(resource.DocumentLanguageArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		subsetFonts, err := r.BoolArg(resource.SubsetFontsArgKey, true)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		acceptEncoding, err := r.StringArg(resource.AcceptEncodingArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		grayscale, err := r.BoolArg(resource.GrayscaleArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		scale, err := r.Float64Arg(resource.ScaleArgKey, 0.0)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		preferCSSPageSize, err := r.BoolArg(resource.PreferCSSPageSizeArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		waitForSubsequentNavigation, err := r.BoolArg(resource.WaitForSubsequentNavigationArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		subsequentNavigationTimeout, err := r.Float64Arg(
+			resource.SubsequentNavigationTimeoutArgKey,
+			2.0,
+			xassert.Float64NotInferiorTo(0.01),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		waitForNetworkIdle, err := r.BoolArg(resource.WaitForNetworkIdleArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		networkIdleDuration, err := r.Float64Arg(
+			resource.NetworkIdleDurationArgKey,
+			0.5,
+			xassert.Float64NotInferiorTo(0.01),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		contentWidth, err := r.Int64Arg(
+			resource.ContentWidthArgKey,
+			0,
+			xassert.Int64NotInferiorTo(0),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		username, err := r.StringArg(resource.UsernameArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		password, err := r.StringArg(resource.PasswordArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		cookies, err := resource.CookiesArg(r)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		extraHTTPHeaders, err := resource.ExtraHTTPHeadersArg(r)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		viewportWidth, err := r.Int64Arg(
+			resource.ViewportWidthArgKey,
+			0,
+			xassert.Int64NotInferiorTo(0),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		viewportHeight, err := r.Int64Arg(
+			resource.ViewportHeightArgKey,
+			0,
+			xassert.Int64NotInferiorTo(0),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		deviceScaleFactor, err := r.Float64Arg(
+			resource.DeviceScaleFactorArgKey,
+			0.0,
+			xassert.Float64NotInferiorTo(0.0),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		emulatedMediaType, err := r.StringArg(
+			resource.EmulatedMediaTypeArgKey,
+			printer.PrintMediaType,
+			xassert.StringOneOf(printer.MediaTypes()),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		forcePageCount, err := r.Int64Arg(
+			resource.ForcePageCountArgKey,
+			0,
+			xassert.Int64NotInferiorTo(0),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		disableThirdPartyJS, err := r.BoolArg(resource.DisableThirdPartyJSArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		disableJavaScript, err := r.BoolArg(resource.DisableJavaScriptArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		timezone, err := r.StringArg(resource.TimezoneArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		locale, err := r.StringArg(resource.LocaleArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		title, err := r.StringArg(resource.TitleArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		author, err := r.StringArg(resource.AuthorArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		subject, err := r.StringArg(resource.SubjectArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		keywords, err := r.StringArg(resource.KeywordsArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		customJavaScript, err := r.StringArg(resource.CustomJavaScriptArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		customCSS, err := r.StringArg(resource.CustomCSSArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		captureConsole, err := r.BoolArg(resource.CaptureConsoleArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		waitForText, err := r.StringArg(resource.WaitForTextArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		waitForExpression, err := r.StringArg(resource.WaitForExpressionArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		embedSourceHTML, err := r.BoolArg(resource.EmbedSourceHTMLArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		slowResources, err := r.Int64Arg(
+			resource.SlowResourcesArgKey,
+			0,
+			xassert.Int64NotInferiorTo(0),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		bypassCSP, err := r.BoolArg(resource.BypassCSPArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		pageRanges, err := r.StringArg(resource.PageRangesArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		printAreaSelector, err := r.StringArg(resource.PrintAreaSelectorArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		readerMode, err := r.BoolArg(resource.ReaderModeArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		qrCode, err := r.BoolArg(resource.QRCodeArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		qrCodeContent, err := r.StringArg(resource.QRCodeContentArgKey, "")
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		qrCodePosition, err := r.StringArg(
+			resource.QRCodePositionArgKey,
+			printer.BottomRightQRCodePosition,
+			xassert.StringOneOf(printer.QRCodePositions()),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		qrCodeSize, err := r.Float64Arg(
+			resource.QRCodeSizeArgKey,
+			0.6,
+			xassert.Float64NotInferiorTo(0.1),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		maxOutputBytes, err := r.Int64Arg(
+			resource.MaxOutputBytesArgKey,
+			0,
+			xassert.Int64NotInferiorTo(0),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		pdfx, err := r.StringArg(
+			resource.PDFXArgKey,
+			"",
+			xassert.StringOneOf(printer.PDFXVersions()),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		headerFontSize, err := r.Float64Arg(
+			resource.HeaderFontSizeArgKey,
+			0.0,
+			xassert.Float64NotInferiorTo(0.0),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		footerFontSize, err := r.Float64Arg(
+			resource.FooterFontSizeArgKey,
+			0.0,
+			xassert.Float64NotInferiorTo(0.0),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		acceptConsent, err := r.BoolArg(resource.AcceptConsentArgKey, false)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		acceptConsentSelectors, err := r.StringSliceArg(resource.AcceptConsentSelectorsArgKey, nil)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		// the default selectors are used only
+		// if no selectors are given.
+		if acceptConsent && len(acceptConsentSelectors) == 0 {
+			acceptConsentSelectors = printer.DefaultConsentSelectors()
+		}
+		minRenderTime, err := r.Float64Arg(
+			resource.MinRenderTimeArgKey,
+			0.0,
+			xassert.Float64NotInferiorTo(0.0),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		cpuThrottlingRate, err := r.Float64Arg(
+			resource.CPUThrottlingRateArgKey,
+			1.0,
+			xassert.Float64NotInferiorTo(1.0),
+		)
+		if err != nil {
+			return printer.ChromePrinterOptions{}, err
+		}
+		// an explicit orientation wins.
+		if r.HasArg(resource.LandscapeArgKey) {
+			autoLandscape = false
+		}
 		return printer.ChromePrinterOptions{
-			WaitTimeout:    waitTimeout,
-			WaitDelay:      waitDelay,
-			HeaderHTML:     headerHTML,
-			FooterHTML:     footerHTML,
-			PaperWidth:     paperWidth,
-			PaperHeight:    paperHeight,
-			MarginTop:      marginTop,
-			MarginBottom:   marginBottom,
-			MarginLeft:     marginLeft,
-			MarginRight:    marginRight,
-			Landscape:      landscape,
-			RpccBufferSize: googleChromeRpccBufferSize,
+			WaitTimeout:                 waitTimeout,
+			WaitDelay:                   waitDelay,
+			HeaderHTML:                  headerHTML,
+			FooterHTML:                  footerHTML,
+			PaperWidth:                  paperWidth,
+			PaperHeight:                 paperHeight,
+			MarginTop:                   marginTop,
+			MarginBottom:                marginBottom,
+			MarginLeft:                  marginLeft,
+			MarginRight:                 marginRight,
+			Landscape:                   landscape,
+			RpccBufferSize:              googleChromeRpccBufferSize,
+			WaitForSelector:             waitForSelector,
+			PollInterval:                pollInterval,
+			ICCProfilePath:              resource.ICCProfileFpath(r),
+			FailOnHTTPError:             failOnHTTPError,
+			AcceptableStatusCodes:       acceptableStatusCodes,
+			FailOnSubresourceError:      failOnSubresourceError,
+			SubresourceErrorTypes:       subresourceErrorTypes,
+			BlockedResourceTypes:        blockedResourceTypes,
+			ExactColors:                 exactColors,
+			RequiredFonts:               requiredFonts,
+			DefaultFooterElements:       defaultFooterElements,
+			AutoLandscape:               autoLandscape,
+			PoolIdleTimeout:             config.GoogleChromePoolIdleTimeout(),
+			PoolMaxLifetime:             config.GoogleChromePoolMaxLifetime(),
+			PoolSize:                    config.GoogleChromePoolSize(),
+			MaxDOMNodes:                 maxDOMNodes,
+			WaitForReadyStateComplete:   waitForReadyStateComplete,
+			WaitForFrameCount:           waitForFrameCount,
+			DocumentLanguage:            documentLanguage,
+			PDFX:                        pdfx,
+			MinRenderTime:               minRenderTime,
+			AcceptConsentSelectors:      acceptConsentSelectors,
+			SubsetFonts:                 subsetFonts,
+			AcceptEncoding:              acceptEncoding,
+			PrintAreaSelector:           printAreaSelector,
+			MaxOutputBytes:              maxOutputBytes,
+			ReaderMode:                  readerMode,
+			QRCode:                      qrCode,
+			QRCodeContent:               qrCodeContent,
+			QRCodePosition:              qrCodePosition,
+			QRCodeSize:                  qrCodeSize,
+			DebugURL:                    config.GoogleChromeDebugURL(),
+			StreamThresholdBytes:        config.GoogleChromeStreamThreshold(),
+			WriteRateLimit:              config.OutputWriteRateLimit(),
+			PageRanges:                  pageRanges,
+			BypassCSP:                   bypassCSP,
+			Grayscale:                   grayscale,
+			Scale:                       scale,
+			PreferCSSPageSize:           preferCSSPageSize,
+			SlowResources:               slowResources,
+			WaitForSubsequentNavigation: waitForSubsequentNavigation,
+			SubsequentNavigationTimeout: subsequentNavigationTimeout,
+			WaitForNetworkIdle:          waitForNetworkIdle,
+			NetworkIdleDuration:         networkIdleDuration,
+			EmbedSourceHTML:             embedSourceHTML,
+			WaitForExpression:           waitForExpression,
+			ExtraHTTPHeaders:            extraHTTPHeaders,
+			ContentWidthPx:              contentWidth,
+			Cookies:                     cookies,
+			Username:                    username,
+			Password:                    password,
+			WaitForText:                 waitForText,
+			ViewportWidth:               viewportWidth,
+			ViewportHeight:              viewportHeight,
+			DeviceScaleFactor:           deviceScaleFactor,
+			EmulatedMediaType:           emulatedMediaType,
+			CaptureConsole:              captureConsole,
+			CustomCSS:                   customCSS,
+			ForcePageCount:              forcePageCount,
+			DisableThirdPartyJS:         disableThirdPartyJS,
+			CustomJavaScript:            customJavaScript,
+			Title:                       title,
+			Author:                      author,
+			Subject:                     subject,
+			Keywords:                    keywords,
+			DisableJavaScript:           disableJavaScript,
+			Timezone:                    timezone,
+			Locale:                      locale,
+			HeaderFontSize:              headerFontSize,
+			FooterFontSize:              footerFontSize,
+			CPUThrottlingRate:           cpuThrottlingRate,
+		}, nil
+	}
+	opts, err := resolver()
+	if err != nil {
+		return opts, xerror.New(op, err)
+	}
+	return opts, nil
+}
+
+func svgPrinterOptions(r resource.Resource) (printer.SVGPrinterOptions, error) {
+	const op string = "xhttp.svgPrinterOptions"
+	resolver := func() (printer.SVGPrinterOptions, error) {
+		opts := printer.DefaultSVGPrinterOptions()
+		scale, err := r.Float64Arg(
+			resource.SVGScaleArgKey,
+			opts.Scale,
+			xassert.Float64NotInferiorTo(0.01),
+		)
+		if err != nil {
+			return printer.SVGPrinterOptions{}, err
+		}
+		padding, err := r.Float64Arg(
+			resource.SVGPaddingArgKey,
+			opts.Padding,
+			xassert.Float64NotInferiorTo(0.0),
+		)
+		if err != nil {
+			return printer.SVGPrinterOptions{}, err
+		}
+		return printer.SVGPrinterOptions{
+			Scale:   scale,
+			Padding: padding,
 		}, nil
 	}
 	opts, err := resolver()
@@ -74,6 +634,26 @@ func chromePrinterOptions(r resource.Resource, config conf.Config) (printer.Chro
 	return opts, nil
 }
 
+func epubPrinterOptions(r resource.Resource, opts printer.ChromePrinterOptions) (printer.EPUBPrinterOptions, error) {
+	const op string = "xhttp.epubPrinterOptions"
+	resolver := func() (printer.EPUBPrinterOptions, error) {
+		honorStylesheet, err := r.BoolArg(resource.EPUBHonorStylesheetArgKey, true)
+		if err != nil {
+			return printer.EPUBPrinterOptions{}, err
+		}
+		return printer.EPUBPrinterOptions{
+			PaperWidth:      opts.PaperWidth,
+			PaperHeight:     opts.PaperHeight,
+			HonorStylesheet: honorStylesheet,
+		}, nil
+	}
+	epubOpts, err := resolver()
+	if err != nil {
+		return epubOpts, xerror.New(op, err)
+	}
+	return epubOpts, nil
+}
+
 func officePrinterOptions(r resource.Resource, config conf.Config) (printer.OfficePrinterOptions, error) {
 	const op string = "xhttp.officePrinterOptions"
 	resolver := func() (printer.OfficePrinterOptions, error) {
@@ -85,9 +665,19 @@ func officePrinterOptions(r resource.Resource, config conf.Config) (printer.Offi
 		if err != nil {
 			return printer.OfficePrinterOptions{}, err
 		}
+		converter, err := r.StringArg(
+			resource.OfficeConverterArgKey,
+			printer.UnoconvOfficeConverter,
+			xassert.StringOneOf(printer.OfficeConverters()),
+		)
+		if err != nil {
+			return printer.OfficePrinterOptions{}, err
+		}
 		return printer.OfficePrinterOptions{
-			WaitTimeout: waitTimeout,
-			Landscape:   landscape,
+			WaitTimeout:  waitTimeout,
+			Landscape:    landscape,
+			MaxProcesses: config.MaximumConcurrentOfficeProcesses(),
+			Converter:    converter,
 		}, nil
 	}
 	opts, err := resolver()
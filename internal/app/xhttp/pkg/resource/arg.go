@@ -1,6 +1,9 @@
 package resource
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/printer"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xassert"
@@ -54,6 +57,255 @@ const (
 	// GoogleChromeRpccBufferSizeArgKey is the key
 	// of the argument "googleChromeRpccBufferSize".
 	GoogleChromeRpccBufferSizeArgKey ArgKey = "googleChromeRpccBufferSize"
+	// PermissionsArgKey is the key
+	// of the argument "permissions".
+	PermissionsArgKey ArgKey = "permissions"
+	// WaitForSelectorArgKey is the key
+	// of the argument "waitForSelector".
+	WaitForSelectorArgKey ArgKey = "waitForSelector"
+	// PollIntervalArgKey is the key
+	// of the argument "pollInterval".
+	PollIntervalArgKey ArgKey = "pollInterval"
+	// FailOnHTTPErrorArgKey is the key
+	// of the argument "failOnHTTPError".
+	FailOnHTTPErrorArgKey ArgKey = "failOnHTTPError"
+	// AcceptableStatusCodesArgKey is the key
+	// of the argument "acceptableStatusCodes".
+	AcceptableStatusCodesArgKey ArgKey = "acceptableStatusCodes"
+	// ExactColorsArgKey is the key
+	// of the argument "exactColors".
+	ExactColorsArgKey ArgKey = "exactColors"
+	// RequiredFontsArgKey is the key
+	// of the argument "requiredFonts".
+	RequiredFontsArgKey ArgKey = "requiredFonts"
+	// DefaultFooterElementsArgKey is the key
+	// of the argument "defaultFooterElements".
+	DefaultFooterElementsArgKey ArgKey = "defaultFooterElements"
+	// AutoLandscapeArgKey is the key
+	// of the argument "autoLandscape".
+	AutoLandscapeArgKey ArgKey = "autoLandscape"
+	// MaxDOMNodesArgKey is the key
+	// of the argument "maxDOMNodes".
+	MaxDOMNodesArgKey ArgKey = "maxDOMNodes"
+	// SVGScaleArgKey is the key
+	// of the argument "svgScale".
+	SVGScaleArgKey ArgKey = "svgScale"
+	// SVGPaddingArgKey is the key
+	// of the argument "svgPadding".
+	SVGPaddingArgKey ArgKey = "svgPadding"
+	// WaitForReadyStateCompleteArgKey is the key
+	// of the argument "waitForReadyStateComplete".
+	WaitForReadyStateCompleteArgKey ArgKey = "waitForReadyStateComplete"
+	// EPUBHonorStylesheetArgKey is the key
+	// of the argument "epubHonorStylesheet".
+	EPUBHonorStylesheetArgKey ArgKey = "epubHonorStylesheet"
+	// WaitForFrameCountArgKey is the key
+	// of the argument "waitForFrameCount".
+	WaitForFrameCountArgKey ArgKey = "waitForFrameCount"
+	// DocumentLanguageArgKey is the key
+	// of the argument "documentLanguage".
+	DocumentLanguageArgKey ArgKey = "documentLanguage"
+	// SanitizeJSArgKey is the key
+	// of the argument "sanitizeJS".
+	SanitizeJSArgKey ArgKey = "sanitizeJS"
+	// IndexPageArgKey is the key
+	// of the argument "indexPage".
+	IndexPageArgKey ArgKey = "indexPage"
+	// HeaderFontSizeArgKey is the key
+	// of the argument "headerFontSize".
+	HeaderFontSizeArgKey ArgKey = "headerFontSize"
+	// FooterFontSizeArgKey is the key
+	// of the argument "footerFontSize".
+	FooterFontSizeArgKey ArgKey = "footerFontSize"
+	// NormalizePageSizeArgKey is the key
+	// of the argument "normalizePageSize".
+	NormalizePageSizeArgKey ArgKey = "normalizePageSize"
+	// CPUThrottlingRateArgKey is the key
+	// of the argument "cpuThrottlingRate".
+	CPUThrottlingRateArgKey ArgKey = "cpuThrottlingRate"
+	// InterleaveArgKey is the key
+	// of the argument "interleave".
+	InterleaveArgKey ArgKey = "interleave"
+	// FailOnSubresourceErrorArgKey is the key
+	// of the argument "failOnSubresourceError".
+	FailOnSubresourceErrorArgKey ArgKey = "failOnSubresourceError"
+	// SubresourceErrorTypesArgKey is the key
+	// of the argument "subresourceErrorTypes".
+	SubresourceErrorTypesArgKey ArgKey = "subresourceErrorTypes"
+	// PDFXArgKey is the key
+	// of the argument "pdfx".
+	PDFXArgKey ArgKey = "pdfx"
+	// MinRenderTimeArgKey is the key
+	// of the argument "minRenderTime".
+	MinRenderTimeArgKey ArgKey = "minRenderTime"
+	// AcceptConsentArgKey is the key
+	// of the argument "acceptConsent".
+	AcceptConsentArgKey ArgKey = "acceptConsent"
+	// AcceptConsentSelectorsArgKey is the key
+	// of the argument "acceptConsentSelectors".
+	AcceptConsentSelectorsArgKey ArgKey = "acceptConsentSelectors"
+	// SubsetFontsArgKey is the key
+	// of the argument "subsetFonts".
+	SubsetFontsArgKey ArgKey = "subsetFonts"
+	// AcceptEncodingArgKey is the key
+	// of the argument "acceptEncoding".
+	AcceptEncodingArgKey ArgKey = "acceptEncoding"
+	// PrintAreaSelectorArgKey is the key
+	// of the argument "printAreaSelector".
+	PrintAreaSelectorArgKey ArgKey = "printAreaSelector"
+	// MaxOutputBytesArgKey is the key
+	// of the argument "maxOutputBytes".
+	MaxOutputBytesArgKey ArgKey = "maxOutputBytes"
+	// ReaderModeArgKey is the key
+	// of the argument "readerMode".
+	ReaderModeArgKey ArgKey = "readerMode"
+	// QRCodeArgKey is the key
+	// of the argument "qrCode".
+	QRCodeArgKey ArgKey = "qrCode"
+	// QRCodeContentArgKey is the key
+	// of the argument "qrCodeContent".
+	QRCodeContentArgKey ArgKey = "qrCodeContent"
+	// QRCodePositionArgKey is the key
+	// of the argument "qrCodePosition".
+	QRCodePositionArgKey ArgKey = "qrCodePosition"
+	// QRCodeSizeArgKey is the key
+	// of the argument "qrCodeSize".
+	QRCodeSizeArgKey ArgKey = "qrCodeSize"
+	// PageRangesArgKey is the key
+	// of the argument "pageRanges".
+	PageRangesArgKey ArgKey = "pageRanges"
+	// BypassCSPArgKey is the key
+	// of the argument "bypassCSP".
+	BypassCSPArgKey ArgKey = "bypassCSP"
+	// GrayscaleArgKey is the key
+	// of the argument "grayscale".
+	GrayscaleArgKey ArgKey = "grayscale"
+	// ScaleArgKey is the key
+	// of the argument "scale".
+	ScaleArgKey ArgKey = "scale"
+	// PreferCSSPageSizeArgKey is the key
+	// of the argument "preferCSSPageSize".
+	PreferCSSPageSizeArgKey ArgKey = "preferCSSPageSize"
+	// SlowResourcesArgKey is the key
+	// of the argument "slowResources".
+	SlowResourcesArgKey ArgKey = "slowResources"
+	// MetadataPolicyArgKey is the key
+	// of the argument "metadataPolicy".
+	MetadataPolicyArgKey ArgKey = "metadataPolicy"
+	// MetadataArgKey is the key
+	// of the argument "metadata".
+	MetadataArgKey ArgKey = "metadata"
+	// WaitForSubsequentNavigationArgKey is the key
+	// of the argument "waitForSubsequentNavigation".
+	WaitForSubsequentNavigationArgKey ArgKey = "waitForSubsequentNavigation"
+	// SubsequentNavigationTimeoutArgKey is the key
+	// of the argument "subsequentNavigationTimeout".
+	SubsequentNavigationTimeoutArgKey ArgKey = "subsequentNavigationTimeout"
+	// WaitForNetworkIdleArgKey is the key
+	// of the argument "waitForNetworkIdle".
+	WaitForNetworkIdleArgKey ArgKey = "waitForNetworkIdle"
+	// NetworkIdleDurationArgKey is the key
+	// of the argument "networkIdleDuration".
+	NetworkIdleDurationArgKey ArgKey = "networkIdleDuration"
+	// EmbedSourceHTMLArgKey is the key
+	// of the argument "embedSourceHTML".
+	EmbedSourceHTMLArgKey ArgKey = "embedSourceHTML"
+	// WaitForExpressionArgKey is the key
+	// of the argument "waitForExpression".
+	WaitForExpressionArgKey ArgKey = "waitForExpression"
+	// ExtraHTTPHeadersArgKey is the key
+	// of the argument "extraHttpHeaders".
+	ExtraHTTPHeadersArgKey ArgKey = "extraHttpHeaders"
+	// CropRegionsArgKey is the key
+	// of the argument "cropRegions".
+	CropRegionsArgKey ArgKey = "cropRegions"
+	// ContentWidthArgKey is the key
+	// of the argument "contentWidth".
+	ContentWidthArgKey ArgKey = "contentWidth"
+	// CookiesArgKey is the key
+	// of the argument "cookies".
+	CookiesArgKey ArgKey = "cookies"
+	// UsernameArgKey is the key
+	// of the argument "username".
+	UsernameArgKey ArgKey = "username"
+	// PasswordArgKey is the key
+	// of the argument "password".
+	PasswordArgKey ArgKey = "password"
+	// AlternateBackgroundsArgKey is the key
+	// of the argument "alternateBackgrounds".
+	AlternateBackgroundsArgKey ArgKey = "alternateBackgrounds"
+	// OddPageBackgroundArgKey is the key
+	// of the argument "oddPageBackground".
+	OddPageBackgroundArgKey ArgKey = "oddPageBackground"
+	// EvenPageBackgroundArgKey is the key
+	// of the argument "evenPageBackground".
+	EvenPageBackgroundArgKey ArgKey = "evenPageBackground"
+	// WaitForTextArgKey is the key
+	// of the argument "waitForText".
+	WaitForTextArgKey ArgKey = "waitForText"
+	// ViewportWidthArgKey is the key
+	// of the argument "viewportWidth".
+	ViewportWidthArgKey ArgKey = "viewportWidth"
+	// ViewportHeightArgKey is the key
+	// of the argument "viewportHeight".
+	ViewportHeightArgKey ArgKey = "viewportHeight"
+	// DeviceScaleFactorArgKey is the key
+	// of the argument "deviceScaleFactor".
+	DeviceScaleFactorArgKey ArgKey = "deviceScaleFactor"
+	// EmulatedMediaTypeArgKey is the key
+	// of the argument "emulatedMediaType".
+	EmulatedMediaTypeArgKey ArgKey = "emulatedMediaType"
+	// CaptureConsoleArgKey is the key
+	// of the argument "captureConsole".
+	CaptureConsoleArgKey ArgKey = "captureConsole"
+	// BlockedResourceTypesArgKey is the key
+	// of the argument "blockedResourceTypes".
+	BlockedResourceTypesArgKey ArgKey = "blockedResourceTypes"
+	// CustomCSSArgKey is the key
+	// of the argument "customCss".
+	CustomCSSArgKey ArgKey = "customCss"
+	// ForcePageCountArgKey is the key
+	// of the argument "forcePageCount".
+	ForcePageCountArgKey ArgKey = "forcePageCount"
+	// DisableThirdPartyJSArgKey is the key
+	// of the argument "disableThirdPartyJS".
+	DisableThirdPartyJSArgKey ArgKey = "disableThirdPartyJS"
+	// CustomJavaScriptArgKey is the key
+	// of the argument "customJavaScript".
+	CustomJavaScriptArgKey ArgKey = "customJavaScript"
+	// TitleArgKey is the key
+	// of the argument "title".
+	TitleArgKey ArgKey = "title"
+	// AuthorArgKey is the key
+	// of the argument "author".
+	AuthorArgKey ArgKey = "author"
+	// SubjectArgKey is the key
+	// of the argument "subject".
+	SubjectArgKey ArgKey = "subject"
+	// KeywordsArgKey is the key
+	// of the argument "keywords".
+	KeywordsArgKey ArgKey = "keywords"
+	// DisableJavaScriptArgKey is the key
+	// of the argument "disableJavaScript".
+	DisableJavaScriptArgKey ArgKey = "disableJavaScript"
+	// TimezoneArgKey is the key
+	// of the argument "timezone".
+	TimezoneArgKey ArgKey = "timezone"
+	// LocaleArgKey is the key
+	// of the argument "locale".
+	LocaleArgKey ArgKey = "locale"
+	// MergeToolArgKey is the key
+	// of the argument "mergeTool".
+	MergeToolArgKey ArgKey = "mergeTool"
+	// UserPasswordArgKey is the key
+	// of the argument "userPassword".
+	UserPasswordArgKey ArgKey = "userPassword"
+	// OwnerPasswordArgKey is the key
+	// of the argument "ownerPassword".
+	OwnerPasswordArgKey ArgKey = "ownerPassword"
+	// OfficeConverterArgKey is the key
+	// of the argument "officeConverter".
+	OfficeConverterArgKey ArgKey = "officeConverter"
 )
 
 /*
@@ -77,6 +329,89 @@ func ArgKeys() []ArgKey {
 		MarginRightArgKey,
 		LandscapeArgKey,
 		GoogleChromeRpccBufferSizeArgKey,
+		PermissionsArgKey,
+		WaitForSelectorArgKey,
+		PollIntervalArgKey,
+		FailOnHTTPErrorArgKey,
+		AcceptableStatusCodesArgKey,
+		ExactColorsArgKey,
+		RequiredFontsArgKey,
+		DefaultFooterElementsArgKey,
+		AutoLandscapeArgKey,
+		MaxDOMNodesArgKey,
+		SVGScaleArgKey,
+		SVGPaddingArgKey,
+		WaitForReadyStateCompleteArgKey,
+		EPUBHonorStylesheetArgKey,
+		WaitForFrameCountArgKey,
+		DocumentLanguageArgKey,
+		SanitizeJSArgKey,
+		IndexPageArgKey,
+		HeaderFontSizeArgKey,
+		FooterFontSizeArgKey,
+		NormalizePageSizeArgKey,
+		CPUThrottlingRateArgKey,
+		InterleaveArgKey,
+		FailOnSubresourceErrorArgKey,
+		SubresourceErrorTypesArgKey,
+		PDFXArgKey,
+		MinRenderTimeArgKey,
+		AcceptConsentArgKey,
+		AcceptConsentSelectorsArgKey,
+		SubsetFontsArgKey,
+		AcceptEncodingArgKey,
+		PrintAreaSelectorArgKey,
+		MaxOutputBytesArgKey,
+		ReaderModeArgKey,
+		QRCodeArgKey,
+		QRCodeContentArgKey,
+		QRCodePositionArgKey,
+		QRCodeSizeArgKey,
+		PageRangesArgKey,
+		BypassCSPArgKey,
+		GrayscaleArgKey,
+		ScaleArgKey,
+		PreferCSSPageSizeArgKey,
+		SlowResourcesArgKey,
+		MetadataPolicyArgKey,
+		MetadataArgKey,
+		WaitForSubsequentNavigationArgKey,
+		SubsequentNavigationTimeoutArgKey,
+		WaitForNetworkIdleArgKey,
+		NetworkIdleDurationArgKey,
+		EmbedSourceHTMLArgKey,
+		WaitForExpressionArgKey,
+		ExtraHTTPHeadersArgKey,
+		CropRegionsArgKey,
+		ContentWidthArgKey,
+		CookiesArgKey,
+		UsernameArgKey,
+		PasswordArgKey,
+		AlternateBackgroundsArgKey,
+		OddPageBackgroundArgKey,
+		EvenPageBackgroundArgKey,
+		WaitForTextArgKey,
+		ViewportWidthArgKey,
+		ViewportHeightArgKey,
+		DeviceScaleFactorArgKey,
+		EmulatedMediaTypeArgKey,
+		CaptureConsoleArgKey,
+		BlockedResourceTypesArgKey,
+		CustomCSSArgKey,
+		ForcePageCountArgKey,
+		DisableThirdPartyJSArgKey,
+		CustomJavaScriptArgKey,
+		TitleArgKey,
+		AuthorArgKey,
+		SubjectArgKey,
+		KeywordsArgKey,
+		DisableJavaScriptArgKey,
+		TimezoneArgKey,
+		LocaleArgKey,
+		MergeToolArgKey,
+		UserPasswordArgKey,
+		OwnerPasswordArgKey,
+		OfficeConverterArgKey,
 	}
 }
 
@@ -290,3 +625,231 @@ func GoogleChromeRpccBufferSizeArg(r Resource, config conf.Config) (int64, error
 	}
 	return result, nil
 }
+
+/*
+PermissionsArg is a helper for retrieving
+the "permissions" argument as a slice of
+strings.
+
+It also validates each permission against
+the ones PDFtk understands.
+*/
+func PermissionsArg(r Resource) ([]string, error) {
+	const op string = "resource.PermissionsArg"
+	result, err := r.StringSliceArg(
+		PermissionsArgKey,
+		nil,
+		xassert.StringOneOf(printer.Permissions()),
+	)
+	if err != nil {
+		return result, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+/*
+MetadataArg is a helper for retrieving
+the "metadata" argument, a JSON object of
+strings, as a map.
+*/
+func MetadataArg(r Resource) (map[string]string, error) {
+	const op string = "resource.MetadataArg"
+	value, err := r.StringArg(MetadataArgKey, "")
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	if value == "" {
+		return nil, nil
+	}
+	var metadata map[string]string
+	if err := json.Unmarshal([]byte(value), &metadata); err != nil {
+		return nil, xerror.Invalid(
+			op,
+			fmt.Sprintf("'%s' should be a JSON object of strings", MetadataArgKey),
+			err,
+		)
+	}
+	return metadata, nil
+}
+
+/*
+ExtraHTTPHeadersArg is a helper for retrieving
+the "extraHttpHeaders" argument, a JSON object
+of strings, as a map.
+*/
+func ExtraHTTPHeadersArg(r Resource) (map[string]string, error) {
+	const op string = "resource.ExtraHTTPHeadersArg"
+	value, err := r.StringArg(ExtraHTTPHeadersArgKey, "")
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	if value == "" {
+		return nil, nil
+	}
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(value), &headers); err != nil {
+		return nil, xerror.Invalid(
+			op,
+			fmt.Sprintf("'%s' should be a JSON object of strings", ExtraHTTPHeadersArgKey),
+			err,
+		)
+	}
+	return headers, nil
+}
+
+/*
+CropRegionsArg is a helper for retrieving
+the "cropRegions" argument, a JSON array of
+crop regions, as a slice of printer.CropRegion.
+*/
+func CropRegionsArg(r Resource) ([]printer.CropRegion, error) {
+	const op string = "resource.CropRegionsArg"
+	value, err := r.StringArg(CropRegionsArgKey, "")
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	if value == "" {
+		return nil, nil
+	}
+	var regions []printer.CropRegion
+	if err := json.Unmarshal([]byte(value), &regions); err != nil {
+		return nil, xerror.Invalid(
+			op,
+			fmt.Sprintf("'%s' should be a JSON array of crop regions", CropRegionsArgKey),
+			err,
+		)
+	}
+	return regions, nil
+}
+
+/*
+CookiesArg is a helper for retrieving
+the "cookies" argument, a JSON array of
+cookies, as a slice of printer.Cookie.
+*/
+func CookiesArg(r Resource) ([]printer.Cookie, error) {
+	const op string = "resource.CookiesArg"
+	value, err := r.StringArg(CookiesArgKey, "")
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	if value == "" {
+		return nil, nil
+	}
+	var cookies []printer.Cookie
+	if err := json.Unmarshal([]byte(value), &cookies); err != nil {
+		return nil, xerror.Invalid(
+			op,
+			fmt.Sprintf("'%s' should be a JSON array of cookies", CookiesArgKey),
+			err,
+		)
+	}
+	return cookies, nil
+}
+
+/*
+PollIntervalArg is a helper for retrieving
+the "pollInterval" argument as float64.
+
+It also validates it against the application
+configuration.
+*/
+func PollIntervalArg(r Resource, config conf.Config) (float64, error) {
+	const op string = "resource.PollIntervalArg"
+	opts := printer.DefaultChromePrinterOptions(config)
+	result, err := r.Float64Arg(
+		PollIntervalArgKey,
+		opts.PollInterval,
+		xassert.Float64NotInferiorTo(0.01),
+		xassert.Float64NotSuperiorTo(config.MaximumWaitTimeout()),
+	)
+	if err != nil {
+		return result, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+/*
+AcceptableStatusCodesArg is a helper for retrieving
+the "acceptableStatusCodes" argument as a slice of
+int64.
+
+It also validates that each status code is a
+valid HTTP status code.
+*/
+func AcceptableStatusCodesArg(r Resource) ([]int64, error) {
+	const op string = "resource.AcceptableStatusCodesArg"
+	result, err := r.Int64SliceArg(
+		AcceptableStatusCodesArgKey,
+		nil,
+		xassert.Int64NotInferiorTo(100),
+		xassert.Int64NotSuperiorTo(599),
+	)
+	if err != nil {
+		return result, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+/*
+DefaultFooterElementsArg is a helper for retrieving
+the "defaultFooterElements" argument as a slice of
+strings.
+
+It also validates each element against the ones
+available for the default footer.
+*/
+func DefaultFooterElementsArg(r Resource) ([]string, error) {
+	const op string = "resource.DefaultFooterElementsArg"
+	result, err := r.StringSliceArg(
+		DefaultFooterElementsArgKey,
+		nil,
+		xassert.StringOneOf(printer.FooterElements()),
+	)
+	if err != nil {
+		return result, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+/*
+SubresourceErrorTypesArg is a helper for retrieving
+the "subresourceErrorTypes" argument as a slice of
+strings.
+
+It also validates each type against the resource
+types Google Chrome reports.
+*/
+func SubresourceErrorTypesArg(r Resource) ([]string, error) {
+	const op string = "resource.SubresourceErrorTypesArg"
+	result, err := r.StringSliceArg(
+		SubresourceErrorTypesArgKey,
+		nil,
+		xassert.StringOneOf(printer.SubresourceTypes()),
+	)
+	if err != nil {
+		return result, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+/*
+BlockedResourceTypesArg is a helper for retrieving
+the "blockedResourceTypes" argument as a slice of
+strings.
+
+It also validates each type against the resource
+types which may be blocked.
+*/
+func BlockedResourceTypesArg(r Resource) ([]string, error) {
+	const op string = "resource.BlockedResourceTypesArg"
+	result, err := r.StringSliceArg(
+		BlockedResourceTypesArgKey,
+		nil,
+		xassert.StringOneOf(printer.BlockableResourceTypes()),
+	)
+	if err != nil {
+		return result, xerror.New(op, err)
+	}
+	return result, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/printer"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
 	"github.com/thecodingmachine/gotenberg/test"
 )
 
@@ -25,6 +26,89 @@ func TestArgKeys(t *testing.T) {
 		MarginRightArgKey,
 		LandscapeArgKey,
 		GoogleChromeRpccBufferSizeArgKey,
+		PermissionsArgKey,
+		WaitForSelectorArgKey,
+		PollIntervalArgKey,
+		FailOnHTTPErrorArgKey,
+		AcceptableStatusCodesArgKey,
+		ExactColorsArgKey,
+		RequiredFontsArgKey,
+		DefaultFooterElementsArgKey,
+		AutoLandscapeArgKey,
+		MaxDOMNodesArgKey,
+		SVGScaleArgKey,
+		SVGPaddingArgKey,
+		WaitForReadyStateCompleteArgKey,
+		EPUBHonorStylesheetArgKey,
+		WaitForFrameCountArgKey,
+		DocumentLanguageArgKey,
+		SanitizeJSArgKey,
+		IndexPageArgKey,
+		HeaderFontSizeArgKey,
+		FooterFontSizeArgKey,
+		NormalizePageSizeArgKey,
+		CPUThrottlingRateArgKey,
+		InterleaveArgKey,
+		FailOnSubresourceErrorArgKey,
+		SubresourceErrorTypesArgKey,
+		PDFXArgKey,
+		MinRenderTimeArgKey,
+		AcceptConsentArgKey,
+		AcceptConsentSelectorsArgKey,
+		SubsetFontsArgKey,
+		AcceptEncodingArgKey,
+		PrintAreaSelectorArgKey,
+		MaxOutputBytesArgKey,
+		ReaderModeArgKey,
+		QRCodeArgKey,
+		QRCodeContentArgKey,
+		QRCodePositionArgKey,
+		QRCodeSizeArgKey,
+		PageRangesArgKey,
+		BypassCSPArgKey,
+		GrayscaleArgKey,
+		ScaleArgKey,
+		PreferCSSPageSizeArgKey,
+		SlowResourcesArgKey,
+		MetadataPolicyArgKey,
+		MetadataArgKey,
+		WaitForSubsequentNavigationArgKey,
+		SubsequentNavigationTimeoutArgKey,
+		WaitForNetworkIdleArgKey,
+		NetworkIdleDurationArgKey,
+		EmbedSourceHTMLArgKey,
+		WaitForExpressionArgKey,
+		ExtraHTTPHeadersArgKey,
+		CropRegionsArgKey,
+		ContentWidthArgKey,
+		CookiesArgKey,
+		UsernameArgKey,
+		PasswordArgKey,
+		AlternateBackgroundsArgKey,
+		OddPageBackgroundArgKey,
+		EvenPageBackgroundArgKey,
+		WaitForTextArgKey,
+		ViewportWidthArgKey,
+		ViewportHeightArgKey,
+		DeviceScaleFactorArgKey,
+		EmulatedMediaTypeArgKey,
+		CaptureConsoleArgKey,
+		BlockedResourceTypesArgKey,
+		CustomCSSArgKey,
+		ForcePageCountArgKey,
+		DisableThirdPartyJSArgKey,
+		CustomJavaScriptArgKey,
+		TitleArgKey,
+		AuthorArgKey,
+		SubjectArgKey,
+		KeywordsArgKey,
+		DisableJavaScriptArgKey,
+		TimezoneArgKey,
+		LocaleArgKey,
+		MergeToolArgKey,
+		UserPasswordArgKey,
+		OwnerPasswordArgKey,
+		OfficeConverterArgKey,
 	}
 	assert.Equal(t, expected, ArgKeys())
 }
@@ -345,3 +429,282 @@ func TestGoogleChromeRpccBufferSizeArg(t *testing.T) {
 	err = r.Close()
 	assert.Nil(t, err)
 }
+
+func TestPermissionsArg(t *testing.T) {
+	const resourceDirectoryName string = "foo"
+	var expected []string
+	logger := test.DebugLogger()
+	r, err := New(logger, resourceDirectoryName)
+	assert.Nil(t, err)
+	// argument does not exist.
+	expected = nil
+	v, err := PermissionsArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// argument exist.
+	expected = []string{printer.PrintingPermission, printer.ScreenReadersPermission}
+	r.WithArg(PermissionsArgKey, "Printing,ScreenReaders")
+	v, err = PermissionsArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// should not be OK as
+	// argument value is invalid.
+	expected = nil
+	r.WithArg(PermissionsArgKey, "Printing,foo")
+	v, err = PermissionsArg(r)
+	test.AssertError(t, err)
+	assert.Equal(t, expected, v)
+	// finally...
+	err = r.Close()
+	assert.Nil(t, err)
+}
+
+func TestMetadataArg(t *testing.T) {
+	const resourceDirectoryName string = "foo"
+	logger := test.DebugLogger()
+	r, err := New(logger, resourceDirectoryName)
+	assert.Nil(t, err)
+	// argument does not exist.
+	v, err := MetadataArg(r)
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+	// argument exist.
+	r.WithArg(MetadataArgKey, `{"Title":"foo","Author":"bar"}`)
+	v, err = MetadataArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"Title": "foo", "Author": "bar"}, v)
+	// should not be OK as
+	// argument value is invalid.
+	r.WithArg(MetadataArgKey, `{"Title":1}`)
+	_, err = MetadataArg(r)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// finally...
+	err = r.Close()
+	assert.Nil(t, err)
+}
+
+func TestExtraHTTPHeadersArg(t *testing.T) {
+	const resourceDirectoryName string = "foo"
+	logger := test.DebugLogger()
+	r, err := New(logger, resourceDirectoryName)
+	assert.Nil(t, err)
+	// argument does not exist.
+	v, err := ExtraHTTPHeadersArg(r)
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+	// argument exist.
+	r.WithArg(ExtraHTTPHeadersArgKey, `{"X-Tenant-ID":"foo","X-Foo":"bar, baz"}`)
+	v, err = ExtraHTTPHeadersArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"X-Tenant-ID": "foo", "X-Foo": "bar, baz"}, v)
+	// should not be OK as
+	// argument value is invalid.
+	r.WithArg(ExtraHTTPHeadersArgKey, `{"X-Tenant-ID":1}`)
+	_, err = ExtraHTTPHeadersArg(r)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// finally...
+	err = r.Close()
+	assert.Nil(t, err)
+}
+
+func TestCropRegionsArg(t *testing.T) {
+	const resourceDirectoryName string = "foo"
+	logger := test.DebugLogger()
+	r, err := New(logger, resourceDirectoryName)
+	assert.Nil(t, err)
+	// argument does not exist.
+	v, err := CropRegionsArg(r)
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+	// argument exist.
+	r.WithArg(CropRegionsArgKey, `[{"page":2,"left":9,"bottom":9,"right":603,"top":783}]`)
+	v, err = CropRegionsArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, []printer.CropRegion{{Page: 2, Left: 9, Bottom: 9, Right: 603, Top: 783}}, v)
+	// should not be OK as
+	// argument value is invalid.
+	r.WithArg(CropRegionsArgKey, `{"page":2}`)
+	_, err = CropRegionsArg(r)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// finally...
+	err = r.Close()
+	assert.Nil(t, err)
+}
+
+func TestCookiesArg(t *testing.T) {
+	const resourceDirectoryName string = "foo"
+	logger := test.DebugLogger()
+	r, err := New(logger, resourceDirectoryName)
+	assert.Nil(t, err)
+	// argument does not exist.
+	v, err := CookiesArg(r)
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+	// argument exist.
+	r.WithArg(CookiesArgKey, `[{"name":"session","value":"foo","httpOnly":true}]`)
+	v, err = CookiesArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, []printer.Cookie{{Name: "session", Value: "foo", HTTPOnly: true}}, v)
+	// should not be OK as
+	// argument value is invalid.
+	r.WithArg(CookiesArgKey, `{"name":"session"}`)
+	_, err = CookiesArg(r)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// finally...
+	err = r.Close()
+	assert.Nil(t, err)
+}
+
+func TestPollIntervalArg(t *testing.T) {
+	const resourceDirectoryName string = "foo"
+	var expected float64
+	logger := test.DebugLogger()
+	config := conf.DefaultConfig()
+	opts := printer.DefaultChromePrinterOptions(config)
+	r, err := New(logger, resourceDirectoryName)
+	assert.Nil(t, err)
+	// argument does not exist.
+	expected = opts.PollInterval
+	v, err := PollIntervalArg(r, config)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// argument exist.
+	expected = 0.5
+	r.WithArg(PollIntervalArgKey, "0.5")
+	v, err = PollIntervalArg(r, config)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// should not be OK as argument
+	// value is < 0.01.
+	expected = opts.PollInterval
+	r.WithArg(PollIntervalArgKey, "0")
+	v, err = PollIntervalArg(r, config)
+	test.AssertError(t, err)
+	assert.Equal(t, expected, v)
+	// should not be OK as argument
+	// value is > config.MaximumWaitTimeout().
+	expected = opts.PollInterval
+	r.WithArg(PollIntervalArgKey, "31.0")
+	v, err = PollIntervalArg(r, config)
+	test.AssertError(t, err)
+	assert.Equal(t, expected, v)
+	// finally...
+	err = r.Close()
+	assert.Nil(t, err)
+}
+
+func TestAcceptableStatusCodesArg(t *testing.T) {
+	const resourceDirectoryName string = "foo"
+	var expected []int64
+	logger := test.DebugLogger()
+	r, err := New(logger, resourceDirectoryName)
+	assert.Nil(t, err)
+	// argument does not exist.
+	v, err := AcceptableStatusCodesArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// argument exist.
+	expected = []int64{401, 404}
+	r.WithArg(AcceptableStatusCodesArgKey, "401,404")
+	v, err = AcceptableStatusCodesArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// should not be OK as one of the
+	// argument values is > 599.
+	expected = nil
+	r.WithArg(AcceptableStatusCodesArgKey, "401,600")
+	v, err = AcceptableStatusCodesArg(r)
+	test.AssertError(t, err)
+	assert.Equal(t, expected, v)
+	// finally...
+	err = r.Close()
+	assert.Nil(t, err)
+}
+
+func TestDefaultFooterElementsArg(t *testing.T) {
+	const resourceDirectoryName string = "foo"
+	var expected []string
+	logger := test.DebugLogger()
+	r, err := New(logger, resourceDirectoryName)
+	assert.Nil(t, err)
+	// argument does not exist.
+	v, err := DefaultFooterElementsArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// argument exist.
+	expected = []string{printer.DateFooterElement, printer.PageNumberFooterElement}
+	r.WithArg(DefaultFooterElementsArgKey, "date,pageNumber")
+	v, err = DefaultFooterElementsArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// should not be OK as one of the
+	// argument values is invalid.
+	expected = nil
+	r.WithArg(DefaultFooterElementsArgKey, "date,foo")
+	v, err = DefaultFooterElementsArg(r)
+	test.AssertError(t, err)
+	assert.Equal(t, expected, v)
+	// finally...
+	err = r.Close()
+	assert.Nil(t, err)
+}
+
+func TestSubresourceErrorTypesArg(t *testing.T) {
+	const resourceDirectoryName string = "foo"
+	var expected []string
+	logger := test.DebugLogger()
+	r, err := New(logger, resourceDirectoryName)
+	assert.Nil(t, err)
+	// argument does not exist.
+	v, err := SubresourceErrorTypesArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// argument exist.
+	expected = []string{"Stylesheet", "Font"}
+	r.WithArg(SubresourceErrorTypesArgKey, "Stylesheet,Font")
+	v, err = SubresourceErrorTypesArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// should not be OK as one of the
+	// argument values is invalid.
+	expected = nil
+	r.WithArg(SubresourceErrorTypesArgKey, "Stylesheet,foo")
+	v, err = SubresourceErrorTypesArg(r)
+	test.AssertError(t, err)
+	assert.Equal(t, expected, v)
+	// finally...
+	err = r.Close()
+	assert.Nil(t, err)
+}
+
+func TestBlockedResourceTypesArg(t *testing.T) {
+	const resourceDirectoryName string = "foo"
+	var expected []string
+	logger := test.DebugLogger()
+	r, err := New(logger, resourceDirectoryName)
+	assert.Nil(t, err)
+	// argument does not exist.
+	v, err := BlockedResourceTypesArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// argument exist.
+	expected = []string{"Image", "Script"}
+	r.WithArg(BlockedResourceTypesArgKey, "Image,Script")
+	v, err = BlockedResourceTypesArg(r)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// should not be OK as the main
+	// document cannot be blocked.
+	expected = nil
+	r.WithArg(BlockedResourceTypesArgKey, "Image,Document")
+	v, err = BlockedResourceTypesArg(r)
+	test.AssertError(t, err)
+	assert.Equal(t, expected, v)
+	// finally...
+	err = r.Close()
+	assert.Nil(t, err)
+}
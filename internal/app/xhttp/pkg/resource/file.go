@@ -89,3 +89,18 @@ func HeaderFooterContents(r Resource, config conf.Config) (string, string, error
 		footerHTML,
 		nil
 }
+
+/*
+ICCProfileFpath is a helper for retrieving
+the path of the file "profile.icc".
+
+If there is no such file, returns an empty
+string.
+*/
+func ICCProfileFpath(r Resource) string {
+	file, ok := r.files["profile.icc"]
+	if !ok {
+		return ""
+	}
+	return file.fpath
+}
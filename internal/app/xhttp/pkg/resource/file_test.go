@@ -1,7 +1,9 @@
 package resource
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -44,3 +46,19 @@ func TestHeaderFooterContents(t *testing.T) {
 	err = r.Close()
 	assert.Nil(t, err)
 }
+
+func TestICCProfileFpath(t *testing.T) {
+	const resourceDirectoryName string = "foo"
+	logger := test.DebugLogger()
+	r, err := New(logger, resourceDirectoryName)
+	assert.Nil(t, err)
+	// file does not exist.
+	assert.Equal(t, "", ICCProfileFpath(r))
+	// file exists.
+	err = r.WithFile("profile.icc", strings.NewReader("foo"))
+	assert.Nil(t, err)
+	assert.Equal(t, fmt.Sprintf("%s/profile.icc", r.DirPath()), ICCProfileFpath(r))
+	// finally...
+	err = r.Close()
+	assert.Nil(t, err)
+}
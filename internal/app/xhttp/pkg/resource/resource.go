@@ -74,6 +74,10 @@ func (r Resource) Close() error {
 func (r *Resource) WithArg(key ArgKey, value string) {
 	const op string = "resource.Resource.WithArg"
 	r.args[key] = value
+	// the passwords must not end up in the logs.
+	if key == PasswordArgKey || key == UserPasswordArgKey || key == OwnerPasswordArgKey {
+		value = "***"
+	}
 	r.logger.DebugfOp(op, "added '%s' with value '%s' to resource args", key, value)
 }
 
@@ -120,6 +124,22 @@ func (r Resource) StringArg(key ArgKey, defaultValue string, rules ...xassert.Ru
 	return result, nil
 }
 
+/*
+StringSliceArg returns the values of the
+comma separated argument identified by
+given key.
+
+It works in the same manner as xassert.StringSlice.
+*/
+func (r Resource) StringSliceArg(key ArgKey, defaultValue []string, rules ...xassert.RuleString) ([]string, error) {
+	const op string = "resource.Resource.StringSliceArg"
+	result, err := xassert.StringSlice(string(key), r.args[key], defaultValue, rules...)
+	if err != nil {
+		return result, xerror.New(op, err)
+	}
+	return result, nil
+}
+
 /*
 Int64Arg returns the int64 representation of the
 argument identified by given key.
@@ -135,6 +155,21 @@ func (r Resource) Int64Arg(key ArgKey, defaultValue int64, rules ...xassert.Rule
 	return result, nil
 }
 
+/*
+Int64SliceArg returns the int64 representations of
+the comma separated argument identified by given key.
+
+It works in the same manner as xassert.Int64Slice.
+*/
+func (r Resource) Int64SliceArg(key ArgKey, defaultValue []int64, rules ...xassert.RuleInt64) ([]int64, error) {
+	const op string = "resource.Resource.Int64SliceArg"
+	result, err := xassert.Int64Slice(string(key), r.args[key], defaultValue, rules...)
+	if err != nil {
+		return result, xerror.New(op, err)
+	}
+	return result, nil
+}
+
 /*
 Float64Arg returns the float64 representation of the
 argument identified by given key.
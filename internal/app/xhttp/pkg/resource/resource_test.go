@@ -46,6 +46,41 @@ func TestStringArg(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestStringSliceArg(t *testing.T) {
+	const resourceDirectoryName string = "foo"
+	var (
+		defaultValue []string
+		expected     []string
+	)
+	rule := xassert.StringOneOf([]string{"FOO", "BAR"})
+	logger := test.DebugLogger()
+	r, err := New(logger, resourceDirectoryName)
+	assert.Nil(t, err)
+	// empty value, result should be equal
+	// to the default value.
+	r.WithArg(ResultFilenameArgKey, "")
+	v, err := r.StringSliceArg(ResultFilenameArgKey, defaultValue)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultValue, v)
+	// result should be equal to given values
+	// as they are one of "FOO" and "BAR".
+	expected = []string{"FOO", "BAR"}
+	r.WithArg(ResultFilenameArgKey, "FOO,BAR")
+	v, err = r.StringSliceArg(ResultFilenameArgKey, defaultValue, rule)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// should not be OK as one of the given
+	// values is not one of "FOO" and "BAR".
+	expected = defaultValue
+	r.WithArg(ResultFilenameArgKey, "FOO,BAZ")
+	v, err = r.StringSliceArg(ResultFilenameArgKey, defaultValue, rule)
+	test.AssertError(t, err)
+	assert.Equal(t, expected, v)
+	// finally...
+	err = r.Close()
+	assert.Nil(t, err)
+}
+
 func TestInt64Arg(t *testing.T) {
 	const (
 		resourceDirectoryName string = "foo"
@@ -89,6 +124,40 @@ func TestInt64Arg(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestInt64SliceArg(t *testing.T) {
+	const resourceDirectoryName string = "foo"
+	var (
+		defaultValue []int64
+		expected     []int64
+	)
+	rule := xassert.Int64NotInferiorTo(0)
+	logger := test.DebugLogger()
+	r, err := New(logger, resourceDirectoryName)
+	assert.Nil(t, err)
+	// empty value, result should be equal
+	// to the default value.
+	r.WithArg(WaitTimeoutArgKey, "")
+	v, err := r.Int64SliceArg(WaitTimeoutArgKey, defaultValue, rule)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultValue, v)
+	// result should be equal to given values.
+	expected = []int64{1, 2}
+	r.WithArg(WaitTimeoutArgKey, "1,2")
+	v, err = r.Int64SliceArg(WaitTimeoutArgKey, defaultValue, rule)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+	// should not be OK as one of the given
+	// values is < 0.
+	expected = defaultValue
+	r.WithArg(WaitTimeoutArgKey, "1,-2")
+	v, err = r.Int64SliceArg(WaitTimeoutArgKey, defaultValue, rule)
+	test.AssertError(t, err)
+	assert.Equal(t, expected, v)
+	// finally...
+	err = r.Close()
+	assert.Nil(t, err)
+}
+
 func TestFloat64Arg(t *testing.T) {
 	const (
 		resourceDirectoryName string  = "foo"
@@ -24,6 +24,8 @@ func New(config conf.Config) *echo.Echo {
 		g.POST(htmlEndpoint, htmlHandler)
 		g.POST(urlEndpoint, urlHandler)
 		g.POST(markdownEndpoint, markdownHandler)
+		g.POST(svgEndpoint, svgHandler)
+		g.POST(epubEndpoint, epubHandler)
 	}
 	if !config.DisableUnoconv() {
 		g.POST(officeEndpoint, officeHandler)
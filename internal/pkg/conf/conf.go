@@ -37,6 +37,39 @@ const (
 	// DefaultGoogleChromeRpccBufferSizeEnvVar contains the name
 	// of the environment variable "DEFAULT_GOOGLE_CHROME_RPCC_BUFFER_SIZE".
 	DefaultGoogleChromeRpccBufferSizeEnvVar string = "DEFAULT_GOOGLE_CHROME_RPCC_BUFFER_SIZE"
+	// GoogleChromePoolIdleTimeoutEnvVar contains the name
+	// of the environment variable "GOOGLE_CHROME_POOL_IDLE_TIMEOUT".
+	GoogleChromePoolIdleTimeoutEnvVar string = "GOOGLE_CHROME_POOL_IDLE_TIMEOUT"
+	// GoogleChromePoolMaxLifetimeEnvVar contains the name
+	// of the environment variable "GOOGLE_CHROME_POOL_MAX_LIFETIME".
+	GoogleChromePoolMaxLifetimeEnvVar string = "GOOGLE_CHROME_POOL_MAX_LIFETIME"
+	// GoogleChromePoolSizeEnvVar contains the name
+	// of the environment variable "GOOGLE_CHROME_POOL_SIZE".
+	GoogleChromePoolSizeEnvVar string = "GOOGLE_CHROME_POOL_SIZE"
+	// GoogleChromeDebugURLEnvVar contains the name
+	// of the environment variable "GOOGLE_CHROME_DEBUG_URL".
+	GoogleChromeDebugURLEnvVar string = "GOOGLE_CHROME_DEBUG_URL"
+	// GoogleChromeStreamThresholdEnvVar contains the name
+	// of the environment variable "GOOGLE_CHROME_STREAM_THRESHOLD".
+	GoogleChromeStreamThresholdEnvVar string = "GOOGLE_CHROME_STREAM_THRESHOLD"
+	// OutputWriteRateLimitEnvVar contains the name
+	// of the environment variable "OUTPUT_WRITE_RATE_LIMIT".
+	OutputWriteRateLimitEnvVar string = "OUTPUT_WRITE_RATE_LIMIT"
+	// MaximumConcurrentProcessesEnvVar contains the name
+	// of the environment variable "MAXIMUM_CONCURRENT_PROCESSES".
+	MaximumConcurrentProcessesEnvVar string = "MAXIMUM_CONCURRENT_PROCESSES"
+	// MaximumConcurrentOfficeProcessesEnvVar contains the name
+	// of the environment variable "MAXIMUM_CONCURRENT_OFFICE_PROCESSES".
+	MaximumConcurrentOfficeProcessesEnvVar string = "MAXIMUM_CONCURRENT_OFFICE_PROCESSES"
+	// MinimumPDFtkVersionEnvVar contains the name
+	// of the environment variable "MINIMUM_PDFTK_VERSION".
+	MinimumPDFtkVersionEnvVar string = "MINIMUM_PDFTK_VERSION"
+	// MinimumQPDFVersionEnvVar contains the name
+	// of the environment variable "MINIMUM_QPDF_VERSION".
+	MinimumQPDFVersionEnvVar string = "MINIMUM_QPDF_VERSION"
+	// MinimumGhostscriptVersionEnvVar contains the name
+	// of the environment variable "MINIMUM_GHOSTSCRIPT_VERSION".
+	MinimumGhostscriptVersionEnvVar string = "MINIMUM_GHOSTSCRIPT_VERSION"
 )
 
 // Config contains the application
@@ -53,6 +86,17 @@ type Config struct {
 	logLevel                          xlog.Level
 	maximumGoogleChromeRpccBufferSize int64
 	defaultGoogleChromeRpccBufferSize int64
+	googleChromePoolIdleTimeout       float64
+	googleChromePoolMaxLifetime       float64
+	googleChromePoolSize              int64
+	googleChromeDebugURL              string
+	googleChromeStreamThreshold       int64
+	outputWriteRateLimit              int64
+	maximumConcurrentProcesses        int64
+	maximumConcurrentOfficeProcesses  int64
+	minimumPDFtkVersion               string
+	minimumQPDFVersion                string
+	minimumGhostscriptVersion         string
 }
 
 // DefaultConfig returns the default
@@ -70,6 +114,17 @@ func DefaultConfig() Config {
 		logLevel:                          xlog.InfoLevel,
 		maximumGoogleChromeRpccBufferSize: 104857600, // ~100 MB
 		defaultGoogleChromeRpccBufferSize: 1048576,   // 1 MB
+		googleChromePoolIdleTimeout:       30.0,
+		googleChromePoolMaxLifetime:       300.0,
+		googleChromePoolSize:              5,
+		googleChromeDebugURL:              "http://localhost:9222",
+		googleChromeStreamThreshold:       0,
+		outputWriteRateLimit:              0,
+		maximumConcurrentProcesses:        10,
+		maximumConcurrentOfficeProcesses:  2,
+		minimumPDFtkVersion:               "",
+		minimumQPDFVersion:                "",
+		minimumGhostscriptVersion:         "",
 	}
 }
 
@@ -173,6 +228,101 @@ func FromEnv() (Config, error) {
 		if err != nil {
 			return c, err
 		}
+		googleChromePoolIdleTimeout, err := xassert.Float64FromEnv(
+			GoogleChromePoolIdleTimeoutEnvVar,
+			c.googleChromePoolIdleTimeout,
+			xassert.Float64NotInferiorTo(0.0),
+		)
+		c.googleChromePoolIdleTimeout = googleChromePoolIdleTimeout
+		if err != nil {
+			return c, err
+		}
+		googleChromePoolMaxLifetime, err := xassert.Float64FromEnv(
+			GoogleChromePoolMaxLifetimeEnvVar,
+			c.googleChromePoolMaxLifetime,
+			xassert.Float64NotInferiorTo(0.0),
+		)
+		c.googleChromePoolMaxLifetime = googleChromePoolMaxLifetime
+		if err != nil {
+			return c, err
+		}
+		googleChromePoolSize, err := xassert.Int64FromEnv(
+			GoogleChromePoolSizeEnvVar,
+			c.googleChromePoolSize,
+			xassert.Int64NotInferiorTo(0),
+		)
+		c.googleChromePoolSize = googleChromePoolSize
+		if err != nil {
+			return c, err
+		}
+		googleChromeDebugURL, err := xassert.StringFromEnv(
+			GoogleChromeDebugURLEnvVar,
+			c.googleChromeDebugURL,
+		)
+		c.googleChromeDebugURL = googleChromeDebugURL
+		if err != nil {
+			return c, err
+		}
+		googleChromeStreamThreshold, err := xassert.Int64FromEnv(
+			GoogleChromeStreamThresholdEnvVar,
+			c.googleChromeStreamThreshold,
+			xassert.Int64NotInferiorTo(0),
+		)
+		c.googleChromeStreamThreshold = googleChromeStreamThreshold
+		if err != nil {
+			return c, err
+		}
+		outputWriteRateLimit, err := xassert.Int64FromEnv(
+			OutputWriteRateLimitEnvVar,
+			c.outputWriteRateLimit,
+			xassert.Int64NotInferiorTo(0),
+		)
+		c.outputWriteRateLimit = outputWriteRateLimit
+		if err != nil {
+			return c, err
+		}
+		maximumConcurrentProcesses, err := xassert.Int64FromEnv(
+			MaximumConcurrentProcessesEnvVar,
+			c.maximumConcurrentProcesses,
+			xassert.Int64NotInferiorTo(1),
+		)
+		c.maximumConcurrentProcesses = maximumConcurrentProcesses
+		if err != nil {
+			return c, err
+		}
+		maximumConcurrentOfficeProcesses, err := xassert.Int64FromEnv(
+			MaximumConcurrentOfficeProcessesEnvVar,
+			c.maximumConcurrentOfficeProcesses,
+			xassert.Int64NotInferiorTo(1),
+		)
+		c.maximumConcurrentOfficeProcesses = maximumConcurrentOfficeProcesses
+		if err != nil {
+			return c, err
+		}
+		minimumPDFtkVersion, err := xassert.StringFromEnv(
+			MinimumPDFtkVersionEnvVar,
+			c.minimumPDFtkVersion,
+		)
+		c.minimumPDFtkVersion = minimumPDFtkVersion
+		if err != nil {
+			return c, err
+		}
+		minimumQPDFVersion, err := xassert.StringFromEnv(
+			MinimumQPDFVersionEnvVar,
+			c.minimumQPDFVersion,
+		)
+		c.minimumQPDFVersion = minimumQPDFVersion
+		if err != nil {
+			return c, err
+		}
+		minimumGhostscriptVersion, err := xassert.StringFromEnv(
+			MinimumGhostscriptVersionEnvVar,
+			c.minimumGhostscriptVersion,
+		)
+		c.minimumGhostscriptVersion = minimumGhostscriptVersion
+		if err != nil {
+			return c, err
+		}
 		return c, nil
 	}
 	result, err := resolver()
@@ -253,3 +403,73 @@ func (c Config) MaximumGoogleChromeRpccBufferSize() int64 {
 func (c Config) DefaultGoogleChromeRpccBufferSize() int64 {
 	return c.defaultGoogleChromeRpccBufferSize
 }
+
+// GoogleChromePoolIdleTimeout returns the duration after which
+// an idle connection to Google Chrome is closed from the configuration.
+func (c Config) GoogleChromePoolIdleTimeout() float64 {
+	return c.googleChromePoolIdleTimeout
+}
+
+// GoogleChromePoolMaxLifetime returns the maximum lifetime
+// of a connection to Google Chrome from the configuration.
+func (c Config) GoogleChromePoolMaxLifetime() float64 {
+	return c.googleChromePoolMaxLifetime
+}
+
+// GoogleChromePoolSize returns the maximum number
+// of idle connections to Google Chrome from the configuration.
+func (c Config) GoogleChromePoolSize() int64 {
+	return c.googleChromePoolSize
+}
+
+// GoogleChromeDebugURL returns the URL of the Google Chrome
+// DevTools endpoint from the configuration.
+func (c Config) GoogleChromeDebugURL() string {
+	return c.googleChromeDebugURL
+}
+
+// GoogleChromeStreamThreshold returns the number of bytes
+// loaded by a page above which Google Chrome streams the
+// resulting PDF from the configuration.
+func (c Config) GoogleChromeStreamThreshold() int64 {
+	return c.googleChromeStreamThreshold
+}
+
+// OutputWriteRateLimit returns the maximum number of bytes
+// per second written to the resulting files from the
+// configuration.
+func (c Config) OutputWriteRateLimit() int64 {
+	return c.outputWriteRateLimit
+}
+
+// MaximumConcurrentProcesses returns the maximum number of
+// merge and rasterize conversions running at the same time
+// from the configuration.
+func (c Config) MaximumConcurrentProcesses() int64 {
+	return c.maximumConcurrentProcesses
+}
+
+// MaximumConcurrentOfficeProcesses returns the maximum number
+// of Office conversions running at the same time from the
+// configuration.
+func (c Config) MaximumConcurrentOfficeProcesses() int64 {
+	return c.maximumConcurrentOfficeProcesses
+}
+
+// MinimumPDFtkVersion returns the minimum version of PDFtk
+// from the configuration. An empty string means no minimum.
+func (c Config) MinimumPDFtkVersion() string {
+	return c.minimumPDFtkVersion
+}
+
+// MinimumQPDFVersion returns the minimum version of qpdf
+// from the configuration. An empty string means no minimum.
+func (c Config) MinimumQPDFVersion() string {
+	return c.minimumQPDFVersion
+}
+
+// MinimumGhostscriptVersion returns the minimum version of Ghostscript
+// from the configuration. An empty string means no minimum.
+func (c Config) MinimumGhostscriptVersion() string {
+	return c.minimumGhostscriptVersion
+}
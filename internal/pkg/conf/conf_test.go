@@ -357,6 +357,266 @@ func TestDefaultGoogleChromeRpccBufferSizeFromEnv(t *testing.T) {
 	os.Unsetenv(DefaultGoogleChromeRpccBufferSizeEnvVar)
 }
 
+func TestGoogleChromePoolIdleTimeoutFromEnv(t *testing.T) {
+	var (
+		expected Config
+		result   Config
+		err      error
+	)
+	// GOOGLE_CHROME_POOL_IDLE_TIMEOUT correctly set.
+	os.Setenv(GoogleChromePoolIdleTimeoutEnvVar, "10.0")
+	expected = DefaultConfig()
+	expected.googleChromePoolIdleTimeout = 10.0
+	result, err = FromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(GoogleChromePoolIdleTimeoutEnvVar)
+	// GOOGLE_CHROME_POOL_IDLE_TIMEOUT wrongly set.
+	os.Setenv(GoogleChromePoolIdleTimeoutEnvVar, "foo")
+	expected = DefaultConfig()
+	result, err = FromEnv()
+	test.AssertError(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(GoogleChromePoolIdleTimeoutEnvVar)
+	// GOOGLE_CHROME_POOL_IDLE_TIMEOUT < 0.
+	os.Setenv(GoogleChromePoolIdleTimeoutEnvVar, "-1.0")
+	expected = DefaultConfig()
+	result, err = FromEnv()
+	test.AssertError(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(GoogleChromePoolIdleTimeoutEnvVar)
+}
+
+func TestGoogleChromePoolMaxLifetimeFromEnv(t *testing.T) {
+	var (
+		expected Config
+		result   Config
+		err      error
+	)
+	// GOOGLE_CHROME_POOL_MAX_LIFETIME correctly set.
+	os.Setenv(GoogleChromePoolMaxLifetimeEnvVar, "10.0")
+	expected = DefaultConfig()
+	expected.googleChromePoolMaxLifetime = 10.0
+	result, err = FromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(GoogleChromePoolMaxLifetimeEnvVar)
+	// GOOGLE_CHROME_POOL_MAX_LIFETIME wrongly set.
+	os.Setenv(GoogleChromePoolMaxLifetimeEnvVar, "foo")
+	expected = DefaultConfig()
+	result, err = FromEnv()
+	test.AssertError(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(GoogleChromePoolMaxLifetimeEnvVar)
+	// GOOGLE_CHROME_POOL_MAX_LIFETIME < 0.
+	os.Setenv(GoogleChromePoolMaxLifetimeEnvVar, "-1.0")
+	expected = DefaultConfig()
+	result, err = FromEnv()
+	test.AssertError(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(GoogleChromePoolMaxLifetimeEnvVar)
+}
+
+func TestGoogleChromePoolSizeFromEnv(t *testing.T) {
+	var (
+		expected Config
+		result   Config
+		err      error
+	)
+	// GOOGLE_CHROME_POOL_SIZE correctly set.
+	os.Setenv(GoogleChromePoolSizeEnvVar, "10")
+	expected = DefaultConfig()
+	expected.googleChromePoolSize = 10
+	result, err = FromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(GoogleChromePoolSizeEnvVar)
+	// GOOGLE_CHROME_POOL_SIZE wrongly set.
+	os.Setenv(GoogleChromePoolSizeEnvVar, "foo")
+	expected = DefaultConfig()
+	result, err = FromEnv()
+	test.AssertError(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(GoogleChromePoolSizeEnvVar)
+	// GOOGLE_CHROME_POOL_SIZE < 0.
+	os.Setenv(GoogleChromePoolSizeEnvVar, "-1")
+	expected = DefaultConfig()
+	result, err = FromEnv()
+	test.AssertError(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(GoogleChromePoolSizeEnvVar)
+}
+
+func TestGoogleChromeDebugURLFromEnv(t *testing.T) {
+	var (
+		expected Config
+		result   Config
+		err      error
+	)
+	// GOOGLE_CHROME_DEBUG_URL correctly set.
+	os.Setenv(GoogleChromeDebugURLEnvVar, "http://chrome:9222")
+	expected = DefaultConfig()
+	expected.googleChromeDebugURL = "http://chrome:9222"
+	result, err = FromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(GoogleChromeDebugURLEnvVar)
+}
+
+func TestGoogleChromeStreamThresholdFromEnv(t *testing.T) {
+	var (
+		expected Config
+		result   Config
+		err      error
+	)
+	// GOOGLE_CHROME_STREAM_THRESHOLD correctly set.
+	os.Setenv(GoogleChromeStreamThresholdEnvVar, "10485760")
+	expected = DefaultConfig()
+	expected.googleChromeStreamThreshold = 10485760
+	result, err = FromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(GoogleChromeStreamThresholdEnvVar)
+	// GOOGLE_CHROME_STREAM_THRESHOLD wrongly set.
+	os.Setenv(GoogleChromeStreamThresholdEnvVar, "-1")
+	expected = DefaultConfig()
+	result, err = FromEnv()
+	test.AssertError(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(GoogleChromeStreamThresholdEnvVar)
+}
+
+func TestOutputWriteRateLimitFromEnv(t *testing.T) {
+	var (
+		expected Config
+		result   Config
+		err      error
+	)
+	// OUTPUT_WRITE_RATE_LIMIT correctly set.
+	os.Setenv(OutputWriteRateLimitEnvVar, "1048576")
+	expected = DefaultConfig()
+	expected.outputWriteRateLimit = 1048576
+	result, err = FromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(OutputWriteRateLimitEnvVar)
+	// OUTPUT_WRITE_RATE_LIMIT wrongly set.
+	os.Setenv(OutputWriteRateLimitEnvVar, "-1")
+	expected = DefaultConfig()
+	result, err = FromEnv()
+	test.AssertError(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(OutputWriteRateLimitEnvVar)
+}
+
+func TestMaximumConcurrentProcessesFromEnv(t *testing.T) {
+	var (
+		expected Config
+		result   Config
+		err      error
+	)
+	// MAXIMUM_CONCURRENT_PROCESSES correctly set.
+	os.Setenv(MaximumConcurrentProcessesEnvVar, "4")
+	expected = DefaultConfig()
+	expected.maximumConcurrentProcesses = 4
+	result, err = FromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(MaximumConcurrentProcessesEnvVar)
+	// MAXIMUM_CONCURRENT_PROCESSES wrongly set.
+	os.Setenv(MaximumConcurrentProcessesEnvVar, "foo")
+	expected = DefaultConfig()
+	result, err = FromEnv()
+	test.AssertError(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(MaximumConcurrentProcessesEnvVar)
+	// MAXIMUM_CONCURRENT_PROCESSES < 1.
+	os.Setenv(MaximumConcurrentProcessesEnvVar, "0")
+	expected = DefaultConfig()
+	result, err = FromEnv()
+	test.AssertError(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(MaximumConcurrentProcessesEnvVar)
+}
+
+func TestMaximumConcurrentOfficeProcessesFromEnv(t *testing.T) {
+	var (
+		expected Config
+		result   Config
+		err      error
+	)
+	// MAXIMUM_CONCURRENT_OFFICE_PROCESSES correctly set.
+	os.Setenv(MaximumConcurrentOfficeProcessesEnvVar, "4")
+	expected = DefaultConfig()
+	expected.maximumConcurrentOfficeProcesses = 4
+	result, err = FromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(MaximumConcurrentOfficeProcessesEnvVar)
+	// MAXIMUM_CONCURRENT_OFFICE_PROCESSES wrongly set.
+	os.Setenv(MaximumConcurrentOfficeProcessesEnvVar, "foo")
+	expected = DefaultConfig()
+	result, err = FromEnv()
+	test.AssertError(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(MaximumConcurrentOfficeProcessesEnvVar)
+	// MAXIMUM_CONCURRENT_OFFICE_PROCESSES < 1.
+	os.Setenv(MaximumConcurrentOfficeProcessesEnvVar, "0")
+	expected = DefaultConfig()
+	result, err = FromEnv()
+	test.AssertError(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(MaximumConcurrentOfficeProcessesEnvVar)
+}
+
+func TestMinimumPDFtkVersionFromEnv(t *testing.T) {
+	var (
+		expected Config
+		result   Config
+		err      error
+	)
+	// MINIMUM_PDFTK_VERSION correctly set.
+	os.Setenv(MinimumPDFtkVersionEnvVar, "9.50")
+	expected = DefaultConfig()
+	expected.minimumPDFtkVersion = "9.50"
+	result, err = FromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(MinimumPDFtkVersionEnvVar)
+}
+
+func TestMinimumQPDFVersionFromEnv(t *testing.T) {
+	var (
+		expected Config
+		result   Config
+		err      error
+	)
+	// MINIMUM_QPDF_VERSION correctly set.
+	os.Setenv(MinimumQPDFVersionEnvVar, "9.50")
+	expected = DefaultConfig()
+	expected.minimumQPDFVersion = "9.50"
+	result, err = FromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(MinimumQPDFVersionEnvVar)
+}
+
+func TestMinimumGhostscriptVersionFromEnv(t *testing.T) {
+	var (
+		expected Config
+		result   Config
+		err      error
+	)
+	// MINIMUM_GHOSTSCRIPT_VERSION correctly set.
+	os.Setenv(MinimumGhostscriptVersionEnvVar, "9.50")
+	expected = DefaultConfig()
+	expected.minimumGhostscriptVersion = "9.50"
+	result, err = FromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+	os.Unsetenv(MinimumGhostscriptVersionEnvVar)
+}
+
 func TestGetters(t *testing.T) {
 	result := DefaultConfig()
 	assert.Equal(t, result.maximumWaitTimeout, result.MaximumWaitTimeout())
@@ -370,4 +630,15 @@ func TestGetters(t *testing.T) {
 	assert.Equal(t, result.logLevel, result.LogLevel())
 	assert.Equal(t, result.maximumGoogleChromeRpccBufferSize, result.MaximumGoogleChromeRpccBufferSize())
 	assert.Equal(t, result.defaultGoogleChromeRpccBufferSize, result.DefaultGoogleChromeRpccBufferSize())
+	assert.Equal(t, result.googleChromePoolIdleTimeout, result.GoogleChromePoolIdleTimeout())
+	assert.Equal(t, result.googleChromePoolMaxLifetime, result.GoogleChromePoolMaxLifetime())
+	assert.Equal(t, result.googleChromePoolSize, result.GoogleChromePoolSize())
+	assert.Equal(t, result.googleChromeDebugURL, result.GoogleChromeDebugURL())
+	assert.Equal(t, result.googleChromeStreamThreshold, result.GoogleChromeStreamThreshold())
+	assert.Equal(t, result.outputWriteRateLimit, result.OutputWriteRateLimit())
+	assert.Equal(t, result.maximumConcurrentProcesses, result.MaximumConcurrentProcesses())
+	assert.Equal(t, result.maximumConcurrentOfficeProcesses, result.MaximumConcurrentOfficeProcesses())
+	assert.Equal(t, result.minimumPDFtkVersion, result.MinimumPDFtkVersion())
+	assert.Equal(t, result.minimumQPDFVersion, result.MinimumQPDFVersion())
+	assert.Equal(t, result.minimumGhostscriptVersion, result.MinimumGhostscriptVersion())
 }
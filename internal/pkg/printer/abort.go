@@ -0,0 +1,82 @@
+package printer
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xcontext"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+type conversion struct {
+	cancel  context.CancelFunc
+	aborted int32
+}
+
+type conversionKey struct{}
+
+// nolint: gochecknoglobals
+var conversions = struct {
+	sync.Mutex
+	active map[*conversion]struct{}
+}{active: make(map[*conversion]struct{})}
+
+/*
+withTimeout creates a context.Context which times
+out after given seconds, and tracks it as an active
+conversion until the returned context.CancelFunc is
+called.
+*/
+func withTimeout(logger xlog.Logger, seconds float64) (context.Context, context.CancelFunc) {
+	ctx, cancelTimeout := xcontext.WithTimeout(logger, seconds)
+	ctx, cancel := context.WithCancel(ctx)
+	c := &conversion{cancel: cancel}
+	ctx = context.WithValue(ctx, conversionKey{}, c)
+	conversions.Lock()
+	conversions.active[c] = struct{}{}
+	conversions.Unlock()
+	return ctx, func() {
+		conversions.Lock()
+		delete(conversions.active, c)
+		conversions.Unlock()
+		cancel()
+		cancelTimeout()
+	}
+}
+
+/*
+AbortAll cancels every active conversion. They
+return promptly with a xerror.Error with
+xerror.CanceledCode.
+
+It returns the number of aborted conversions.
+*/
+func AbortAll() int {
+	conversions.Lock()
+	defer conversions.Unlock()
+	for c := range conversions.active {
+		atomic.StoreInt32(&c.aborted, 1)
+		c.cancel()
+	}
+	return len(conversions.active)
+}
+
+/*
+mustHandleError works in the same manner as
+xcontext.MustHandleError, but wraps the previous
+error inside an xerror.Error with xerror.CanceledCode
+if the conversion has been aborted or canceled.
+*/
+func mustHandleError(ctx context.Context, previousErr error) error {
+	const op string = "printer.mustHandleError"
+	c, ok := ctx.Value(conversionKey{}).(*conversion)
+	if ok && atomic.LoadInt32(&c.aborted) == 1 && previousErr != nil {
+		return xerror.Canceled(op, "conversion has been aborted", previousErr)
+	}
+	if ctx.Err() == context.Canceled && previousErr != nil {
+		return xerror.Canceled(op, "conversion has been canceled", previousErr)
+	}
+	return xcontext.MustHandleError(ctx, previousErr)
+}
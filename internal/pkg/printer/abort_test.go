@@ -0,0 +1,39 @@
+package printer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestAbortAll(t *testing.T) {
+	logger := test.DebugLogger()
+	// should abort the active conversions.
+	ctx1, cancel1 := withTimeout(logger, 10)
+	ctx2, cancel2 := withTimeout(logger, 10)
+	assert.Equal(t, 2, AbortAll())
+	assert.Equal(t, context.Canceled, ctx1.Err())
+	assert.Equal(t, context.Canceled, ctx2.Err())
+	err := mustHandleError(ctx1, xerror.New("foo", ctx1.Err()))
+	assert.Equal(t, xerror.CanceledCode, xerror.Code(err))
+	// should not track a conversion anymore
+	// once it has been canceled.
+	cancel2()
+	assert.Equal(t, 1, AbortAll())
+	cancel1()
+	assert.Equal(t, 0, AbortAll())
+	// should not be a canceled error if
+	// the conversion has not been aborted.
+	ctx3, cancel3 := withTimeout(logger, 10)
+	err = mustHandleError(ctx3, xerror.New("foo", errors.New("foo")))
+	assert.Equal(t, xerror.InternalCode, xerror.Code(err))
+	// should be a canceled error if the
+	// conversion has been canceled.
+	cancel3()
+	err = mustHandleError(ctx3, xerror.New("foo", ctx3.Err()))
+	assert.Equal(t, xerror.CanceledCode, xerror.Code(err))
+}
@@ -0,0 +1,142 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+const (
+	// DefaultOddPageBackground is the default
+	// background color of the odd pages.
+	DefaultOddPageBackground string = "#FFFFFF"
+	// DefaultEvenPageBackground is the default
+	// background color of the even pages.
+	DefaultEvenPageBackground string = "#F2F2F2"
+)
+
+// nolint: gochecknoglobals
+var hexColorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+/*
+validateBackgroundColor returns a xerror.Error
+with xerror.InvalidCode if the given color is not
+an hexadecimal color (e.g. "#F2F2F2").
+*/
+func validateBackgroundColor(color string) error {
+	const op string = "printer.validateBackgroundColor"
+	if !hexColorRegexp.MatchString(color) {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("background color should be an hexadecimal color like '#F2F2F2', got '%s'", color),
+			nil,
+		)
+	}
+	return nil
+}
+
+/*
+rgbColor returns the PostScript components, between
+0 and 1, of the given hexadecimal color.
+*/
+func rgbColor(color string) string {
+	components := make([]string, 3)
+	for i := range components {
+		// validateBackgroundColor makes sure it parses.
+		value, _ := strconv.ParseUint(color[1+2*i:3+2*i], 16, 8)
+		components[i] = strconv.FormatFloat(float64(value)/255, 'f', 4, 64)
+	}
+	return strings.Join(components, " ")
+}
+
+/*
+backgroundsPostScript returns a PostScript program
+which draws one page per given MediaBox, filled
+with the odd and even colors alternately.
+*/
+func backgroundsPostScript(boxes []CropRegion, odd, even string) string {
+	var ps strings.Builder
+	for i, box := range boxes {
+		color := odd
+		// pages start at 1.
+		if (i+1)%2 == 0 {
+			color = even
+		}
+		width := formatPDFNumber(box.Right - box.Left)
+		height := formatPDFNumber(box.Top - box.Bottom)
+		fmt.Fprintf(
+			&ps,
+			"<< /PageSize [%s %s] >> setpagedevice %s setrgbcolor 0 0 %s %s rectfill showpage\n",
+			width, height, rgbColor(color), width, height,
+		)
+	}
+	return ps.String()
+}
+
+/*
+alternateBackgrounds puts a background filled with
+the odd or even color under each page of the PDF
+file located at fpath.
+
+Ghostscript draws the backgrounds, one page per page
+of the PDF with the same size, and PDFtk puts them
+under the pages thanks to its multibackground
+operation. The backgrounds only show where the
+pages are transparent.
+*/
+func alternateBackgrounds(ctx context.Context, logger xlog.Logger, fpath, odd, even string) error {
+	const op string = "printer.alternateBackgrounds"
+	logger.DebugfOp(op, "alternating backgrounds '%s' and '%s'...", odd, even)
+	resolver := func() error {
+		if err := validateBackgroundColor(odd); err != nil {
+			return err
+		}
+		if err := validateBackgroundColor(even); err != nil {
+			return err
+		}
+		boxes, err := mediaBoxes(ctx, logger, fpath)
+		if err != nil {
+			return err
+		}
+		dirPath := filepath.Dir(fpath)
+		psPath := fmt.Sprintf("%s/%s.ps", dirPath, xrand.Get())
+		defer os.Remove(psPath) // nolint: errcheck
+		if err := ioutil.WriteFile(psPath, []byte(backgroundsPostScript(boxes, odd, even)), 0644); err != nil {
+			return err
+		}
+		backgroundsPath := fmt.Sprintf("%s/%s.pdf", dirPath, xrand.Get())
+		defer os.Remove(backgroundsPath) // nolint: errcheck
+		err = xexec.Run(
+			ctx,
+			logger,
+			"gs",
+			"-dSAFER",
+			"-dBATCH",
+			"-dNOPAUSE",
+			"-dQUIET",
+			"-sDEVICE=pdfwrite",
+			fmt.Sprintf("-sOutputFile=%s", backgroundsPath),
+			psPath,
+		)
+		if err != nil {
+			return err
+		}
+		return postProcess(fpath, func(tmpDest string) error {
+			return xexec.Run(ctx, logger, "pdftk", fpath, "multibackground", backgroundsPath, "output", tmpDest)
+		})
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
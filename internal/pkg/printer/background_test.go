@@ -0,0 +1,35 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+func TestValidateBackgroundColor(t *testing.T) {
+	// should be OK.
+	assert.Nil(t, validateBackgroundColor(DefaultOddPageBackground))
+	assert.Nil(t, validateBackgroundColor("#f2f2f2"))
+	// should not be OK.
+	for _, color := range []string{"", "grey", "#FFF", "F2F2F2", "#GGGGGG"} {
+		assert.Equal(t, xerror.InvalidCode, xerror.Code(validateBackgroundColor(color)))
+	}
+}
+
+func TestRGBColor(t *testing.T) {
+	assert.Equal(t, "1.0000 1.0000 1.0000", rgbColor("#FFFFFF"))
+	assert.Equal(t, "1.0000 0.0000 0.2000", rgbColor("#ff0033"))
+}
+
+func TestBackgroundsPostScript(t *testing.T) {
+	boxes := []CropRegion{
+		{Page: 1, Left: 0, Bottom: 0, Right: 612, Top: 792},
+		{Page: 2, Left: 0, Bottom: 0, Right: 595.28, Top: 841.89},
+		{Page: 3, Left: 0, Bottom: 0, Right: 612, Top: 792},
+	}
+	expected := "<< /PageSize [612 792] >> setpagedevice 1.0000 1.0000 1.0000 setrgbcolor 0 0 612 792 rectfill showpage\n" +
+		"<< /PageSize [595.28 841.89] >> setpagedevice 0.0000 0.0000 0.0000 setrgbcolor 0 0 595.28 841.89 rectfill showpage\n" +
+		"<< /PageSize [612 792] >> setpagedevice 1.0000 1.0000 1.0000 setrgbcolor 0 0 612 792 rectfill showpage\n"
+	assert.Equal(t, expected, backgroundsPostScript(boxes, "#FFFFFF", "#000000"))
+}
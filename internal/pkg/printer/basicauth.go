@@ -0,0 +1,62 @@
+package printer
+
+import (
+	"net/url"
+	"sync"
+
+	"github.com/mafredri/cdp/protocol/fetch"
+)
+
+const (
+	provideCredentialsAuthResponse string = "ProvideCredentials"
+	cancelAuthResponse             string = "CancelAuth"
+)
+
+/*
+basicAuth answers the HTTP authentication challenges
+of the page with the given credentials, as long as
+they come from the host of the target URL.
+
+Each challenge is answered once: if the credentials
+are wrong, the next challenge of the same request is
+canceled, so that the 401 response is rendered.
+*/
+type basicAuth struct {
+	mu       sync.Mutex
+	host     string
+	username string
+	password string
+	answered map[fetch.RequestID]bool
+}
+
+func newBasicAuth(targetURL, username, password string) *basicAuth {
+	ba := &basicAuth{
+		username: username,
+		password: password,
+		answered: make(map[fetch.RequestID]bool),
+	}
+	if u, err := url.Parse(targetURL); err == nil {
+		ba.host = u.Host
+	}
+	return ba
+}
+
+/*
+response returns the answer to the authentication
+challenge of the given request.
+*/
+func (ba *basicAuth) response(ID fetch.RequestID, requestURL string) fetch.AuthChallengeResponse {
+	ba.mu.Lock()
+	defer ba.mu.Unlock()
+	u, err := url.Parse(requestURL)
+	if err != nil || u.Host != ba.host || ba.answered[ID] {
+		return fetch.AuthChallengeResponse{Response: cancelAuthResponse}
+	}
+	ba.answered[ID] = true
+	username, password := ba.username, ba.password
+	return fetch.AuthChallengeResponse{
+		Response: provideCredentialsAuthResponse,
+		Username: &username,
+		Password: &password,
+	}
+}
@@ -0,0 +1,31 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/mafredri/cdp/protocol/fetch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicAuthResponse(t *testing.T) {
+	ba := &basicAuth{
+		host:     "localhost:3000",
+		username: "foo",
+		password: "bar",
+		answered: make(map[fetch.RequestID]bool),
+	}
+	// should provide the credentials.
+	response := ba.response("1", "http://localhost:3000/index.html")
+	assert.Equal(t, provideCredentialsAuthResponse, response.Response)
+	assert.Equal(t, "foo", *response.Username)
+	assert.Equal(t, "bar", *response.Password)
+	// should cancel as the credentials have
+	// already been provided for this request.
+	response = ba.response("1", "http://localhost:3000/index.html")
+	assert.Equal(t, cancelAuthResponse, response.Response)
+	assert.Nil(t, response.Username)
+	// should cancel as the challenge comes
+	// from another host.
+	response = ba.response("2", "http://example.com/index.html")
+	assert.Equal(t, cancelAuthResponse, response.Response)
+}
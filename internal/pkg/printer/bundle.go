@@ -0,0 +1,263 @@
+package printer
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+const (
+	// bundleMetadataName is the name of the
+	// JSON metadata sidecar in a bundle.
+	bundleMetadataName string = "metadata.json"
+	// bundleConsoleName is the name of the
+	// console messages log in a bundle.
+	bundleConsoleName string = "console.log"
+	// bundleDiagnosticsDir is the directory of
+	// the diagnostics artifacts in a bundle.
+	bundleDiagnosticsDir string = "diagnostics"
+)
+
+/*
+BundleOptions selects the artifacts of a
+conversion a bundle includes, alongside its
+result.
+*/
+type BundleOptions struct {
+	// Metadata adds a JSON sidecar with the size,
+	// the number of pages and the Info dictionary
+	// of the result.
+	Metadata bool
+	// ConsoleMessages, if any, are added as a log
+	// (see ConsoleError).
+	ConsoleMessages []string
+	// Diagnostics adds the artifacts written into
+	// the DiagnosticsDestination of the result, if
+	// any.
+	Diagnostics bool
+}
+
+/*
+BundleMetadata is the JSON metadata sidecar
+of a bundle.
+*/
+type BundleMetadata struct {
+	Filename string            `json:"filename"`
+	Size     int64             `json:"size"`
+	Pages    int               `json:"pages"`
+	Info     map[string]string `json:"info,omitempty"`
+}
+
+/*
+Bundle packages the result of a conversion,
+located at fpath, and the artifacts selected by
+the given options into a zip archive:
+
+	<result filename>
+	metadata.json
+	console.log
+	diagnostics/...
+
+It returns the content of the archive.
+*/
+func Bundle(ctx context.Context, logger xlog.Logger, fpath string, opts BundleOptions) ([]byte, error) {
+	const op string = "printer.Bundle"
+	var buffer bytes.Buffer
+	if err := writeBundle(ctx, logger, &buffer, fpath, opts); err != nil {
+		return nil, xerror.New(op, err)
+	}
+	return buffer.Bytes(), nil
+}
+
+/*
+WriteBundle is like Bundle, but writes the
+archive to the given destination.
+*/
+func WriteBundle(ctx context.Context, logger xlog.Logger, fpath, destination string, opts BundleOptions) error {
+	const op string = "printer.WriteBundle"
+	resolver := func() error {
+		f, err := os.Create(destination)
+		if err != nil {
+			return err
+		}
+		if err := writeBundle(ctx, logger, f, fpath, opts); err != nil {
+			f.Close() // nolint: errcheck
+			// do not leave a partial archive behind.
+			os.Remove(destination) // nolint: errcheck
+			return err
+		}
+		return f.Close()
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+func writeBundle(ctx context.Context, logger xlog.Logger, w io.Writer, fpath string, opts BundleOptions) error {
+	const op string = "printer.writeBundle"
+	resolver := func() error {
+		archive := zip.NewWriter(w)
+		if err := addFileToBundle(archive, filepath.Base(fpath), fpath); err != nil {
+			return err
+		}
+		if opts.Metadata {
+			metadata, err := bundleMetadata(ctx, logger, fpath)
+			if err != nil {
+				return err
+			}
+			data, err := json.MarshalIndent(metadata, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := addToBundle(archive, bundleMetadataName, data); err != nil {
+				return err
+			}
+		}
+		if len(opts.ConsoleMessages) > 0 {
+			data := []byte(strings.Join(opts.ConsoleMessages, "\n"))
+			if err := addToBundle(archive, bundleConsoleName, data); err != nil {
+				return err
+			}
+		}
+		if opts.Diagnostics {
+			if err := addDiagnosticsToBundle(logger, archive, DiagnosticsDestination(fpath)); err != nil {
+				return err
+			}
+		}
+		return archive.Close()
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+func addToBundle(archive *zip.Writer, name string, data []byte) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func addFileToBundle(archive *zip.Writer, name, fpath string) error {
+	f, err := os.Open(fpath)
+	if err != nil {
+		return err
+	}
+	defer f.Close() // nolint: errcheck
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+/*
+addDiagnosticsToBundle adds the files of the
+given diagnostics directory. As diagnostics are
+only written if a conversion fails, a missing
+directory is not an error.
+*/
+func addDiagnosticsToBundle(logger xlog.Logger, archive *zip.Writer, dirPath string) error {
+	const op string = "printer.addDiagnosticsToBundle"
+	files, err := ioutil.ReadDir(dirPath)
+	if os.IsNotExist(err) {
+		logger.DebugfOp(op, "no diagnostics in '%s', skipping", dirPath)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		name := filepath.ToSlash(filepath.Join(bundleDiagnosticsDir, file.Name()))
+		if err := addFileToBundle(archive, name, filepath.Join(dirPath, file.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+bundleMetadata returns the metadata of the PDF
+file located at fpath thanks to pdfinfo.
+*/
+func bundleMetadata(ctx context.Context, logger xlog.Logger, fpath string) (BundleMetadata, error) {
+	const op string = "printer.bundleMetadata"
+	resolver := func() (BundleMetadata, error) {
+		stat, err := os.Stat(fpath)
+		if err != nil {
+			return BundleMetadata{}, err
+		}
+		// same as pageCount, but the whole
+		// output is kept.
+		cmd := exec.CommandContext(ctx, "pdfinfo", fpath)
+		xexec.LogBeforeExecute(logger, cmd)
+		out, err := cmd.Output()
+		if err != nil {
+			return BundleMetadata{}, err
+		}
+		pages, info, err := parsePDFInfo(out)
+		if err != nil {
+			return BundleMetadata{}, err
+		}
+		return BundleMetadata{
+			Filename: filepath.Base(fpath),
+			Size:     stat.Size(),
+			Pages:    pages,
+			Info:     info,
+		}, nil
+	}
+	result, err := resolver()
+	if err != nil {
+		return BundleMetadata{}, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+/*
+parsePDFInfo returns the number of pages and the
+Info dictionary entries of a pdfinfo output.
+*/
+func parsePDFInfo(out []byte) (int, map[string]string, error) {
+	const op string = "printer.parsePDFInfo"
+	info := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		i := strings.Index(line, ":")
+		if i <= 0 {
+			continue
+		}
+		info[line[:i]] = strings.TrimSpace(line[i+1:])
+	}
+	pages, ok := info["Pages"]
+	if !ok {
+		return 0, nil, xerror.Invalid(op, "unable to find the number of pages", nil)
+	}
+	delete(info, "Pages")
+	count, err := strconv.Atoi(pages)
+	if err != nil {
+		return 0, nil, xerror.New(op, err)
+	}
+	return count, info, nil
+}
@@ -0,0 +1,87 @@
+package printer
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestBundle(t *testing.T) {
+	logger := test.DebugLogger()
+	fpath := test.GenerateDestination()
+	assert.Nil(t, ioutil.WriteFile(fpath, []byte("foo"), 0644))
+	defer os.Remove(fpath) // nolint: errcheck
+	bundleFiles := func(data []byte) map[string]string {
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		assert.Nil(t, err)
+		files := make(map[string]string)
+		for _, file := range archive.File {
+			r, err := file.Open()
+			assert.Nil(t, err)
+			content, err := ioutil.ReadAll(r)
+			assert.Nil(t, err)
+			r.Close() // nolint: errcheck
+			files[file.Name] = string(content)
+		}
+		return files
+	}
+	// only the result.
+	data, err := Bundle(context.Background(), logger, fpath, BundleOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{filepath.Base(fpath): "foo"}, bundleFiles(data))
+	// with console messages and missing diagnostics.
+	opts := BundleOptions{
+		ConsoleMessages: []string{"[log] foo", "[error] bar"},
+		Diagnostics:     true,
+	}
+	data, err = Bundle(context.Background(), logger, fpath, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		filepath.Base(fpath): "foo",
+		"console.log":        "[log] foo\n[error] bar",
+	}, bundleFiles(data))
+	// with diagnostics.
+	dirPath := DiagnosticsDestination(fpath)
+	assert.Nil(t, os.MkdirAll(dirPath, 0755))
+	defer os.RemoveAll(dirPath) // nolint: errcheck
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dirPath, "url.txt"), []byte("http://foo"), 0644))
+	destination := test.GenerateDestination()
+	defer os.Remove(destination) // nolint: errcheck
+	err = WriteBundle(context.Background(), logger, fpath, destination, BundleOptions{Diagnostics: true})
+	assert.Nil(t, err)
+	data, err = ioutil.ReadFile(destination)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		filepath.Base(fpath):  "foo",
+		"diagnostics/url.txt": "http://foo",
+	}, bundleFiles(data))
+	// the result does not exist: the
+	// destination should not be written.
+	err = WriteBundle(context.Background(), logger, "/foo.pdf", destination, BundleOptions{})
+	test.AssertError(t, err)
+	_, err = os.Stat(destination)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestParsePDFInfo(t *testing.T) {
+	out := []byte("Title:          foo\nProducer:       bar: baz\nPages:          3\nEncrypted:      no\n")
+	pages, info, err := parsePDFInfo(out)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, map[string]string{
+		"Title":     "foo",
+		"Producer":  "bar: baz",
+		"Encrypted": "no",
+	}, info)
+	_, _, err = parsePDFInfo([]byte("Title: foo\n"))
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
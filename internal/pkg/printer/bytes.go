@@ -0,0 +1,169 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+/*
+BytesPrinter is a Printer which may also return
+its result as bytes, without writing it to disk.
+*/
+type BytesPrinter interface {
+	Printer
+	PrintBytes() ([]byte, error)
+}
+
+/*
+PrintBytes returns the result of the given
+Printer as bytes. If the Printer is not a
+BytesPrinter, it prints into a temporary file
+which is read back then removed.
+*/
+func PrintBytes(p Printer) ([]byte, error) {
+	const op string = "printer.PrintBytes"
+	if bp, ok := p.(BytesPrinter); ok {
+		data, err := bp.PrintBytes()
+		if err != nil {
+			return nil, xerror.New(op, err)
+		}
+		return data, nil
+	}
+	data, err := printBytesThroughFile(p)
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	return data, nil
+}
+
+/*
+printBytesThroughFile prints into a temporary
+file of the system temporary directory, then
+reads it back and removes it.
+*/
+func printBytesThroughFile(p Printer) ([]byte, error) {
+	const op string = "printer.printBytesThroughFile"
+	dirPath := fmt.Sprintf("%s/%s", os.TempDir(), xrand.Get())
+	// some Printers write artifacts alongside
+	// the destination.
+	defer os.RemoveAll(dirPath) // nolint: errcheck
+	resolver := func() ([]byte, error) {
+		if err := os.MkdirAll(dirPath, 0755); err != nil {
+			return nil, err
+		}
+		destination := fmt.Sprintf("%s/result.pdf", dirPath)
+		if err := p.Print(destination); err != nil {
+			return nil, err
+		}
+		return ioutil.ReadFile(destination)
+	}
+	data, err := resolver()
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	return data, nil
+}
+
+/*
+memoryUploader is an Uploader which keeps
+the uploaded objects in memory.
+*/
+type memoryUploader struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemoryUploader() *memoryUploader {
+	return &memoryUploader{objects: make(map[string][]byte)}
+}
+
+func (u *memoryUploader) Upload(ctx context.Context, name string, r io.Reader) error {
+	const op string = "printer.memoryUploader.Upload"
+	resolver := func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var buffer bytes.Buffer
+		if _, err := io.Copy(&buffer, r); err != nil {
+			return err
+		}
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		u.objects[name] = buffer.Bytes()
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+PrintBytes returns the PDF Google Chrome prints
+as is, thanks to an in-memory Uploader.
+
+As post-processing requires a local file, if
+the options cannot be applied while uploading
+the result, the PDF is printed into a temporary
+file instead.
+*/
+func (p chromePrinter) PrintBytes() ([]byte, error) {
+	const (
+		op          string = "printer.chromePrinter.PrintBytes"
+		destination string = "result.pdf"
+	)
+	if p.opts.Uploader != nil {
+		return nil, xerror.Invalid(op, "the result cannot be returned as bytes when uploading it", nil)
+	}
+	resolver := func() ([]byte, error) {
+		uploader := newMemoryUploader()
+		direct := p
+		direct.opts.Uploader = uploader
+		if err := direct.opts.validate(); err != nil {
+			p.logger.DebugfOp(op, "options require a local file, printing into a temporary file...")
+			return printBytesThroughFile(p)
+		}
+		if err := direct.Print(destination); err != nil {
+			return nil, err
+		}
+		data, ok := uploader.objects[destination]
+		if !ok {
+			return nil, xerror.Internal(op, "no result has been uploaded", nil)
+		}
+		return data, nil
+	}
+	data, err := resolver()
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	return data, nil
+}
+
+/*
+PrintBytes merges the PDF files into a
+temporary file, as PDFtk and the post-processing
+tools write into files.
+*/
+func (p mergePrinter) PrintBytes() ([]byte, error) {
+	const op string = "printer.mergePrinter.PrintBytes"
+	data, err := printBytesThroughFile(p)
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	return data, nil
+}
+
+// Compile-time checks to ensure type implements desired interfaces.
+var (
+	_ = Uploader(new(memoryUploader))
+	_ = BytesPrinter(new(chromePrinter))
+	_ = BytesPrinter(new(mergePrinter))
+)
@@ -0,0 +1,47 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestPrintBytes(t *testing.T) {
+	// the Printer is not a BytesPrinter.
+	data, err := PrintBytes(fakePrinter{})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("foo"), data)
+	// the Printer fails.
+	_, err = PrintBytes(flakyPrinter{attempts: new(int), failures: 1, err: xerror.Internal("foo", "bar", nil)})
+	test.AssertError(t, err)
+}
+
+func TestMemoryUploader(t *testing.T) {
+	u := newMemoryUploader()
+	err := u.Upload(context.Background(), "foo.pdf", bytes.NewReader([]byte("foo")))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("foo"), u.objects["foo.pdf"])
+	// should not upload if the context.Context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = u.Upload(ctx, "bar.pdf", bytes.NewReader([]byte("bar")))
+	test.AssertError(t, err)
+	assert.NotContains(t, u.objects, "bar.pdf")
+}
+
+func TestChromePrinterPrintBytes(t *testing.T) {
+	config := conf.DefaultConfig()
+	opts := DefaultChromePrinterOptions(config)
+	opts.Uploader = NewDirectoryUploader("/tmp")
+	p := NewHTMLPrinter(test.DebugLogger(), "/foo.html", opts).(BytesPrinter)
+	// should not be OK as the result
+	// is uploaded.
+	_, err := p.PrintBytes()
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
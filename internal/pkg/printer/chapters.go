@@ -0,0 +1,295 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+type chaptersPrinter struct {
+	logger xlog.Logger
+	fpaths []string
+	titles []string
+	opts   ChromePrinterOptions
+}
+
+// bookmark is an entry of the outline
+// of a PDF file, 1 being the top level.
+type bookmark struct {
+	Title      string
+	Level      int
+	PageNumber int
+}
+
+/*
+NewChaptersPrinter returns a Printer which
+converts each of the given HTML files to PDF
+thanks to Google Chrome and merges them into
+a single PDF file.
+
+Each HTML file becomes a chapter with a
+top-level bookmark named after the title at
+the same position, which points to its first
+page. The outline of a chapter (if any) is kept
+below its bookmark.
+
+The WaitTimeout option applies to the whole
+conversion.
+*/
+func NewChaptersPrinter(logger xlog.Logger, fpaths, titles []string, opts ChromePrinterOptions) Printer {
+	return chaptersPrinter{
+		logger: requestLogger(logger, opts.RequestID),
+		fpaths: fpaths,
+		titles: titles,
+		opts:   opts,
+	}
+}
+
+func (p chaptersPrinter) Print(destination string) error {
+	const op string = "printer.chaptersPrinter.Print"
+	logOptions(p.logger, p.opts)
+	ctx, cancel := withTimeout(p.logger, p.opts.WaitTimeout)
+	defer cancel()
+	resolver := func() error {
+		if err := validateChapters(p.fpaths, p.titles); err != nil {
+			return err
+		}
+		// the chapters have to be merged locally.
+		if p.opts.Uploader != nil {
+			return xerror.Invalid(op, "chapters cannot be uploaded", nil)
+		}
+		dirPath := filepath.Dir(destination)
+		baseFilename := xrand.Get()
+		chapters := make([]string, len(p.fpaths))
+		pageCounts := make([]int, len(p.fpaths))
+		outlines := make([][]bookmark, len(p.fpaths))
+		for i, fpath := range p.fpaths {
+			chapters[i] = fmt.Sprintf("%s/%d%s.pdf", dirPath, i, baseFilename)
+			defer os.Remove(chapters[i]) // nolint: errcheck
+			p.logger.DebugfOp(op, "rendering chapter '%s'...", p.titles[i])
+			opts := p.opts
+			// each chapter shares what remains
+			// of the timeout.
+			if deadline, ok := ctx.Deadline(); ok {
+				opts.WaitTimeout = time.Until(deadline).Seconds() - opts.WaitDelay
+			}
+			chapter := chromePrinter{
+				logger: p.logger,
+				url:    fmt.Sprintf("file://%s", fpath),
+				opts:   opts,
+			}
+			if err := chapter.Print(chapters[i]); err != nil {
+				return err
+			}
+			count, err := pageCount(ctx, p.logger, chapters[i])
+			if err != nil {
+				return err
+			}
+			pageCounts[i] = count
+			// PDFtk does not keep it while merging.
+			outline, err := readBookmarks(ctx, p.logger, chapters[i])
+			if err != nil {
+				return err
+			}
+			outlines[i] = outline
+		}
+		args := append(append([]string{}, chapters...), "cat", "output", destination)
+		if err := xexec.Run(ctx, p.logger, "pdftk", args...); err != nil {
+			return err
+		}
+		return addBookmarks(ctx, p.logger, destination, chapterBookmarks(p.titles, pageCounts, outlines))
+	}
+	if err := resolver(); err != nil {
+		return mustHandleError(
+			ctx,
+			xerror.New(requestOp(op, p.opts.RequestID), err),
+		)
+	}
+	return nil
+}
+
+/*
+validateChapters returns a xerror.Error with
+xerror.InvalidCode if there is not exactly one
+title per chapter, or if a title is empty or
+spans several lines.
+*/
+func validateChapters(fpaths, titles []string) error {
+	const op string = "printer.validateChapters"
+	if len(fpaths) == 0 {
+		return xerror.Invalid(op, "at least one chapter is required", nil)
+	}
+	if len(titles) != len(fpaths) {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("got '%d' title(s) for '%d' chapter(s)", len(titles), len(fpaths)),
+			nil,
+		)
+	}
+	for _, title := range titles {
+		if strings.TrimSpace(title) == "" {
+			return xerror.Invalid(op, "chapter titles should not be empty", nil)
+		}
+		if strings.ContainsAny(title, "\r\n") {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("chapter title '%s' should not contain line breaks", title),
+				nil,
+			)
+		}
+	}
+	return nil
+}
+
+/*
+chapterBookmarks returns the bookmarks pointing
+to the first page of each chapter, given their
+number of pages, each followed by the outline of
+the chapter one level below.
+*/
+func chapterBookmarks(titles []string, pageCounts []int, outlines [][]bookmark) []bookmark {
+	var bookmarks []bookmark
+	startPage := 1
+	for i, title := range titles {
+		bookmarks = append(bookmarks, bookmark{
+			Title:      title,
+			Level:      1,
+			PageNumber: startPage,
+		})
+		for _, b := range outlines[i] {
+			bookmarks = append(bookmarks, bookmark{
+				Title:      b.Title,
+				Level:      b.Level + 1,
+				PageNumber: b.PageNumber + startPage - 1,
+			})
+		}
+		startPage += pageCounts[i]
+	}
+	return bookmarks
+}
+
+// bookmarksData returns the PDFtk info
+// data describing the given bookmarks.
+func bookmarksData(bookmarks []bookmark) string {
+	var data strings.Builder
+	for _, b := range bookmarks {
+		fmt.Fprintf(
+			&data,
+			"BookmarkBegin\nBookmarkTitle: %s\nBookmarkLevel: %d\nBookmarkPageNumber: %d\n",
+			pdftkString(b.Title),
+			b.Level,
+			b.PageNumber,
+		)
+	}
+	return data.String()
+}
+
+/*
+pdftkString encodes the given text as PDFtk
+expects it in info data, i.e. with the XML
+special characters and the non-ASCII ones as
+entities (e.g. "&amp;" or "&#233;").
+*/
+func pdftkString(text string) string {
+	var encoded strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '&':
+			encoded.WriteString("&amp;")
+		case r == '<':
+			encoded.WriteString("&lt;")
+		case r == '>':
+			encoded.WriteString("&gt;")
+		case r > unicode.MaxASCII:
+			fmt.Fprintf(&encoded, "&#%d;", r)
+		default:
+			encoded.WriteRune(r)
+		}
+	}
+	return encoded.String()
+}
+
+/*
+readBookmarks returns the outline of the PDF
+file located at fpath thanks to PDFtk.
+*/
+func readBookmarks(ctx context.Context, logger xlog.Logger, fpath string) ([]bookmark, error) {
+	const op string = "printer.readBookmarks"
+	resolver := func() ([]bookmark, error) {
+		cmd := exec.CommandContext(ctx, "pdftk", fpath, "dump_data_utf8")
+		xexec.LogBeforeExecute(logger, cmd)
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, err
+		}
+		return parseBookmarks(string(out))
+	}
+	result, err := resolver()
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+/*
+parseBookmarks returns the bookmarks described
+by the given PDFtk info data, with their titles
+decoded.
+*/
+func parseBookmarks(data string) ([]bookmark, error) {
+	const op string = "printer.parseBookmarks"
+	var bookmarks []bookmark
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "BookmarkBegin" {
+			bookmarks = append(bookmarks, bookmark{})
+			continue
+		}
+		if len(bookmarks) == 0 {
+			continue
+		}
+		b := &bookmarks[len(bookmarks)-1]
+		key, value := line, ""
+		if i := strings.Index(line, ": "); i >= 0 {
+			key, value = line[:i], line[i+len(": "):]
+		}
+		var err error
+		switch key {
+		case "BookmarkTitle":
+			b.Title = html.UnescapeString(value)
+		case "BookmarkLevel":
+			b.Level, err = strconv.Atoi(value)
+		case "BookmarkPageNumber":
+			b.PageNumber, err = strconv.Atoi(value)
+		}
+		if err != nil {
+			return nil, xerror.New(op, err)
+		}
+	}
+	return bookmarks, nil
+}
+
+/*
+addBookmarks adds the given top-level bookmarks
+to the PDF file located at fpath thanks to PDFtk.
+*/
+func addBookmarks(ctx context.Context, logger xlog.Logger, fpath string, bookmarks []bookmark) error {
+	const op string = "printer.addBookmarks"
+	logger.DebugfOp(op, "adding '%d' bookmark(s)...", len(bookmarks))
+	if err := updateInfo(ctx, logger, fpath, bookmarksData(bookmarks)); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
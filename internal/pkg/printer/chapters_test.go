@@ -0,0 +1,113 @@
+package printer
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestChaptersPrinter(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+		fpath  string      = test.HTMLFpaths(t)[0]
+		opts   ChromePrinterOptions
+		dest   string
+		p      Printer
+		err    error
+	)
+	// default options.
+	opts = DefaultChromePrinterOptions(config)
+	p = NewChaptersPrinter(logger, []string{fpath, fpath}, []string{"Introduction", "Conclusion"}, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// titles with special and non-ASCII characters.
+	opts = DefaultChromePrinterOptions(config)
+	p = NewChaptersPrinter(logger, []string{fpath, fpath}, []string{"R&D <2019>", "Été"}, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	data, err := exec.Command("pdftk", dest, "dump_data").Output()
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "BookmarkTitle: R&amp;D &lt;2019&gt;\nBookmarkLevel: 1\nBookmarkPageNumber: 1\n")
+	assert.Contains(t, string(data), "BookmarkTitle: &#201;t&#233;\nBookmarkLevel: 1\n")
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as a title is missing.
+	opts = DefaultChromePrinterOptions(config)
+	p = NewChaptersPrinter(logger, []string{fpath, fpath}, []string{"Introduction"}, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestValidateChapters(t *testing.T) {
+	fpaths := []string{"a.html", "b.html"}
+	assert.Nil(t, validateChapters(fpaths, []string{"A", "B"}))
+	// should not be OK as the chapters
+	// and their titles do not match.
+	for _, titles := range [][]string{nil, {"A"}, {"A", "B", "C"}, {"A", " "}, {"A", "B\nC"}} {
+		err := validateChapters(fpaths, titles)
+		test.AssertError(t, err)
+		assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	}
+	// should not be OK as there
+	// are no chapters.
+	err := validateChapters(nil, nil)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestChapterBookmarks(t *testing.T) {
+	outlines := [][]bookmark{nil, {{Title: "B.1", Level: 1, PageNumber: 1}, {Title: "B.1.1", Level: 2, PageNumber: 2}}, nil}
+	bookmarks := chapterBookmarks([]string{"A", "B", "C"}, []int{2, 3, 1}, outlines)
+	assert.Equal(t, []bookmark{
+		{Title: "A", Level: 1, PageNumber: 1},
+		{Title: "B", Level: 1, PageNumber: 3},
+		{Title: "B.1", Level: 2, PageNumber: 3},
+		{Title: "B.1.1", Level: 3, PageNumber: 4},
+		{Title: "C", Level: 1, PageNumber: 6},
+	}, bookmarks)
+	assert.Equal(
+		t,
+		"BookmarkBegin\nBookmarkTitle: A\nBookmarkLevel: 1\nBookmarkPageNumber: 1\n"+
+			"BookmarkBegin\nBookmarkTitle: B\nBookmarkLevel: 1\nBookmarkPageNumber: 3\n",
+		bookmarksData(bookmarks[:2]),
+	)
+	// should encode the titles.
+	assert.Equal(
+		t,
+		"BookmarkBegin\nBookmarkTitle: R&amp;D &lt;2019&gt; &#233;t&#233;\nBookmarkLevel: 1\nBookmarkPageNumber: 1\n",
+		bookmarksData([]bookmark{{Title: "R&D <2019> été", Level: 1, PageNumber: 1}}),
+	)
+}
+
+func TestParseBookmarks(t *testing.T) {
+	data := "InfoBegin\nInfoKey: Title\nInfoValue: Foo\nNumberOfPages: 3\n" +
+		"BookmarkBegin\nBookmarkTitle: R&amp;D &#233;t&#233;\nBookmarkLevel: 1\nBookmarkPageNumber: 1\n" +
+		"BookmarkBegin\nBookmarkTitle: Foo: bar\nBookmarkLevel: 2\nBookmarkPageNumber: 3\n" +
+		"PageMediaBegin\nPageMediaNumber: 1\n"
+	bookmarks, err := parseBookmarks(data)
+	assert.Nil(t, err)
+	assert.Equal(t, []bookmark{
+		{Title: "R&D été", Level: 1, PageNumber: 1},
+		{Title: "Foo: bar", Level: 2, PageNumber: 3},
+	}, bookmarks)
+	// no outline.
+	bookmarks, err = parseBookmarks("InfoBegin\nInfoKey: Title\nInfoValue: Foo\n")
+	assert.Nil(t, err)
+	assert.Empty(t, bookmarks)
+	// should not be OK as a level is invalid.
+	_, err = parseBookmarks("BookmarkBegin\nBookmarkLevel: foo\n")
+	test.AssertError(t, err)
+}
@@ -1,22 +1,27 @@
 package printer
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/mafredri/cdp"
-	"github.com/mafredri/cdp/devtool"
+	"github.com/mafredri/cdp/protocol/emulation"
 	"github.com/mafredri/cdp/protocol/network"
 	"github.com/mafredri/cdp/protocol/page"
+	"github.com/mafredri/cdp/protocol/runtime"
 	"github.com/mafredri/cdp/protocol/target"
-	"github.com/mafredri/cdp/rpcc"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
-	"github.com/thecodingmachine/gotenberg/internal/pkg/xcontext"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xassert"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xtime"
 	"golang.org/x/sync/errgroup"
 )
@@ -24,44 +29,767 @@ import (
 type chromePrinter struct {
 	logger xlog.Logger
 	url    string
-	opts   ChromePrinterOptions
+	// baseURL, if any, is the URL of a page
+	// loaded from a data URL (see pageURL).
+	baseURL           string
+	opts              ChromePrinterOptions
+	deviceScaleFactor float64
 }
 
-// ChromePrinterOptions helps customizing the
-// Google Chrome Printer behaviour.
+/*
+pageURL returns the URL the page is considered
+to be loaded from, i.e. its base URL (if any)
+rather than the data URL it navigates to.
+*/
+func (p chromePrinter) pageURL() string {
+	if p.baseURL != "" {
+		return p.baseURL
+	}
+	return p.url
+}
+
+/*
+ChromePrinterOptions helps customizing the
+Google Chrome Printer behaviour.
+
+The WaitForSubsequentNavigation option waits for
+the main frame to navigate a second time once
+loaded, e.g. because of a client-side redirect,
+for at most SubsequentNavigationTimeout seconds:
+a later redirect is not awaited, and the page is
+printed as is. The whole wait remains bounded by
+the WaitTimeout option. Server-side redirects are
+followed by Google Chrome during the first
+navigation and do not count, nor does a chain of
+client-side redirects beyond the second
+navigation.
+*/
 type ChromePrinterOptions struct {
-	WaitTimeout    float64
-	WaitDelay      float64
-	HeaderHTML     string
-	FooterHTML     string
-	PaperWidth     float64
-	PaperHeight    float64
-	MarginTop      float64
-	MarginBottom   float64
-	MarginLeft     float64
-	MarginRight    float64
-	Landscape      bool
-	RpccBufferSize int64
+	WaitTimeout                 float64
+	WaitDelay                   float64
+	HeaderHTML                  string
+	FooterHTML                  string
+	PaperWidth                  float64
+	PaperHeight                 float64
+	MarginTop                   float64
+	MarginBottom                float64
+	MarginLeft                  float64
+	MarginRight                 float64
+	Landscape                   bool
+	RpccBufferSize              int64
+	WaitForSelector             string
+	PollInterval                float64
+	ICCProfilePath              string
+	FailOnHTTPError             bool
+	AcceptableStatusCodes       []int64
+	ExactColors                 bool
+	RequiredFonts               []string
+	DefaultFooterElements       []string
+	Uploader                    Uploader
+	AutoLandscape               bool
+	PoolIdleTimeout             float64
+	PoolMaxLifetime             float64
+	PoolSize                    int64
+	Thumbnail                   bool
+	ThumbnailFormat             string
+	ThumbnailWidth              int64
+	RequestID                   string
+	MaxDOMNodes                 int64
+	WaitForReadyStateComplete   bool
+	WaitForFrameCount           int64
+	DocumentLanguage            string
+	HeaderFontSize              float64
+	FooterFontSize              float64
+	CPUThrottlingRate           float64
+	DiagnosticsOnError          bool
+	FailOnSubresourceError      bool
+	SubresourceErrorTypes       []string
+	PDFX                        string
+	MinRenderTime               float64
+	AcceptConsentSelectors      []string
+	SubsetFonts                 bool
+	AcceptEncoding              string
+	PrintAreaSelector           string
+	MaxOutputBytes              int64
+	ReaderMode                  bool
+	QRCode                      bool
+	QRCodeContent               string
+	QRCodePosition              string
+	QRCodeSize                  float64
+	DebugURL                    string
+	PageRanges                  string
+	BypassCSP                   bool
+	Grayscale                   bool
+	Scale                       float64
+	PreferCSSPageSize           bool
+	SlowResources               int64
+	WaitForSubsequentNavigation bool
+	SubsequentNavigationTimeout float64
+	WaitForNetworkIdle          bool
+	NetworkIdleDuration         float64
+	EmbedSourceHTML             bool
+	WaitForExpression           string
+	ExtraHTTPHeaders            map[string]string
+	ContentWidthPx              int64
+	Cookies                     []Cookie
+	Username                    string
+	Password                    string
+	WaitForText                 string
+	ViewportWidth               int64
+	ViewportHeight              int64
+	DeviceScaleFactor           float64
+	EmulatedMediaType           string
+	StreamThresholdBytes        int64
+	CaptureConsole              bool
+	WriteRateLimit              int64
+	BlockedResourceTypes        []string
+	CustomCSS                   string
+	ForcePageCount              int64
+	DisableThirdPartyJS         bool
+	CustomJavaScript            string
+	Title                       string
+	Author                      string
+	Subject                     string
+	Keywords                    string
+	DisableJavaScript           bool
+	Timezone                    string
+	Locale                      string
+}
+
+const (
+	// DateFooterElement displays the
+	// formatted print date.
+	DateFooterElement string = "date"
+	// TitleFooterElement displays the
+	// document title.
+	TitleFooterElement string = "title"
+	// URLFooterElement displays the
+	// document location.
+	URLFooterElement string = "url"
+	// PageNumberFooterElement displays the
+	// current page number.
+	PageNumberFooterElement string = "pageNumber"
+)
+
+// FooterElements returns a slice containing
+// all available default footer elements.
+func FooterElements() []string {
+	return []string{
+		DateFooterElement,
+		TitleFooterElement,
+		URLFooterElement,
+		PageNumberFooterElement,
+	}
+}
+
+/*
+SubresourceTypes returns a slice containing all
+resource types the subresource errors may be
+filtered by.
+*/
+func SubresourceTypes() []string {
+	return []string{
+		string(network.ResourceTypeDocument),
+		string(network.ResourceTypeStylesheet),
+		string(network.ResourceTypeImage),
+		string(network.ResourceTypeMedia),
+		string(network.ResourceTypeFont),
+		string(network.ResourceTypeScript),
+		string(network.ResourceTypeTextTrack),
+		string(network.ResourceTypeXHR),
+		string(network.ResourceTypeFetch),
+		string(network.ResourceTypeEventSource),
+		string(network.ResourceTypeWebSocket),
+		string(network.ResourceTypeManifest),
+		string(network.ResourceTypeSignedExchange),
+		string(network.ResourceTypePing),
+		string(network.ResourceTypeCSPViolationReport),
+		string(network.ResourceTypeOther),
+	}
 }
 
+const defaultHeaderFooterHTML string = "<html><head></head><body></body></html>"
+
 // DefaultChromePrinterOptions returns the default
 // Google Chrome Printer options.
 func DefaultChromePrinterOptions(config conf.Config) ChromePrinterOptions {
-	const defaultHeaderFooterHTML string = "<html><head></head><body></body></html>"
 	return ChromePrinterOptions{
-		WaitTimeout:    config.DefaultWaitTimeout(),
-		WaitDelay:      0.0,
-		HeaderHTML:     defaultHeaderFooterHTML,
-		FooterHTML:     defaultHeaderFooterHTML,
-		PaperWidth:     8.27,
-		PaperHeight:    11.7,
-		MarginTop:      1.0,
-		MarginBottom:   1.0,
-		MarginLeft:     1.0,
-		MarginRight:    1.0,
-		Landscape:      false,
-		RpccBufferSize: config.DefaultGoogleChromeRpccBufferSize(),
+		WaitTimeout:                 config.DefaultWaitTimeout(),
+		WaitDelay:                   0.0,
+		HeaderHTML:                  defaultHeaderFooterHTML,
+		FooterHTML:                  defaultHeaderFooterHTML,
+		PaperWidth:                  8.27,
+		PaperHeight:                 11.7,
+		MarginTop:                   1.0,
+		MarginBottom:                1.0,
+		MarginLeft:                  1.0,
+		MarginRight:                 1.0,
+		Landscape:                   false,
+		RpccBufferSize:              config.DefaultGoogleChromeRpccBufferSize(),
+		WaitForSelector:             "",
+		PollInterval:                0.1,
+		ICCProfilePath:              "",
+		FailOnHTTPError:             false,
+		AcceptableStatusCodes:       nil,
+		ExactColors:                 true,
+		RequiredFonts:               nil,
+		DefaultFooterElements:       nil,
+		Uploader:                    nil,
+		AutoLandscape:               false,
+		PoolIdleTimeout:             config.GoogleChromePoolIdleTimeout(),
+		PoolMaxLifetime:             config.GoogleChromePoolMaxLifetime(),
+		PoolSize:                    config.GoogleChromePoolSize(),
+		Thumbnail:                   false,
+		ThumbnailFormat:             PNGScreenshotFormat,
+		ThumbnailWidth:              256,
+		RequestID:                   "",
+		MaxDOMNodes:                 0,
+		WaitForReadyStateComplete:   false,
+		WaitForFrameCount:           0,
+		DocumentLanguage:            "",
+		HeaderFontSize:              0.0,
+		FooterFontSize:              0.0,
+		CPUThrottlingRate:           1.0,
+		DiagnosticsOnError:          false,
+		FailOnSubresourceError:      false,
+		SubresourceErrorTypes:       nil,
+		PDFX:                        "",
+		MinRenderTime:               0.0,
+		AcceptConsentSelectors:      nil,
+		SubsetFonts:                 true,
+		AcceptEncoding:              "",
+		PrintAreaSelector:           "",
+		MaxOutputBytes:              0,
+		ReaderMode:                  false,
+		QRCode:                      false,
+		QRCodeContent:               "",
+		QRCodePosition:              BottomRightQRCodePosition,
+		QRCodeSize:                  0.6,
+		DebugURL:                    config.GoogleChromeDebugURL(),
+		PageRanges:                  "",
+		BypassCSP:                   false,
+		Grayscale:                   false,
+		Scale:                       0.0,
+		PreferCSSPageSize:           false,
+		SlowResources:               0,
+		WaitForSubsequentNavigation: false,
+		SubsequentNavigationTimeout: 2.0,
+		WaitForNetworkIdle:          false,
+		NetworkIdleDuration:         0.5,
+		EmbedSourceHTML:             false,
+		WaitForExpression:           "",
+		ExtraHTTPHeaders:            nil,
+		ContentWidthPx:              0,
+		Cookies:                     nil,
+		Username:                    "",
+		Password:                    "",
+		WaitForText:                 "",
+		ViewportWidth:               0,
+		ViewportHeight:              0,
+		DeviceScaleFactor:           0,
+		EmulatedMediaType:           PrintMediaType,
+		StreamThresholdBytes:        config.GoogleChromeStreamThreshold(),
+		CaptureConsole:              false,
+		WriteRateLimit:              config.OutputWriteRateLimit(),
+		BlockedResourceTypes:        nil,
+		CustomCSS:                   "",
+		ForcePageCount:              0,
+		DisableThirdPartyJS:         false,
+		CustomJavaScript:            "",
+		Title:                       "",
+		Author:                      "",
+		Subject:                     "",
+		Keywords:                    "",
+		DisableJavaScript:           false,
+		Timezone:                    "",
+		Locale:                      "",
+	}
+}
+
+/*
+SetPaperSize sets the paper width and height
+according to the given page size, e.g. "A4".
+
+If the page size is not one of PageSizes,
+returns a xerror.Error with xerror.InvalidCode
+and leaves the options unchanged.
+*/
+func (opts *ChromePrinterOptions) SetPaperSize(name string) error {
+	const op string = "printer.ChromePrinterOptions.SetPaperSize"
+	width, height, err := PaperSize(name)
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	opts.PaperWidth = width
+	opts.PaperHeight = height
+	return nil
+}
+
+func (opts ChromePrinterOptions) validate() error {
+	const op string = "printer.ChromePrinterOptions.validate"
+	resolver := func() error {
+		// post-processing requires a local file.
+		if postProcessing := opts.postProcessingOptions(); len(postProcessing) > 0 && opts.Uploader != nil {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("post-processing (%s) cannot be applied when uploading the result", strings.Join(postProcessing, ", ")),
+				nil,
+			)
+		}
+		if opts.ICCProfilePath != "" {
+			if err := validateICCProfile(opts.ICCProfilePath); err != nil {
+				return err
+			}
+		}
+		if info := opts.documentInfo(); len(info) > 0 {
+			if err := validateMetadata(ExplicitMetadataPolicy, info); err != nil {
+				return err
+			}
+		}
+		// the diagnostics are written next to the destination.
+		if opts.DiagnosticsOnError && opts.Uploader != nil {
+			return xerror.Invalid(
+				op,
+				"diagnostics cannot be written when uploading the result",
+				nil,
+			)
+		}
+		if opts.HeaderFontSize < 0 || opts.FooterFontSize < 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("header and footer font sizes should be >= '0', got '%.2f' and '%.2f'", opts.HeaderFontSize, opts.FooterFontSize),
+				nil,
+			)
+		}
+		if opts.PDFX != "" {
+			if err := validatePDFX(opts.PDFX, opts.ICCProfilePath); err != nil {
+				return err
+			}
+		}
+		if opts.DocumentLanguage != "" {
+			if err := validateDocumentLanguage(opts.DocumentLanguage); err != nil {
+				return err
+			}
+		}
+		if opts.Locale != "" {
+			if err := validateDocumentLanguage(opts.Locale); err != nil {
+				return err
+			}
+		}
+		if opts.Grayscale {
+			// both convert the colors.
+			if opts.ICCProfilePath != "" || opts.PDFX != "" {
+				return xerror.Invalid(
+					op,
+					"colors cannot be converted to grayscale alongside an ICC profile or a PDF/X standard",
+					nil,
+				)
+			}
+		}
+		if opts.Thumbnail {
+			if _, err := xassert.String(
+				"thumbnailFormat",
+				opts.ThumbnailFormat,
+				"",
+				xassert.StringOneOf(ScreenshotFormats()),
+			); err != nil {
+				return err
+			}
+			if opts.ThumbnailWidth <= 0 {
+				return xerror.Invalid(
+					op,
+					fmt.Sprintf("thumbnail width should be > '0', got '%d'", opts.ThumbnailWidth),
+					nil,
+				)
+			}
+		}
+		if opts.CPUThrottlingRate < 1 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("CPU throttling rate should be >= '1', got '%.2f'", opts.CPUThrottlingRate),
+				nil,
+			)
+		}
+		if err := validateDebugURL(opts.DebugURL); err != nil {
+			return err
+		}
+		if opts.PageRanges != "" {
+			if err := validatePageRanges(opts.PageRanges); err != nil {
+				return err
+			}
+		}
+		if strings.ContainsAny(opts.AcceptEncoding, "\r\n") {
+			return xerror.Invalid(
+				op,
+				"Accept-Encoding header should not contain line breaks",
+				nil,
+			)
+		}
+		if opts.Password != "" && opts.Username == "" {
+			return xerror.Invalid(op, "password requires a username", nil)
+		}
+		// see RFC 7617.
+		if strings.Contains(opts.Username, ":") {
+			return xerror.Invalid(op, "username should not contain a colon", nil)
+		}
+		if err := validateCookies(opts.Cookies); err != nil {
+			return err
+		}
+		for name, value := range opts.ExtraHTTPHeaders {
+			if name == "" || strings.ContainsAny(name, "\r\n:") || strings.ContainsAny(value, "\r\n") {
+				return xerror.Invalid(
+					op,
+					fmt.Sprintf("extra HTTP header '%s' should have a name without colons and no line breaks", name),
+					nil,
+				)
+			}
+		}
+		if err := validateConsentSelectors(opts.AcceptConsentSelectors); err != nil {
+			return err
+		}
+		// 0 keeps the Google Chrome default.
+		if opts.Scale != 0 && (opts.Scale < 0.1 || opts.Scale > 2) {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("scale should be between '0.1' and '2.0', got '%.2f'", opts.Scale),
+				nil,
+			)
+		}
+		if opts.ContentWidthPx < 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("content width should be >= '0', got '%d'", opts.ContentWidthPx),
+				nil,
+			)
+		}
+		if err := validateWriteRateLimit(opts.WriteRateLimit); err != nil {
+			return err
+		}
+		if opts.StreamThresholdBytes < 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("stream threshold should be >= '0', got '%d'", opts.StreamThresholdBytes),
+				nil,
+			)
+		}
+		// an empty media type is the print one.
+		if opts.EmulatedMediaType != "" {
+			if _, err := xassert.String(
+				"emulatedMediaType",
+				opts.EmulatedMediaType,
+				"",
+				xassert.StringOneOf(MediaTypes()),
+			); err != nil {
+				return err
+			}
+		}
+		// 0 keeps the Google Chrome defaults.
+		if opts.ViewportWidth < 0 || opts.ViewportHeight < 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("viewport size should be >= '0', got '%dx%d'", opts.ViewportWidth, opts.ViewportHeight),
+				nil,
+			)
+		}
+		if opts.DeviceScaleFactor < 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("device scale factor should be >= '0', got '%.2f'", opts.DeviceScaleFactor),
+				nil,
+			)
+		}
+		if opts.ContentWidthPx > 0 {
+			if opts.ViewportWidth != 0 {
+				return xerror.Invalid(
+					op,
+					"content width and viewport width are mutually exclusive, as the content width sets the viewport width",
+					nil,
+				)
+			}
+			if opts.Scale != 0 {
+				return xerror.Invalid(
+					op,
+					"content width and scale are mutually exclusive, as the content width sets the scale",
+					nil,
+				)
+			}
+			if _, err := opts.contentScale(opts.Landscape); err != nil {
+				return err
+			}
+		}
+		// the scripts are disabled thanks to a CSP.
+		if opts.DisableThirdPartyJS && opts.BypassCSP {
+			return xerror.Invalid(
+				op,
+				"disabling the third-party JavaScript and bypassing the CSP are mutually exclusive",
+				nil,
+			)
+		}
+		if opts.ForcePageCount < 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("forced page count should be >= '0', got '%d'", opts.ForcePageCount),
+				nil,
+			)
+		}
+		if opts.ForcePageCount > 0 {
+			if opts.PageRanges != "" {
+				return xerror.Invalid(op, "forced page count and page ranges are mutually exclusive", nil)
+			}
+		}
+		if opts.MinRenderTime < 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("minimum render time should be >= '0', got '%.2f'", opts.MinRenderTime),
+				nil,
+			)
+		}
+		if opts.WaitForFrameCount < 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("frame count should be >= '0', got '%d'", opts.WaitForFrameCount),
+				nil,
+			)
+		}
+		if opts.QRCode {
+			if err := validateQRCode(opts.QRCodePosition, opts.QRCodeSize); err != nil {
+				return err
+			}
+		}
+		if opts.EmbedSourceHTML {
+			// PDF/X forbids embedded files.
+			if opts.PDFX != "" {
+				return xerror.Invalid(
+					op,
+					"the source HTML cannot be embedded alongside a PDF/X standard",
+					nil,
+				)
+			}
+		}
+		if opts.WaitForSubsequentNavigation && opts.SubsequentNavigationTimeout <= 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("subsequent navigation timeout should be > '0', got '%.2f'", opts.SubsequentNavigationTimeout),
+				nil,
+			)
+		}
+		if opts.WaitForNetworkIdle && opts.NetworkIdleDuration <= 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("network idle duration should be > '0', got '%.2f'", opts.NetworkIdleDuration),
+				nil,
+			)
+		}
+		if opts.SlowResources < 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("slow resources count should be >= '0', got '%d'", opts.SlowResources),
+				nil,
+			)
+		}
+		if opts.MaxOutputBytes < 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("maximum output bytes should be >= '0', got '%d'", opts.MaxOutputBytes),
+				nil,
+			)
+		}
+		if opts.MaxDOMNodes < 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("maximum DOM nodes should be >= '0', got '%d'", opts.MaxDOMNodes),
+				nil,
+			)
+		}
+		for _, resourceType := range opts.SubresourceErrorTypes {
+			if _, err := xassert.String(
+				"subresourceErrorTypes",
+				resourceType,
+				"",
+				xassert.StringOneOf(SubresourceTypes()),
+			); err != nil {
+				return err
+			}
+		}
+		for _, resourceType := range opts.BlockedResourceTypes {
+			if _, err := xassert.String(
+				"blockedResourceTypes",
+				resourceType,
+				"",
+				xassert.StringOneOf(BlockableResourceTypes()),
+			); err != nil {
+				return err
+			}
+		}
+		for _, element := range opts.DefaultFooterElements {
+			if _, err := xassert.String(
+				"defaultFooterElements",
+				element,
+				"",
+				xassert.StringOneOf(FooterElements()),
+			); err != nil {
+				return err
+			}
+		}
+		return CheckFonts(opts.RequiredFonts)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+postProcessingOptions describes the given
+options which rewrite the destination once
+printed.
+
+Font subsetting is not one of them: it only
+shrinks the destination, so it is skipped
+when uploading the result.
+*/
+func (opts ChromePrinterOptions) postProcessingOptions() []string {
+	var names []string
+	for name, enabled := range map[string]bool{
+		"an ICC profile":      opts.ICCProfilePath != "",
+		"metadata":            len(opts.documentInfo()) > 0,
+		"a PDF/X standard":    opts.PDFX != "",
+		"a document language": opts.DocumentLanguage != "",
+		"grayscale":           opts.Grayscale,
+		"a forced page count": opts.ForcePageCount > 0,
+		"the source HTML":     opts.EmbedSourceHTML,
+	} {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+documentInfo returns the non-empty metadata among
+the Title, Author, Subject and Keywords options,
+keyed by their PDF Info dictionary entries.
+*/
+func (opts ChromePrinterOptions) documentInfo() map[string]string {
+	info := make(map[string]string)
+	for key, value := range map[string]string{
+		"Title":    opts.Title,
+		"Author":   opts.Author,
+		"Subject":  opts.Subject,
+		"Keywords": opts.Keywords,
+	} {
+		if value != "" {
+			info[key] = value
+		}
+	}
+	return info
+}
+
+/*
+exactColorsCSS forces the rendering of
+background colors and images when printing.
+*/
+const exactColorsCSS string = "* { -webkit-print-color-adjust: exact; print-color-adjust: exact; }"
+
+/*
+css returns the CSS to inject into the page
+according to the options. The custom CSS comes
+last, so that it may override the others.
+*/
+func (opts ChromePrinterOptions) css() string {
+	var css []string
+	if opts.ExactColors {
+		css = append(css, exactColorsCSS)
+	}
+	if opts.ContentWidthPx > 0 {
+		css = append(css, fmt.Sprintf("html, body { max-width: none !important; } body { width: %dpx !important; box-sizing: border-box; }", opts.ContentWidthPx))
+	}
+	if strings.TrimSpace(opts.CustomCSS) != "" {
+		css = append(css, opts.CustomCSS)
+	}
+	return strings.Join(css, "\n")
+}
+
+/*
+contentScale returns the scale which makes the
+ContentWidthPx fit the width of the paper minus
+the margins, i.e. the width Google Chrome lays
+the page out with once printed. In landscape,
+this width comes from the paper height.
+*/
+func (opts ChromePrinterOptions) contentScale(landscape bool) (float64, error) {
+	const (
+		op            string  = "printer.ChromePrinterOptions.contentScale"
+		pixelsPerInch float64 = 96
+	)
+	paperWidth := opts.PaperWidth
+	if landscape {
+		paperWidth = opts.PaperHeight
+	}
+	printableWidth := (paperWidth - opts.MarginLeft - opts.MarginRight) * pixelsPerInch
+	scale := printableWidth / float64(opts.ContentWidthPx)
+	if scale < 0.1 || scale > 2 {
+		return 0, xerror.Invalid(
+			op,
+			fmt.Sprintf(
+				"content width '%dpx' does not fit the printable width '%.0fpx', as it requires a scale of '%.2f' while it should be between '0.1' and '2.0'",
+				opts.ContentWidthPx, printableWidth, scale,
+			),
+			nil,
+		)
+	}
+	return scale, nil
+}
+
+/*
+footerHTML returns the footer template. If no
+footer has been given but default footer elements
+have, it returns a minimal template containing
+only those elements.
+*/
+func (opts ChromePrinterOptions) footerHTML() string {
+	if len(opts.DefaultFooterElements) == 0 || opts.FooterHTML != defaultHeaderFooterHTML {
+		return withFontSize(opts.FooterHTML, opts.FooterFontSize)
+	}
+	fontSize := 8.0
+	if opts.FooterFontSize > 0 {
+		fontSize = opts.FooterFontSize
+	}
+	var spans []string
+	for _, element := range opts.DefaultFooterElements {
+		spans = append(spans, fmt.Sprintf("<span class=\"%s\"></span>", element))
+	}
+	return fmt.Sprintf(
+		"<html><head></head><body><div style=\"font-size: %gpx; width: 100%%; margin: 0 0.4in; display: flex; justify-content: space-between;\">%s</div></body></html>",
+		fontSize,
+		strings.Join(spans, ""),
+	)
+}
+
+// headerHTML returns the header template.
+func (opts ChromePrinterOptions) headerHTML() string {
+	return withFontSize(opts.HeaderHTML, opts.HeaderFontSize)
+}
+
+/*
+withFontSize sets the base font size (in pixels)
+of the given header or footer template, as Google
+Chrome renders them with a tiny default one.
+
+If the size is 0, returns the template as is.
+*/
+func withFontSize(template string, fontSize float64) string {
+	if fontSize <= 0 {
+		return template
+	}
+	style := fmt.Sprintf("<style>html, body { font-size: %gpx; }</style>", fontSize)
+	if i := strings.Index(strings.ToLower(template), "<head>"); i >= 0 {
+		i += len("<head>")
+		return template[:i] + style + template[i:]
 	}
+	return style + template
 }
 
 // nolint: gochecknoglobals
@@ -74,22 +802,51 @@ var devtConnections int
 
 func (p chromePrinter) Print(destination string) error {
 	const op string = "printer.chromePrinter.Print"
+	if p.opts.EmbedSourceHTML {
+		// in case the conversion fails before attaching it.
+		defer os.RemoveAll(filepath.Dir(sourceHTMLFpath(destination))) // nolint: errcheck
+	}
+	if err := p.render(destination, p.output, p.postProcess); err != nil {
+		return xerror.New(requestOp(op, p.opts.RequestID), err)
+	}
+	return nil
+}
+
+/*
+chromeOutput writes the result of a rendering
+to the given destination, using the CDP Client
+of the target once the page is ready.
+*/
+type chromeOutput func(ctx context.Context, client *cdp.Client, destination string) error
+
+/*
+render loads the page in a new Google Chrome
+target and waits until it is ready before calling
+the given chromeOutput.
+
+Once Google Chrome has been released, it calls
+the given post-processing function (if any).
+*/
+func (p chromePrinter) render(destination string, output chromeOutput, postProcess func(ctx context.Context, destination string) error) error {
+	const op string = "printer.chromePrinter.render"
 	logOptions(p.logger, p.opts)
-	ctx, cancel := xcontext.WithTimeout(p.logger, p.opts.WaitTimeout+p.opts.WaitDelay)
+	if err := p.opts.validate(); err != nil {
+		return xerror.New(op, err)
+	}
+	ctx, cancel := withTimeout(p.logger, p.opts.WaitTimeout+p.opts.WaitDelay)
 	defer cancel()
 	resolver := func() error {
-		devt, err := devtool.New("http://localhost:9222").Version(ctx)
-		if err != nil {
-			return err
-		}
-		// connect to WebSocket URL (page) that speaks the Chrome DevTools Protocol.
-		devtConn, err := rpcc.DialContext(ctx, devt.WebSocketDebuggerURL)
+		idleTimeout := xtime.Duration(p.opts.PoolIdleTimeout)
+		maxLifetime := xtime.Duration(p.opts.PoolMaxLifetime)
+		// reuse a connection to Google Chrome (if any).
+		pc, err := devtPool.get(ctx, p.opts.DebugURL, int(p.opts.RpccBufferSize), idleTimeout, maxLifetime)
 		if err != nil {
 			return err
 		}
-		defer devtConn.Close() // nolint: errcheck
+		defer devtPool.put(pc, int(p.opts.PoolSize), idleTimeout, maxLifetime)
+		devtConn := pc.conn.(*devtConn)
 		// create a new CDP Client that uses conn.
-		devtClient := cdp.NewClient(devtConn)
+		devtClient := cdp.NewClient(devtConn.Conn)
 		newContextTarget, err := devtClient.Target.CreateBrowserContext(ctx)
 		if err != nil {
 			return err
@@ -111,82 +868,103 @@ func (p chromePrinter) Print(destination string) error {
 		if err != nil {
 			return err
 		}
-		// connect the client to the new target.
-		newTargetWsURL := fmt.Sprintf("ws://127.0.0.1:9222/devtools/page/%s", newTarget.TargetID)
-		newContextConn, err := rpcc.DialContext(
-			ctx,
-			newTargetWsURL,
-			/*
-				see:
-				https://github.com/thecodingmachine/gotenberg/issues/108
-				https://github.com/mafredri/cdp/issues/4
-				https://github.com/ChromeDevTools/devtools-protocol/issues/24
-			*/
-			rpcc.WithWriteBufferSize(int(p.opts.RpccBufferSize)),
-			rpcc.WithCompression(),
-		)
-		if err != nil {
-			return err
-		}
-		defer newContextConn.Close() // nolint: errcheck
-		// create a new CDP Client that uses newContextConn.
-		targetClient := cdp.NewClient(newContextConn)
 		/*
-			close the target when done.
+			close the target when done, even if the
+			conversion fails, so that the connection
+			goes back to the pool without it.
 			we're not using the "default" context
 			as it may timeout before actually closing
 			the target.
 			see: https://github.com/mafredri/cdp/issues/101#issuecomment-524533670
 		*/
 		closeTargetArgs := target.NewCloseTargetArgs(newTarget.TargetID)
-		defer targetClient.Target.CloseTarget(context.Background(), closeTargetArgs) // nolint: errcheck
+		defer devtClient.Target.CloseTarget(context.Background(), closeTargetArgs) // nolint: errcheck
+		// attach to the new target through the
+		// WebSocket of the pooled connection.
+		newContextConn, err := devtConn.sessions.Dial(ctx, newTarget.TargetID)
+		if err != nil {
+			return err
+		}
+		defer newContextConn.Close() // nolint: errcheck
+		// create a new CDP Client that uses newContextConn.
+		targetClient := cdp.NewClient(newContextConn)
 		// enable all events.
 		if err := p.enableEvents(ctx, targetClient); err != nil {
 			return err
 		}
-		// listen for all events.
-		if err := p.listenEvents(ctx, targetClient); err != nil {
-			return err
+		// estimate the size of the PDF (if a stream threshold).
+		if p.opts.StreamThresholdBytes > 0 {
+			lb, err := startLoadedBytes(targetClient)
+			if err != nil {
+				return err
+			}
+			defer lb.close()
+			ctx = withLoadedBytes(ctx, lb)
 		}
-		// apply a wait delay (if any).
-		if p.opts.WaitDelay > 0.0 {
-			// wait for a given amount of time (useful for javascript delay).
-			p.logger.DebugfOp(op, "applying a wait delay of '%.2fs'...", p.opts.WaitDelay)
-			time.Sleep(xtime.Duration(p.opts.WaitDelay))
-		} else {
-			p.logger.DebugOp(op, "no wait delay to apply, moving on...")
+		// record the resource timings (if requested).
+		if p.opts.SlowResources > 0 {
+			timings, err := startResourceTimings(targetClient)
+			if err != nil {
+				return err
+			}
+			defer timings.close()
+			defer p.logSlowResources(timings)
 		}
-		// print the page to PDF.
-		print, err := targetClient.Page.PrintToPDF(
-			ctx,
-			page.NewPrintToPDFArgs().
-				SetPaperWidth(p.opts.PaperWidth).
-				SetPaperHeight(p.opts.PaperHeight).
-				SetMarginTop(p.opts.MarginTop).
-				SetMarginBottom(p.opts.MarginBottom).
-				SetMarginLeft(p.opts.MarginLeft).
-				SetMarginRight(p.opts.MarginRight).
-				SetLandscape(p.opts.Landscape).
-				SetDisplayHeaderFooter(true).
-				SetHeaderTemplate(p.opts.HeaderHTML).
-				SetFooterTemplate(p.opts.FooterHTML).
-				SetPrintBackground(true),
-		)
-		if err != nil {
-			if strings.Contains(err.Error(), "rpcc: message too large") {
-				return xerror.Invalid(
-					op,
-					fmt.Sprintf(
-						"'%d' bytes are not enough: increase the Google Chrome rpcc buffer size (up to 100 MB)",
-						p.opts.RpccBufferSize,
-					),
-					err,
-				)
+		// record the console messages (if requested).
+		var console *consoleMessages
+		if p.opts.CaptureConsole {
+			c, err := startConsoleMessages(ctx, targetClient)
+			if err != nil {
+				return err
 			}
-			return err
+			defer c.close()
+			console = c
 		}
-		if err := ioutil.WriteFile(destination, print.Data, 0644); err != nil {
-			return err
+		// record the diagnostics (if requested).
+		var diag *diagnostics
+		if p.opts.DiagnosticsOnError {
+			d, err := startDiagnostics(targetClient)
+			if err != nil {
+				return err
+			}
+			defer d.close()
+			diag = d
+		}
+		// emulate the timezone and/or the locale (if any).
+		if p.opts.Timezone != "" || p.opts.Locale != "" {
+			if err := p.emulateLocale(ctx, targetClient, newContextConn); err != nil {
+				return err
+			}
+		}
+		err = p.load(ctx, targetClient, destination, output)
+		if err == nil {
+			return nil
+		}
+		if console != nil {
+			err = console.wrap(err)
+		}
+		if diag != nil {
+			return p.diagnose(diag, targetClient, destination, err)
+		}
+		return err
+	}
+	// post-processing does not require Google Chrome,
+	// so it happens once the lock has been released.
+	postProcessing := func() error {
+		if postProcess == nil {
+			return nil
+		}
+		if err := postProcess(ctx, destination); err != nil {
+			// the destination has not been post-processed
+			// as requested: do not leave it behind if the
+			// conversion has been canceled or timed out.
+			if ctx.Err() != nil && p.opts.Uploader == nil {
+				os.Remove(destination) // nolint: errcheck
+			}
+			return mustHandleError(
+				ctx,
+				xerror.New(op, err),
+			)
 		}
 		return nil
 	}
@@ -196,12 +974,12 @@ func (p chromePrinter) Print(destination string) error {
 		err := resolver()
 		devtConnections--
 		if err != nil {
-			return xcontext.MustHandleError(
+			return mustHandleError(
 				ctx,
 				xerror.New(op, err),
 			)
 		}
-		return nil
+		return postProcessing()
 	}
 	p.logger.DebugOp(op, "waiting lock to be acquired...")
 	select {
@@ -213,53 +991,584 @@ func (p chromePrinter) Print(destination string) error {
 		devtConnections--
 		<-lockChrome // we release the lock.
 		if err != nil {
-			return xcontext.MustHandleError(
+			return mustHandleError(
 				ctx,
 				xerror.New(op, err),
 			)
 		}
-		return nil
+		return postProcessing()
 	case <-ctx.Done():
 		// failed to acquire lock before
 		// deadline.
 		p.logger.DebugOp(op, "failed to acquire lock before context.Context deadline")
-		return xcontext.MustHandleError(
+		return mustHandleError(
 			ctx,
 			ctx.Err(),
 		)
 	}
 }
 
-func (p chromePrinter) enableEvents(ctx context.Context, client *cdp.Client) error {
-	const op string = "printer.chromePrinter.enableEvents"
-	// enable all the domain events that we're interested in.
-	if err := runBatch(
-		func() error { return client.DOM.Enable(ctx) },
-		func() error { return client.Network.Enable(ctx, network.NewEnableArgs()) },
-		func() error { return client.Page.Enable(ctx) },
-		func() error {
-			return client.Page.SetLifecycleEventsEnabled(ctx, page.NewSetLifecycleEventsEnabledArgs(true))
-		},
-		func() error { return client.Runtime.Enable(ctx) },
-	); err != nil {
-		return xerror.New(op, err)
-	}
-	return nil
-}
-
-func (p chromePrinter) listenEvents(ctx context.Context, client *cdp.Client) error {
-	const op string = "printer.chromePrinter.listenEvents"
+/*
+load navigates to the page and waits until it is
+ready before calling the given chromeOutput.
+*/
+func (p chromePrinter) load(ctx context.Context, client *cdp.Client, destination string, output chromeOutput) error {
+	const op string = "printer.chromePrinter.load"
 	resolver := func() error {
-		// make sure Page events are enabled.
-		if err := client.Page.Enable(ctx); err != nil {
-			return err
+		// throttle the CPU (if any rate).
+		if p.opts.CPUThrottlingRate > 1 {
+			if err := p.throttleCPU(ctx, client); err != nil {
+				return err
+			}
 		}
-		// make sure Network events are enabled.
-		if err := client.Network.Enable(ctx, nil); err != nil {
-			return err
+		// emulate a device scale factor and/or
+		// a viewport size (if any).
+		if width, height, scaleFactor := p.deviceMetrics(); width > 0 || height > 0 || (scaleFactor > 0 && scaleFactor != 1.0) {
+			if err := p.emulateDeviceMetrics(ctx, client); err != nil {
+				return err
+			}
 		}
-		// create all clients for events.
-		domContentEventFired, err := client.Page.DOMContentEventFired(ctx)
+		// set the extra HTTP headers, including the
+		// Accept-Encoding header (if any).
+		if len(p.opts.ExtraHTTPHeaders) > 0 || p.opts.AcceptEncoding != "" {
+			if err := p.setExtraHTTPHeaders(ctx, client); err != nil {
+				return err
+			}
+		}
+		// emulate the screen media type (if requested).
+		if p.opts.EmulatedMediaType == ScreenMediaType {
+			if err := p.emulateMedia(ctx, client); err != nil {
+				return err
+			}
+		}
+		// set the cookies (if any).
+		if len(p.opts.Cookies) > 0 {
+			if err := p.setCookies(ctx, client); err != nil {
+				return err
+			}
+		}
+		// disable the Content-Security-Policy (if requested).
+		if p.opts.BypassCSP {
+			if err := p.bypassCSP(ctx, client); err != nil {
+				return err
+			}
+		}
+		// disable the scripts of the page (if requested).
+		if p.opts.DisableJavaScript {
+			if err := p.disableJavaScript(ctx, client); err != nil {
+				return err
+			}
+		}
+		// block the requests, answer the authentication challenges
+		// and disable the scripts of the third-party frames (if any).
+		if len(p.opts.BlockedResourceTypes) > 0 || p.opts.Username != "" || p.opts.DisableThirdPartyJS {
+			i, err := startInterception(ctx, p.logger, client, p.pageURL(), p.opts)
+			if err != nil {
+				return err
+			}
+			defer i.close()
+		}
+		// track the requests in flight (if requested).
+		var activity *networkActivity
+		if p.opts.WaitForNetworkIdle {
+			na, err := startNetworkActivity(client)
+			if err != nil {
+				return err
+			}
+			defer na.close()
+			activity = na
+		}
+		navigationStart := time.Now()
+		// listen for all events.
+		if err := p.listenEvents(ctx, client); err != nil {
+			return err
+		}
+		// accept the consent banner (if any selectors).
+		if len(p.opts.AcceptConsentSelectors) > 0 {
+			if err := p.acceptConsent(ctx, client); err != nil {
+				return err
+			}
+		}
+		// wait for a selector (if any).
+		if p.opts.WaitForSelector != "" {
+			if err := p.waitForSelector(ctx, client); err != nil {
+				return err
+			}
+		}
+		// wait for an expression to be truthy (if any).
+		if p.opts.WaitForExpression != "" {
+			if err := p.waitForExpression(ctx, client); err != nil {
+				return err
+			}
+		}
+		// wait for a text to appear (if any).
+		if p.opts.WaitForText != "" {
+			if err := p.waitForText(ctx, client); err != nil {
+				return err
+			}
+		}
+		// simplify the page (if requested).
+		if p.opts.ReaderMode {
+			if err := p.applyReaderMode(ctx, client); err != nil {
+				return err
+			}
+		}
+		// hide everything but the print area (if any).
+		if p.opts.PrintAreaSelector != "" {
+			if err := p.isolatePrintArea(ctx, client); err != nil {
+				return err
+			}
+		}
+		// check the DOM size (if any limit).
+		if p.opts.MaxDOMNodes > 0 {
+			if err := p.checkDOMNodes(ctx, client); err != nil {
+				return err
+			}
+		}
+		// inject the CSS (if any).
+		if css := p.opts.css(); css != "" {
+			if err := injectCSS(ctx, client, css); err != nil {
+				return err
+			}
+		}
+		// run the custom JavaScript (if any).
+		if strings.TrimSpace(p.opts.CustomJavaScript) != "" {
+			if err := p.runCustomJavaScript(ctx, client); err != nil {
+				return err
+			}
+		}
+		// wait for the network to be idle (if requested).
+		if activity != nil {
+			if err := p.waitForNetworkIdle(ctx, activity); err != nil {
+				return err
+			}
+		}
+		// apply a wait delay (if any).
+		if p.opts.WaitDelay > 0.0 {
+			// wait for a given amount of time (useful for javascript delay).
+			p.logger.DebugfOp(op, "applying a wait delay of '%.2fs'...", p.opts.WaitDelay)
+			time.Sleep(xtime.Duration(p.opts.WaitDelay))
+		} else {
+			p.logger.DebugOp(op, "no wait delay to apply, moving on...")
+		}
+		// ensure a minimum render time (if any).
+		if p.opts.MinRenderTime > 0.0 {
+			if err := p.waitForMinRenderTime(ctx, time.Since(navigationStart)); err != nil {
+				return err
+			}
+		}
+		return output(ctx, client, destination)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+waitForMinRenderTime waits until the minimum render
+time has elapsed since the navigation started.
+Unlike the wait delay, it does not wait at all if
+the page took longer than that to be ready.
+*/
+func (p chromePrinter) waitForMinRenderTime(ctx context.Context, elapsed time.Duration) error {
+	const op string = "printer.chromePrinter.waitForMinRenderTime"
+	remaining := xtime.Duration(p.opts.MinRenderTime) - elapsed
+	if remaining <= 0 {
+		p.logger.DebugfOp(op, "minimum render time of '%.2fs' already elapsed, moving on...", p.opts.MinRenderTime)
+		return nil
+	}
+	p.logger.DebugfOp(op, "waiting '%.2fs' to reach the minimum render time...", remaining.Seconds())
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return xerror.New(op, ctx.Err())
+	}
+}
+
+/*
+diagnose writes the diagnostics of the page next
+to the destination, and wraps the given error of
+the conversion in a DiagnosticsError referencing
+them. If they cannot be written, it returns the
+given error as is.
+*/
+func (p chromePrinter) diagnose(d *diagnostics, client *cdp.Client, destination string, err error) error {
+	const op string = "printer.chromePrinter.diagnose"
+	dirPath := DiagnosticsDestination(destination)
+	p.logger.DebugfOp(op, "conversion failed, writing diagnostics to '%s'...", dirPath)
+	if writeErr := d.write(p.logger, client, dirPath); writeErr != nil {
+		p.logger.ErrorOp(op, writeErr)
+		return err
+	}
+	return &DiagnosticsError{DirPath: dirPath, err: err}
+}
+
+/*
+output prints the page to PDF and, if requested,
+captures a thumbnail of its first viewport using
+the same navigation.
+*/
+func (p chromePrinter) output(ctx context.Context, client *cdp.Client, destination string) error {
+	const op string = "printer.chromePrinter.output"
+	resolver := func() error {
+		// the screen media type has been
+		// emulated before loading the page.
+		if p.opts.EmulatedMediaType != ScreenMediaType {
+			if err := p.triggerPrintMedia(ctx, client); err != nil {
+				return err
+			}
+		}
+		// the source HTML is attached once printed.
+		if p.opts.EmbedSourceHTML {
+			if err := p.captureSourceHTML(ctx, client, sourceHTMLFpath(destination)); err != nil {
+				return err
+			}
+		}
+		print := p.printToPDF
+		if p.opts.ForcePageCount > 0 {
+			print = p.printToPageCount
+		}
+		if err := print(ctx, client, destination); err != nil {
+			return err
+		}
+		if !p.opts.Thumbnail {
+			return nil
+		}
+		return p.thumbnail(ctx, client, ThumbnailDestination(destination, p.opts.ThumbnailFormat))
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+// isMessageTooLarge returns true if the given error
+// comes from a reply larger than the rpcc buffer.
+func isMessageTooLarge(err error) bool {
+	return strings.Contains(err.Error(), "rpcc: message too large")
+}
+
+func (p chromePrinter) printToPDF(ctx context.Context, client *cdp.Client, destination string) error {
+	const op string = "printer.chromePrinter.printToPDF"
+	resolver := func() error {
+		landscape, err := p.landscape(ctx, client)
+		if err != nil {
+			return err
+		}
+		header, footer, err := p.headerFooterHTML()
+		if err != nil {
+			return err
+		}
+		args := page.NewPrintToPDFArgs().
+			SetMarginTop(p.opts.MarginTop).
+			SetMarginBottom(p.opts.MarginBottom).
+			SetMarginLeft(p.opts.MarginLeft).
+			SetMarginRight(p.opts.MarginRight).
+			SetLandscape(landscape).
+			SetDisplayHeaderFooter(true).
+			SetHeaderTemplate(header).
+			SetFooterTemplate(footer).
+			SetPrintBackground(true)
+		if p.opts.PageRanges != "" {
+			args.SetPageRanges(p.opts.PageRanges)
+		}
+		if p.opts.Scale > 0 {
+			args.SetScale(p.opts.Scale)
+		}
+		// the content width sets the scale so that
+		// the content fits the width of the pages,
+		// unless the page count is being fitted.
+		if p.opts.ContentWidthPx > 0 && p.opts.Scale == 0 {
+			scale, err := p.opts.contentScale(landscape)
+			if err != nil {
+				return err
+			}
+			args.SetScale(scale)
+		}
+		// the @page rules decide the paper size.
+		if p.opts.PreferCSSPageSize {
+			args.SetPreferCSSPageSize(true)
+		} else {
+			args.SetPaperWidth(p.opts.PaperWidth).
+				SetPaperHeight(p.opts.PaperHeight)
+		}
+		// the stream mode allows to stop reading once the
+		// maximum output size is crossed, and keeps the
+		// memory bounded for large PDFs.
+		stream := p.streamTransferMode(ctx)
+		if stream {
+			args.SetTransferMode("ReturnAsStream")
+		}
+		print, err := client.Page.PrintToPDF(ctx, args)
+		// the loaded bytes underestimated the PDF.
+		if err != nil && !stream && p.opts.StreamThresholdBytes > 0 && isMessageTooLarge(err) {
+			p.logger.DebugOp(op, "PDF too large for the rpcc buffer: switching to stream transfer mode")
+			args.SetTransferMode("ReturnAsStream")
+			print, err = client.Page.PrintToPDF(ctx, args)
+		}
+		if err != nil {
+			if isMessageTooLarge(err) {
+				return xerror.Invalid(
+					op,
+					fmt.Sprintf(
+						"'%d' bytes are not enough: increase the Google Chrome rpcc buffer size (up to 100 MB)",
+						p.opts.RpccBufferSize,
+					),
+					err,
+				)
+			}
+			// e.g. a page range beyond the last page.
+			if p.opts.PageRanges != "" && strings.Contains(err.Error(), "Page range") {
+				return xerror.Invalid(
+					op,
+					fmt.Sprintf("Google Chrome rejected the page ranges '%s'", p.opts.PageRanges),
+					err,
+				)
+			}
+			return err
+		}
+		return p.writePDF(ctx, client, destination, print)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+writePDF writes the PDF printed by Google Chrome,
+either from its data or from its stream.
+
+Google Chrome sometimes succeeds without any data:
+in such a case, returns a xerror.Error with
+xerror.InternalCode, so that the conversion may be
+retried, and never writes an empty file.
+*/
+func (p chromePrinter) writePDF(ctx context.Context, client *cdp.Client, destination string, print *page.PrintToPDFReply) error {
+	const op string = "printer.chromePrinter.writePDF"
+	resolver := func() error {
+		if print.Stream != nil {
+			return p.writeStream(ctx, client, destination, *print.Stream)
+		}
+		if len(print.Data) == 0 {
+			return xerror.Internal(op, "Google Chrome returned an empty PDF", nil)
+		}
+		return p.write(ctx, destination, print.Data)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+thumbnail captures the first viewport of the page,
+scaled down (or up) to the thumbnail width.
+*/
+func (p chromePrinter) thumbnail(ctx context.Context, client *cdp.Client, destination string) error {
+	const op string = "printer.chromePrinter.thumbnail"
+	resolver := func() error {
+		metrics, err := client.Page.GetLayoutMetrics(ctx)
+		if err != nil {
+			return err
+		}
+		viewport := metrics.LayoutViewport
+		if viewport.ClientWidth <= 0 {
+			return xerror.Invalid(op, "page has an empty viewport", nil)
+		}
+		args := screenshotArgs(p.opts.ThumbnailFormat, 100).SetClip(page.Viewport{
+			X:      0,
+			Y:      0,
+			Width:  float64(viewport.ClientWidth),
+			Height: float64(viewport.ClientHeight),
+			Scale:  float64(p.opts.ThumbnailWidth) / float64(viewport.ClientWidth),
+		})
+		return p.capture(ctx, client, destination, args)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+// capture captures a screenshot of the page
+// and writes it to the given destination.
+func (p chromePrinter) capture(ctx context.Context, client *cdp.Client, destination string, args *page.CaptureScreenshotArgs) error {
+	const op string = "printer.chromePrinter.capture"
+	resolver := func() error {
+		screenshot, err := client.Page.CaptureScreenshot(ctx, args)
+		if err != nil {
+			return err
+		}
+		return p.write(ctx, destination, screenshot.Data)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+write writes the given data to the destination,
+or streams it to the Uploader (if any).
+
+The data is first written to a temporary file,
+which is renamed to the destination only if the
+conversion has neither been canceled nor timed
+out meanwhile. Otherwise it is removed, so that
+there is never a partial file at the destination.
+*/
+func (p chromePrinter) write(ctx context.Context, destination string, data []byte) error {
+	const op string = "printer.chromePrinter.write"
+	if p.opts.Uploader != nil {
+		p.logger.DebugfOp(op, "uploading '%s'...", destination)
+		if err := p.opts.Uploader.Upload(ctx, destination, bytes.NewReader(data)); err != nil {
+			return xerror.New(op, err)
+		}
+		return nil
+	}
+	tmpDest := fmt.Sprintf("%s/%s%s", filepath.Dir(destination), xrand.Get(), filepath.Ext(destination))
+	resolver := func() error {
+		if err := writeFile(ctx, tmpDest, bytes.NewReader(data), p.opts.WriteRateLimit); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return os.Rename(tmpDest, destination)
+	}
+	if err := resolver(); err != nil {
+		os.Remove(tmpDest) // nolint: errcheck
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+ThumbnailDestination returns the destination of
+the thumbnail captured alongside the PDF file
+written to the given destination.
+*/
+func ThumbnailDestination(destination, format string) string {
+	ext := filepath.Ext(destination)
+	return fmt.Sprintf("%s_thumbnail.%s", strings.TrimSuffix(destination, ext), format)
+}
+
+/*
+landscape returns true if the page should be
+printed in landscape. If AutoLandscape is enabled
+and Landscape is not, it is the case when the
+rendered content is wider than tall.
+*/
+func (p chromePrinter) landscape(ctx context.Context, client *cdp.Client) (bool, error) {
+	const op string = "printer.chromePrinter.landscape"
+	if p.opts.Landscape || !p.opts.AutoLandscape {
+		return p.opts.Landscape, nil
+	}
+	metrics, err := client.Page.GetLayoutMetrics(ctx)
+	if err != nil {
+		return false, xerror.New(op, err)
+	}
+	landscape := metrics.ContentSize.Width > metrics.ContentSize.Height
+	p.logger.DebugfOp(
+		op,
+		"content size is '%.0fx%.0f', landscape: '%t'",
+		metrics.ContentSize.Width,
+		metrics.ContentSize.Height,
+		landscape,
+	)
+	return landscape, nil
+}
+
+func (p chromePrinter) postProcess(ctx context.Context, destination string) error {
+	const op string = "printer.chromePrinter.postProcess"
+	resolver := func() error {
+		// the PDF/X conversion embeds the ICC profile.
+		if p.opts.ICCProfilePath != "" && p.opts.PDFX == "" {
+			if err := embedICCProfile(ctx, p.logger, destination, p.opts.ICCProfilePath); err != nil {
+				return err
+			}
+		}
+		if p.opts.Grayscale {
+			if err := convertToGrayscale(ctx, p.logger, destination); err != nil {
+				return err
+			}
+		}
+		/*
+			Ghostscript subsets the fonts by default, so
+			a dedicated rewrite is only required if no
+			other rewrite has happened. There is no local
+			file to rewrite when uploading the result.
+		*/
+		if p.opts.SubsetFonts && p.opts.Uploader == nil && p.opts.ICCProfilePath == "" && p.opts.PDFX == "" && !p.opts.Grayscale {
+			if err := subsetFonts(ctx, p.logger, destination); err != nil {
+				return err
+			}
+		}
+		// the PDF/X conversion comes last, as the
+		// other rewrites would not keep its output intent.
+		if p.opts.PDFX != "" {
+			if err := convertToPDFX(ctx, p.logger, destination, p.opts.PDFX, p.opts.ICCProfilePath); err != nil {
+				return err
+			}
+		} else if p.opts.EmbedSourceHTML {
+			// Ghostscript would not keep the attachment.
+			if err := attachSourceHTML(ctx, p.logger, destination); err != nil {
+				return err
+			}
+		}
+		// Ghostscript rewrites some metadata.
+		if info := p.opts.documentInfo(); len(info) > 0 {
+			if err := applyMetadata(ctx, p.logger, destination, ExplicitMetadataPolicy, info); err != nil {
+				return err
+			}
+		}
+		// the rewrites above would drop the language.
+		if p.opts.DocumentLanguage != "" {
+			return setDocumentLanguage(ctx, p.logger, destination, p.opts.DocumentLanguage)
+		}
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+func (p chromePrinter) enableEvents(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.enableEvents"
+	// enable all the domain events that we're interested in.
+	if err := runBatch(
+		func() error { return client.DOM.Enable(ctx) },
+		func() error { return client.Network.Enable(ctx, network.NewEnableArgs()) },
+		func() error { return client.Page.Enable(ctx) },
+		func() error {
+			return client.Page.SetLifecycleEventsEnabled(ctx, page.NewSetLifecycleEventsEnabledArgs(true))
+		},
+		func() error { return client.Runtime.Enable(ctx) },
+	); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+func (p chromePrinter) listenEvents(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.listenEvents"
+	resolver := func() error {
+		// make sure Page events are enabled.
+		if err := client.Page.Enable(ctx); err != nil {
+			return err
+		}
+		// make sure Network events are enabled.
+		if err := client.Network.Enable(ctx, nil); err != nil {
+			return err
+		}
+		// create all clients for events.
+		domContentEventFired, err := client.Page.DOMContentEventFired(ctx)
 		if err != nil {
 			return err
 		}
@@ -279,11 +1588,37 @@ func (p chromePrinter) listenEvents(ctx context.Context, client *cdp.Client) err
 			return err
 		}
 		defer loadingFinished.Close() // nolint: errcheck
-		if _, err := client.Page.Navigate(ctx, page.NewNavigateArgs(p.url)); err != nil {
+		responseReceived, err := client.Network.ResponseReceived(ctx)
+		if err != nil {
+			return err
+		}
+		defer responseReceived.Close() // nolint: errcheck
+		frameStoppedLoading, err := client.Page.FrameStoppedLoading(ctx)
+		if err != nil {
+			return err
+		}
+		defer frameStoppedLoading.Close() // nolint: errcheck
+		requestWillBeSent, err := client.Network.RequestWillBeSent(ctx)
+		if err != nil {
+			return err
+		}
+		defer requestWillBeSent.Close() // nolint: errcheck
+		loadingFailed, err := client.Network.LoadingFailed(ctx)
+		if err != nil {
+			return err
+		}
+		defer loadingFailed.Close() // nolint: errcheck
+		frameNavigated, err := client.Page.FrameNavigated(ctx)
+		if err != nil {
+			return err
+		}
+		defer frameNavigated.Close() // nolint: errcheck
+		navigate, err := client.Page.Navigate(ctx, page.NewNavigateArgs(p.url))
+		if err != nil {
 			return err
 		}
 		// wait for all events.
-		return runBatch(
+		waits := []func() error{
 			func() error {
 				_, err := domContentEventFired.Recv()
 				if err != nil {
@@ -322,7 +1657,646 @@ func (p chromePrinter) listenEvents(ctx context.Context, client *cdp.Client) err
 				p.logger.DebugOp(op, "event 'loadingFinished' received")
 				return nil
 			},
+		}
+		if p.opts.WaitForReadyStateComplete {
+			// polling the ready state replaces
+			// the load events.
+			waits = []func() error{
+				func() error {
+					return p.waitForReadyStateComplete(ctx, client)
+				},
+			}
+		}
+		if p.opts.WaitForFrameCount > 0 {
+			waits = append(waits, func() error {
+				loaded := make(map[page.FrameID]bool)
+				for int64(len(loaded)) < p.opts.WaitForFrameCount {
+					select {
+					case <-ctx.Done():
+						return xerror.Timeout(
+							op,
+							fmt.Sprintf(
+								"only '%d' frame(s) out of '%d' loaded before the deadline",
+								len(loaded),
+								p.opts.WaitForFrameCount,
+							),
+							ctx.Err(),
+						)
+					case <-frameStoppedLoading.Ready():
+						ev, err := frameStoppedLoading.Recv()
+						if err != nil {
+							return err
+						}
+						/*
+							only the child frames count, not the main
+							one. As their events may be received in any
+							order, a frame counts once it stopped loading,
+							whether its attachment has been received or not.
+						*/
+						if ev.FrameID == navigate.FrameID {
+							continue
+						}
+						p.logger.DebugfOp(op, "event 'frameStoppedLoading' received for frame '%s'", ev.FrameID)
+						loaded[ev.FrameID] = true
+					}
+				}
+				return nil
+			})
+		}
+		if p.opts.FailOnHTTPError && navigate.LoaderID != nil {
+			waits = append(waits, func() error {
+				for {
+					ev, err := responseReceived.Recv()
+					if err != nil {
+						return err
+					}
+					// only the response of the main
+					// document matters.
+					if ev.Type != network.ResourceTypeDocument || ev.LoaderID != *navigate.LoaderID {
+						continue
+					}
+					p.logger.DebugfOp(op, "event 'responseReceived' received with status '%d'", ev.Response.Status)
+					if !isAcceptableStatusCode(ev.Response.Status, p.opts.AcceptableStatusCodes) {
+						return xerror.Invalid(
+							op,
+							fmt.Sprintf("'%s' returned HTTP status '%d'", p.url, ev.Response.Status),
+							nil,
+						)
+					}
+					return nil
+				}
+			})
+		}
+		if err := runBatch(waits...); err != nil {
+			return err
+		}
+		// wait for a client-side redirect (if requested).
+		if p.opts.WaitForSubsequentNavigation {
+			if err := p.waitForSubsequentNavigation(ctx, client, frameNavigated); err != nil {
+				return err
+			}
+		}
+		if !p.opts.FailOnSubresourceError {
+			return nil
+		}
+		// the page is loaded: every subresource
+		// failure has already been received.
+		var mainRequestID network.RequestID
+		if navigate.LoaderID != nil {
+			mainRequestID = network.RequestID(*navigate.LoaderID)
+		}
+		failedURLs := p.failedSubresources(requestWillBeSent, loadingFailed, mainRequestID)
+		if len(failedURLs) > 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("subresource(s) failed to load: '%s'", strings.Join(failedURLs, "', '")),
+				nil,
+			)
+		}
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+failedSubresources drains the given streams and
+returns the URLs of the subresources which failed
+to load, filtered by the SubresourceErrorTypes (if
+any). The main document, the canceled requests and the
+blocked ones are ignored.
+*/
+func (p chromePrinter) failedSubresources(requestWillBeSent network.RequestWillBeSentClient, loadingFailed network.LoadingFailedClient, mainRequestID network.RequestID) []string {
+	const op string = "printer.chromePrinter.failedSubresources"
+	urls := make(map[network.RequestID]string)
+	for drained := false; !drained; {
+		select {
+		case <-requestWillBeSent.Ready():
+			ev, err := requestWillBeSent.Recv()
+			if err != nil {
+				drained = true
+				continue
+			}
+			urls[ev.RequestID] = ev.Request.URL
+		default:
+			drained = true
+		}
+	}
+	var failedURLs []string
+	for drained := false; !drained; {
+		select {
+		case <-loadingFailed.Ready():
+			ev, err := loadingFailed.Recv()
+			if err != nil {
+				drained = true
+				continue
+			}
+			if ev.RequestID == mainRequestID || (ev.Canceled != nil && *ev.Canceled) {
+				continue
+			}
+			if !isSubresourceErrorType(string(ev.Type), p.opts.SubresourceErrorTypes) {
+				continue
+			}
+			// the blocked requests fail on purpose.
+			if isBlockedResourceType(string(ev.Type), p.opts.BlockedResourceTypes) {
+				continue
+			}
+			p.logger.DebugfOp(op, "event 'loadingFailed' received for '%s': %s", urls[ev.RequestID], ev.ErrorText)
+			failedURLs = append(failedURLs, urls[ev.RequestID])
+		default:
+			drained = true
+		}
+	}
+	return failedURLs
+}
+
+/*
+isSubresourceErrorType returns true if a failure
+of a subresource of the given type should fail the
+conversion. If no types are given, every type does.
+*/
+func isSubresourceErrorType(resourceType string, types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+isBlockedResourceType returns true if the requests
+of the given type are blocked.
+*/
+func isBlockedResourceType(resourceType string, blocked []string) bool {
+	for _, t := range blocked {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+func (p chromePrinter) waitForSelector(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.waitForSelector"
+	p.logger.DebugfOp(op, "waiting for selector '%s'...", p.opts.WaitForSelector)
+	resolver := func() error {
+		selector, err := json.Marshal(p.opts.WaitForSelector)
+		if err != nil {
+			return err
+		}
+		expression := fmt.Sprintf("document.querySelector(%s) !== null", selector)
+		err = poll(ctx, p.opts.PollInterval, func() (bool, error) {
+			return evaluateBool(ctx, client, expression)
+		})
+		if err != nil && xerror.Code(err) == xerror.TimeoutCode {
+			return xerror.Timeout(
+				op,
+				fmt.Sprintf("selector '%s' did not appear before the deadline", p.opts.WaitForSelector),
+				err,
+			)
+		}
+		return err
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	p.logger.DebugfOp(op, "selector '%s' found", p.opts.WaitForSelector)
+	return nil
+}
+
+/*
+waitForExpression polls the given JavaScript
+expression until it evaluates to a truthy value.
+An exception (e.g. a variable the page has not
+defined yet) counts as a falsy value.
+*/
+func (p chromePrinter) waitForExpression(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.waitForExpression"
+	p.logger.DebugfOp(op, "waiting for expression '%s' to be true...", p.opts.WaitForExpression)
+	resolver := func() error {
+		expression := fmt.Sprintf("!!(%s)", p.opts.WaitForExpression)
+		var exception error
+		err := poll(ctx, p.opts.PollInterval, func() (bool, error) {
+			ok, err := evaluateBool(ctx, client, expression)
+			// evaluate returns a xerror.InvalidCode
+			// if the expression threw an exception.
+			if err != nil && xerror.Code(err) == xerror.InvalidCode {
+				p.logger.DebugfOp(op, "expression '%s' not true yet: %s", p.opts.WaitForExpression, xerror.Message(err))
+				exception = err
+				return false, nil
+			}
+			return ok, err
+		})
+		if err != nil && xerror.Code(err) == xerror.TimeoutCode {
+			message := fmt.Sprintf("expression '%s' never became true before the deadline", p.opts.WaitForExpression)
+			if exception != nil {
+				message = fmt.Sprintf("%s, last exception: %s", message, xerror.Message(exception))
+			}
+			return xerror.Timeout(op, message, err)
+		}
+		return err
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	p.logger.DebugfOp(op, "expression '%s' is true", p.opts.WaitForExpression)
+	return nil
+}
+
+/*
+waitForText polls the text of the page until it
+contains the given text. The match is case-sensitive.
+*/
+func (p chromePrinter) waitForText(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.waitForText"
+	p.logger.DebugfOp(op, "waiting for text '%s'...", p.opts.WaitForText)
+	resolver := func() error {
+		text, err := json.Marshal(p.opts.WaitForText)
+		if err != nil {
+			return err
+		}
+		expression := fmt.Sprintf("!!document.body && document.body.innerText.includes(%s)", text)
+		err = poll(ctx, p.opts.PollInterval, func() (bool, error) {
+			return evaluateBool(ctx, client, expression)
+		})
+		if err != nil && xerror.Code(err) == xerror.TimeoutCode {
+			return xerror.Timeout(
+				op,
+				fmt.Sprintf("text '%s' did not appear before the deadline", p.opts.WaitForText),
+				err,
+			)
+		}
+		return err
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	p.logger.DebugfOp(op, "text '%s' found", p.opts.WaitForText)
+	return nil
+}
+
+/*
+waitForReadyStateComplete polls the ready state of
+the document until it is "complete". It is more
+forgiving than the load events for pages which
+never fire them cleanly.
+*/
+func (p chromePrinter) waitForReadyStateComplete(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.waitForReadyStateComplete"
+	p.logger.DebugOp(op, "waiting for ready state 'complete'...")
+	resolver := func() error {
+		err := poll(ctx, p.opts.PollInterval, func() (bool, error) {
+			return evaluateBool(ctx, client, "document.readyState === 'complete'")
+		})
+		if err != nil && xerror.Code(err) == xerror.TimeoutCode {
+			return xerror.Timeout(
+				op,
+				"ready state did not reach 'complete' before the deadline",
+				err,
+			)
+		}
+		return err
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	p.logger.DebugOp(op, "ready state 'complete' reached")
+	return nil
+}
+
+/*
+waitForSubsequentNavigation waits for the main
+frame to navigate a second time, e.g. because of
+a JavaScript redirect, then for the new document
+to reach the ready state "complete". If it does
+not navigate again within the
+SubsequentNavigationTimeout option, the
+current page is kept.
+
+The given stream should have been created before
+the first navigation, so that it is counted.
+*/
+func (p chromePrinter) waitForSubsequentNavigation(ctx context.Context, client *cdp.Client, frameNavigated page.FrameNavigatedClient) error {
+	const op string = "printer.chromePrinter.waitForSubsequentNavigation"
+	p.logger.DebugOp(op, "waiting for a subsequent navigation...")
+	resolver := func() error {
+		grace := time.NewTimer(xtime.Duration(p.opts.SubsequentNavigationTimeout))
+		defer grace.Stop()
+		navigations := 0
+		for navigations < 2 {
+			select {
+			case <-ctx.Done():
+				return xerror.Timeout(
+					op,
+					"no subsequent navigation before the deadline",
+					ctx.Err(),
+				)
+			case <-grace.C:
+				p.logger.DebugfOp(op, "no subsequent navigation within '%.2fs', moving on...", p.opts.SubsequentNavigationTimeout)
+				return nil
+			case <-frameNavigated.Ready():
+				ev, err := frameNavigated.Recv()
+				if err != nil {
+					return err
+				}
+				// only the main frame matters.
+				if ev.Frame.ParentID != nil {
+					continue
+				}
+				navigations++
+				p.logger.DebugfOp(op, "event 'frameNavigated' received for '%s'", ev.Frame.URL)
+			}
+		}
+		return p.waitForReadyStateComplete(ctx, client)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+deviceMetrics returns the viewport size and the
+device scale factor to emulate, 0 meaning that
+the Google Chrome default is kept.
+
+The content width sets the viewport width, and
+the device scale factor of a screenshot wins
+over the one of the options.
+*/
+func (p chromePrinter) deviceMetrics() (int64, int64, float64) {
+	width := p.opts.ViewportWidth
+	if p.opts.ContentWidthPx > 0 {
+		width = p.opts.ContentWidthPx
+	}
+	scaleFactor := p.opts.DeviceScaleFactor
+	if p.deviceScaleFactor > 0 {
+		scaleFactor = p.deviceScaleFactor
+	}
+	return width, p.opts.ViewportHeight, scaleFactor
+}
+
+/*
+emulateDeviceMetrics overrides the device scale
+factor and the viewport size (if any) before the
+page is loaded, so that its device-pixel-ratio and
+size media queries and its responsive images match
+them from the start.
+*/
+func (p chromePrinter) emulateDeviceMetrics(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.emulateDeviceMetrics"
+	width, height, scaleFactor := p.deviceMetrics()
+	p.logger.DebugfOp(
+		op,
+		"emulating a device scale factor of '%.2f' and a viewport size of '%dx%d'...",
+		scaleFactor,
+		width,
+		height,
+	)
+	// a width, a height or a device scale factor
+	// equal to 0 keeps the current value.
+	args := emulation.NewSetDeviceMetricsOverrideArgs(int(width), int(height), scaleFactor, false)
+	if err := client.Emulation.SetDeviceMetricsOverride(ctx, args); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+throttleCPU slows down the CPU of the target by
+the CPUThrottlingRate (e.g. 2 is a 2x slowdown)
+before the page is loaded, so that slow devices
+may be emulated.
+*/
+func (p chromePrinter) throttleCPU(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.throttleCPU"
+	p.logger.DebugfOp(op, "throttling the CPU with a rate of '%.2f'...", p.opts.CPUThrottlingRate)
+	args := emulation.NewSetCPUThrottlingRateArgs(p.opts.CPUThrottlingRate)
+	if err := client.Emulation.SetCPUThrottlingRate(ctx, args); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+setExtraHTTPHeaders sets the extra HTTP headers
+and the Accept-Encoding header of every request of
+the page, including the document request. Google
+Chrome only advertises its own encodings if this
+header is not already set, so that "identity"
+disables compression.
+
+As Google Chrome replaces the extra HTTP headers
+on each call, they are all sent at once.
+*/
+func (p chromePrinter) setExtraHTTPHeaders(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.setExtraHTTPHeaders"
+	p.logger.DebugfOp(op, "setting '%d' extra HTTP header(s)...", len(p.opts.ExtraHTTPHeaders))
+	resolver := func() error {
+		headers := make(map[string]string)
+		for name, value := range p.opts.ExtraHTTPHeaders {
+			// the Accept-Encoding option takes
+			// precedence over an extra header.
+			if p.opts.AcceptEncoding != "" && strings.EqualFold(name, "Accept-Encoding") {
+				continue
+			}
+			headers[name] = value
+		}
+		if p.opts.AcceptEncoding != "" {
+			p.logger.DebugfOp(op, "setting Accept-Encoding header to '%s'...", p.opts.AcceptEncoding)
+			headers["Accept-Encoding"] = p.opts.AcceptEncoding
+		}
+		b, err := json.Marshal(headers)
+		if err != nil {
+			return err
+		}
+		return client.Network.SetExtraHTTPHeaders(ctx, network.NewSetExtraHTTPHeadersArgs(b))
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+logSlowResources logs the slowest subresources
+of the page, so that the ones slowing down the
+conversion may be identified.
+*/
+func (p chromePrinter) logSlowResources(timings *resourceTimings) {
+	const op string = "printer.chromePrinter.logSlowResources"
+	slowest := timings.slowest(int(p.opts.SlowResources))
+	if len(slowest) == 0 {
+		p.logger.InfoOp(op, "no resource has been loaded")
+		return
+	}
+	p.logger.InfofOp(op, "slowest resources: %s", formatResourceTimings(slowest))
+}
+
+/*
+bypassCSP disables the Content-Security-Policy
+of the page, so that the styles and scripts the
+API injects are not blocked. It weakens the
+security of the page, but only while rendering.
+*/
+func (p chromePrinter) bypassCSP(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.bypassCSP"
+	p.logger.DebugfOp(op, "bypassing Content-Security-Policy...")
+	if err := client.Page.SetBypassCSP(ctx, page.NewSetBypassCSPArgs(true)); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+disableJavaScript disables the scripts of the
+page before it is loaded, so that slow or
+untrusted scripts cannot delay the conversion.
+The scripts the API evaluates still run.
+*/
+func (p chromePrinter) disableJavaScript(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.disableJavaScript"
+	p.logger.DebugfOp(op, "disabling JavaScript...")
+	if err := client.Emulation.SetScriptExecutionDisabled(ctx, emulation.NewSetScriptExecutionDisabledArgs(true)); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+printAreaScript marks the first element matching
+the given selector and its ancestors, then hides
+every other element thanks to a style sheet. It
+returns false if no element matches.
+*/
+const printAreaScript string = `(selector => {
+	const area = document.querySelector(selector);
+	if (area === null) {
+		return false;
+	}
+	area.setAttribute('data-gotenberg-print-area', '');
+	for (let el = area.parentElement; el !== null; el = el.parentElement) {
+		el.setAttribute('data-gotenberg-print-ancestor', '');
+	}
+	const style = document.createElement('style');
+	style.textContent = '[data-gotenberg-print-ancestor] > :not([data-gotenberg-print-ancestor]):not([data-gotenberg-print-area]) { display: none !important; }';
+	(document.head || document.documentElement).appendChild(style);
+	return true;
+})(%s)`
+
+/*
+isolatePrintArea hides everything but the subtree
+of the element matching the PrintAreaSelector and
+its ancestors, so that navigation and other page
+chrome do not end up in the PDF.
+
+If no element matches, returns a xerror.Error
+with xerror.InvalidCode.
+*/
+func (p chromePrinter) isolatePrintArea(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.isolatePrintArea"
+	p.logger.DebugfOp(op, "isolating print area '%s'...", p.opts.PrintAreaSelector)
+	resolver := func() error {
+		selector, err := json.Marshal(p.opts.PrintAreaSelector)
+		if err != nil {
+			return err
+		}
+		result, err := evaluate(ctx, client, fmt.Sprintf(printAreaScript, selector))
+		if err != nil {
+			return err
+		}
+		var found bool
+		if err := json.Unmarshal(result.Value, &found); err != nil {
+			return err
+		}
+		if !found {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("print area selector '%s' does not match any element", p.opts.PrintAreaSelector),
+				nil,
+			)
+		}
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+checkDOMNodes returns a xerror.Error with
+xerror.InvalidCode if the page contains more
+elements than allowed, so that Google Chrome
+does not have to print a DOM bomb.
+*/
+func (p chromePrinter) checkDOMNodes(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.checkDOMNodes"
+	resolver := func() error {
+		result, err := evaluate(ctx, client, "document.getElementsByTagName('*').length")
+		if err != nil {
+			return err
+		}
+		var nodes int64
+		if err := json.Unmarshal(result.Value, &nodes); err != nil {
+			return err
+		}
+		p.logger.DebugfOp(op, "page contains '%d' DOM node(s)", nodes)
+		if nodes > p.opts.MaxDOMNodes {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("page contains '%d' DOM nodes, maximum is '%d'", nodes, p.opts.MaxDOMNodes),
+				nil,
+			)
+		}
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+runCustomJavaScript evaluates the CustomJavaScript
+option in the page, awaiting its result if it is a
+Promise, e.g. for expanding the collapsed sections.
+
+If it throws (or its Promise is rejected), returns
+a xerror.Error with xerror.InvalidCode.
+*/
+func (p chromePrinter) runCustomJavaScript(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.runCustomJavaScript"
+	p.logger.DebugOp(op, "running the custom JavaScript...")
+	if _, err := evaluate(ctx, client, p.opts.CustomJavaScript); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+injectCSS appends a style element containing
+the given CSS to the head of the page.
+*/
+func injectCSS(ctx context.Context, client *cdp.Client, css string) error {
+	const op string = "printer.injectCSS"
+	resolver := func() error {
+		content, err := json.Marshal(css)
+		if err != nil {
+			return err
+		}
+		expression := fmt.Sprintf(
+			"(() => { const style = document.createElement('style'); style.textContent = %s; (document.head || document.documentElement).appendChild(style); return true; })()",
+			content,
 		)
+		_, err = evaluate(ctx, client, expression)
+		return err
 	}
 	if err := resolver(); err != nil {
 		return xerror.New(op, err)
@@ -330,6 +2304,81 @@ func (p chromePrinter) listenEvents(ctx context.Context, client *cdp.Client) err
 	return nil
 }
 
+/*
+evaluate evaluates the given JavaScript expression
+in the page and returns its result by value. If the
+expression resolves to a Promise, it awaits it.
+
+If the expression throws, returns a xerror.Error
+with xerror.InvalidCode.
+*/
+func evaluate(ctx context.Context, client *cdp.Client, expression string) (runtime.RemoteObject, error) {
+	const op string = "printer.evaluate"
+	reply, err := client.Runtime.Evaluate(
+		ctx,
+		runtime.NewEvaluateArgs(expression).
+			SetReturnByValue(true).
+			SetAwaitPromise(true),
+	)
+	if err != nil {
+		return runtime.RemoteObject{}, xerror.New(op, err)
+	}
+	if reply.ExceptionDetails != nil {
+		return runtime.RemoteObject{}, xerror.Invalid(
+			op,
+			fmt.Sprintf("expression '%s' threw an exception: %s", expression, exceptionMessage(reply.ExceptionDetails)),
+			nil,
+		)
+	}
+	return reply.Result, nil
+}
+
+// evaluateBool evaluates the given JavaScript
+// expression and returns its boolean result.
+func evaluateBool(ctx context.Context, client *cdp.Client, expression string) (bool, error) {
+	const op string = "printer.evaluateBool"
+	result, err := evaluate(ctx, client, expression)
+	if err != nil {
+		return false, xerror.New(op, err)
+	}
+	var value bool
+	if err := json.Unmarshal(result.Value, &value); err != nil {
+		return false, xerror.Invalid(
+			op,
+			fmt.Sprintf("expression '%s' did not return a boolean, got '%s'", expression, result.Type),
+			err,
+		)
+	}
+	return value, nil
+}
+
+func exceptionMessage(details *runtime.ExceptionDetails) string {
+	if details.Exception != nil && details.Exception.Description != nil {
+		return *details.Exception.Description
+	}
+	return details.Text
+}
+
+/*
+isAcceptableStatusCode returns true if the given
+HTTP status is either a 2xx or 3xx status, or one of
+the given acceptable status codes.
+
+A status equal to 0 comes from a non-HTTP scheme
+(e.g. "file://") and is always acceptable.
+*/
+func isAcceptableStatusCode(status int, acceptableStatusCodes []int64) bool {
+	if status == 0 || (status >= 200 && status < 400) {
+		return true
+	}
+	for _, code := range acceptableStatusCodes {
+		if int64(status) == code {
+			return true
+		}
+	}
+	return false
+}
+
 func runBatch(fn ...func() error) error {
 	// run all functions simultaneously and wait until
 	// execution has completed or an error is encountered.
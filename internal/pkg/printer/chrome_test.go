@@ -0,0 +1,358 @@
+package printer
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mafredri/cdp/protocol/page"
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestIsAcceptableStatusCode(t *testing.T) {
+	// 2xx and 3xx statuses are acceptable.
+	assert.True(t, isAcceptableStatusCode(200, nil))
+	assert.True(t, isAcceptableStatusCode(302, nil))
+	// non-HTTP schemes are acceptable.
+	assert.True(t, isAcceptableStatusCode(0, nil))
+	// 4xx and 5xx statuses are not acceptable...
+	assert.False(t, isAcceptableStatusCode(401, nil))
+	assert.False(t, isAcceptableStatusCode(500, []int64{401}))
+	// ...unless explicitly given.
+	assert.True(t, isAcceptableStatusCode(401, []int64{401}))
+}
+
+func TestChromePrinterOptionsCSS(t *testing.T) {
+	opts := ChromePrinterOptions{ExactColors: true}
+	assert.Equal(t, exactColorsCSS, opts.css())
+	opts.ExactColors = false
+	assert.Equal(t, "", opts.css())
+	opts.ContentWidthPx = 1200
+	assert.Contains(t, opts.css(), "width: 1200px !important")
+	// the custom CSS should come last.
+	opts.CustomCSS = "#cookie-banner { display: none; }"
+	assert.True(t, strings.HasSuffix(opts.css(), "\n#cookie-banner { display: none; }"))
+	// a blank custom CSS should be ignored.
+	opts = ChromePrinterOptions{CustomCSS: " \n"}
+	assert.Equal(t, "", opts.css())
+}
+
+func TestChromePrinterOptionsDocumentInfo(t *testing.T) {
+	opts := ChromePrinterOptions{}
+	assert.Empty(t, opts.documentInfo())
+	opts.Title = "Foo"
+	opts.Keywords = "foo, bar"
+	assert.Equal(t, map[string]string{"Title": "Foo", "Keywords": "foo, bar"}, opts.documentInfo())
+}
+
+func TestChromePrinterDeviceMetrics(t *testing.T) {
+	p := chromePrinter{opts: ChromePrinterOptions{ViewportWidth: 1280, ViewportHeight: 1024, DeviceScaleFactor: 2}}
+	width, height, scaleFactor := p.deviceMetrics()
+	assert.Equal(t, int64(1280), width)
+	assert.Equal(t, int64(1024), height)
+	assert.Equal(t, 2.0, scaleFactor)
+	// the content width sets the viewport width.
+	p = chromePrinter{opts: ChromePrinterOptions{ContentWidthPx: 1200}}
+	width, height, scaleFactor = p.deviceMetrics()
+	assert.Equal(t, int64(1200), width)
+	assert.Equal(t, int64(0), height)
+	assert.Equal(t, 0.0, scaleFactor)
+	// the device scale factor of a screenshot wins.
+	p = chromePrinter{opts: ChromePrinterOptions{DeviceScaleFactor: 2}, deviceScaleFactor: 3}
+	_, _, scaleFactor = p.deviceMetrics()
+	assert.Equal(t, 3.0, scaleFactor)
+}
+
+func TestChromePrinterOptionsContentScale(t *testing.T) {
+	opts := ChromePrinterOptions{
+		PaperWidth:     8.5,
+		PaperHeight:    11,
+		MarginLeft:     0.5,
+		MarginRight:    0.5,
+		ContentWidthPx: 1536,
+	}
+	// the printable width is 7.5in, i.e. 720px.
+	scale, err := opts.contentScale(false)
+	assert.Nil(t, err)
+	assert.Equal(t, 0.46875, scale)
+	// in landscape, it is 10in, i.e. 960px.
+	scale, err = opts.contentScale(true)
+	assert.Nil(t, err)
+	assert.Equal(t, 0.625, scale)
+	// should not be OK as the scale is > 2.
+	opts.ContentWidthPx = 100
+	_, err = opts.contentScale(false)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestChromePrinterOptionsFooterHTML(t *testing.T) {
+	// no default footer elements.
+	opts := ChromePrinterOptions{FooterHTML: defaultHeaderFooterHTML}
+	assert.Equal(t, defaultHeaderFooterHTML, opts.footerHTML())
+	// default footer elements.
+	opts.DefaultFooterElements = []string{PageNumberFooterElement}
+	assert.Contains(t, opts.footerHTML(), "<span class=\"pageNumber\"></span>")
+	assert.NotContains(t, opts.footerHTML(), "url")
+	// a given footer wins.
+	opts.FooterHTML = "<html><head></head><body>foo</body></html>"
+	assert.Equal(t, opts.FooterHTML, opts.footerHTML())
+	// a footer font size.
+	opts.FooterFontSize = 12
+	assert.Equal(t, "<html><head><style>html, body { font-size: 12px; }</style></head><body>foo</body></html>", opts.footerHTML())
+	opts.FooterHTML = defaultHeaderFooterHTML
+	assert.Contains(t, opts.footerHTML(), "font-size: 12px;")
+}
+
+func TestChromePrinterOptionsHeaderHTML(t *testing.T) {
+	opts := ChromePrinterOptions{HeaderHTML: "<p>foo</p>"}
+	assert.Equal(t, "<p>foo</p>", opts.headerHTML())
+	// no head element.
+	opts.HeaderFontSize = 10.5
+	assert.Equal(t, "<style>html, body { font-size: 10.5px; }</style><p>foo</p>", opts.headerHTML())
+}
+
+func TestIsSubresourceErrorType(t *testing.T) {
+	// every type fails the conversion.
+	assert.True(t, isSubresourceErrorType("Image", nil))
+	// only the given types fail the conversion.
+	assert.True(t, isSubresourceErrorType("Stylesheet", []string{"Stylesheet", "Font"}))
+	assert.False(t, isSubresourceErrorType("Image", []string{"Stylesheet", "Font"}))
+}
+
+func TestThumbnailDestination(t *testing.T) {
+	assert.Equal(t, "/tmp/foo_thumbnail.png", ThumbnailDestination("/tmp/foo.pdf", PNGScreenshotFormat))
+	assert.Equal(t, "/tmp/foo_thumbnail.jpeg", ThumbnailDestination("/tmp/foo", JPEGScreenshotFormat))
+}
+
+func TestChromePrinterOptionsSetPaperSize(t *testing.T) {
+	opts := ChromePrinterOptions{PaperWidth: 8.27, PaperHeight: 11.7}
+	err := opts.SetPaperSize(LegalPageSize)
+	assert.Nil(t, err)
+	assert.Equal(t, 8.5, opts.PaperWidth)
+	assert.Equal(t, 14.0, opts.PaperHeight)
+	// should not be OK as the page size
+	// is unknown, the options should
+	// remain unchanged.
+	err = opts.SetPaperSize("foo")
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	assert.Equal(t, 14.0, opts.PaperHeight)
+}
+
+func TestChromePrinterOptionsValidate(t *testing.T) {
+	// no DOM nodes limit.
+	opts := ChromePrinterOptions{MaxDOMNodes: 0, CPUThrottlingRate: 1, DebugURL: "http://localhost:9222"}
+	assert.Nil(t, opts.validate())
+	// a DOM nodes limit.
+	opts.MaxDOMNodes = 1000
+	assert.Nil(t, opts.validate())
+	// a negative DOM nodes limit.
+	opts.MaxDOMNodes = -1
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a negative frame count.
+	opts = ChromePrinterOptions{WaitForFrameCount: -1, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a CPU throttling rate.
+	opts = ChromePrinterOptions{CPUThrottlingRate: 4, DebugURL: "http://localhost:9222"}
+	assert.Nil(t, opts.validate())
+	// a CPU throttling rate which speeds up the CPU.
+	opts = ChromePrinterOptions{CPUThrottlingRate: 0.5}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// an invalid subresource error type.
+	opts = ChromePrinterOptions{SubresourceErrorTypes: []string{"foo"}, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a negative minimum render time.
+	opts = ChromePrinterOptions{MinRenderTime: -1, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a negative header font size.
+	opts = ChromePrinterOptions{HeaderFontSize: -1, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// an invalid DevTools endpoint.
+	opts = ChromePrinterOptions{DebugURL: "localhost:9222", CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// invalid page ranges.
+	opts = ChromePrinterOptions{PageRanges: "4-2", CPUThrottlingRate: 1, DebugURL: "http://localhost:9222"}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a grayscale conversion alongside a PDF/X standard.
+	opts = ChromePrinterOptions{Grayscale: true, PDFX: PDFX32003, CPUThrottlingRate: 1, DebugURL: "http://localhost:9222"}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a scale.
+	opts = ChromePrinterOptions{Scale: 0.5, CPUThrottlingRate: 1, DebugURL: "http://localhost:9222"}
+	assert.Nil(t, opts.validate())
+	// a scale beyond the Google Chrome bounds.
+	opts = ChromePrinterOptions{Scale: 2.5, CPUThrottlingRate: 1, DebugURL: "http://localhost:9222"}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a network idle wait without an idle duration.
+	opts = ChromePrinterOptions{WaitForNetworkIdle: true, CPUThrottlingRate: 1, DebugURL: "http://localhost:9222"}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// an Accept-Encoding header with a line break.
+	opts = ChromePrinterOptions{AcceptEncoding: "gzip\r\nX-Foo: bar", CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// an extra HTTP header with a line break.
+	opts = ChromePrinterOptions{ExtraHTTPHeaders: map[string]string{"X-Foo": "bar\r\nX-Baz: qux"}, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// an extra HTTP header without name.
+	opts = ChromePrinterOptions{ExtraHTTPHeaders: map[string]string{"": "bar"}, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a negative content width.
+	opts = ChromePrinterOptions{ContentWidthPx: -1, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a content width with a scale.
+	opts = ChromePrinterOptions{ContentWidthPx: 1200, Scale: 0.5, PaperWidth: 8.27, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a content width which does not fit the paper.
+	opts = ChromePrinterOptions{ContentWidthPx: 10000, PaperWidth: 8.27, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a password without username.
+	opts = ChromePrinterOptions{Password: "foo", CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a username with a colon.
+	opts = ChromePrinterOptions{Username: "foo:bar", Password: "foo", CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a negative viewport size.
+	opts = ChromePrinterOptions{ViewportHeight: -1, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a negative device scale factor.
+	opts = ChromePrinterOptions{DeviceScaleFactor: -1, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a content width with a viewport width.
+	opts = ChromePrinterOptions{ContentWidthPx: 1200, ViewportWidth: 1280, PaperWidth: 8.27, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// an unknown emulated media type.
+	opts = ChromePrinterOptions{EmulatedMediaType: "tv", CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a negative stream threshold.
+	opts = ChromePrinterOptions{StreamThresholdBytes: -1, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a negative write rate limit.
+	opts = ChromePrinterOptions{WriteRateLimit: -1, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a blocked main document.
+	opts = ChromePrinterOptions{BlockedResourceTypes: []string{"Image", "Document"}, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a negative forced page count.
+	opts = ChromePrinterOptions{ForcePageCount: -1, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a forced page count with page ranges.
+	opts = ChromePrinterOptions{ForcePageCount: 1, PageRanges: "1-2", CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a forced page count with an uploader.
+	opts = ChromePrinterOptions{ForcePageCount: 1, Uploader: NewDirectoryUploader("/tmp"), CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// third-party JavaScript disabled while bypassing the CSP.
+	opts = ChromePrinterOptions{DisableThirdPartyJS: true, BypassCSP: true, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// metadata with a line break.
+	opts = ChromePrinterOptions{Title: "foo\nbar", CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// metadata with an uploader.
+	opts = ChromePrinterOptions{Title: "foo", Uploader: NewDirectoryUploader("/tmp"), CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// several post-processing options with an uploader.
+	opts = ChromePrinterOptions{Grayscale: true, ForcePageCount: 2, Uploader: NewDirectoryUploader("/tmp"), CPUThrottlingRate: 1}
+	err := opts.validate()
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	assert.Equal(t, "post-processing (a forced page count, grayscale) cannot be applied when uploading the result", xerror.Message(err))
+	// font subsetting with an uploader.
+	opts = ChromePrinterOptions{SubsetFonts: true, Uploader: NewDirectoryUploader("/tmp"), CPUThrottlingRate: 1, DebugURL: "http://localhost:9222"}
+	assert.Nil(t, opts.validate())
+	// a subsequent navigation without timeout.
+	opts = ChromePrinterOptions{WaitForSubsequentNavigation: true, SubsequentNavigationTimeout: 0, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// an invalid locale.
+	opts = ChromePrinterOptions{Locale: "fr_FR", CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// a negative maximum output size.
+	opts = ChromePrinterOptions{MaxOutputBytes: -1, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// an unknown QR code position.
+	opts = ChromePrinterOptions{QRCode: true, QRCodePosition: "middle", QRCodeSize: 0.6, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+	// an empty consent selector.
+	opts = ChromePrinterOptions{AcceptConsentSelectors: []string{"#accept", " "}, CPUThrottlingRate: 1}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(opts.validate()))
+}
+
+func TestChromePrinterWrite(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "write")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dirPath) // nolint: errcheck
+	p := chromePrinter{logger: test.DebugLogger()}
+	dest := dirPath + "/result.pdf"
+	// should write the destination.
+	err = p.write(context.Background(), dest, []byte("foo"))
+	assert.Nil(t, err)
+	assert.FileExists(t, dest)
+	err = os.Remove(dest)
+	assert.Nil(t, err)
+	// should write the destination at
+	// most at the write rate limit.
+	p.opts.WriteRateLimit = 1000
+	err = p.write(context.Background(), dest, []byte("foo"))
+	assert.Nil(t, err)
+	assert.FileExists(t, dest)
+	err = os.Remove(dest)
+	assert.Nil(t, err)
+	p.opts.WriteRateLimit = 0
+	// should not write anything as
+	// the conversion has been canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = p.write(ctx, dest, []byte("foo"))
+	assert.NotNil(t, err)
+	files, err := ioutil.ReadDir(dirPath)
+	assert.Nil(t, err)
+	assert.Empty(t, files)
+}
+
+func TestChromePrinterWritePDF(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "writePDF")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dirPath) // nolint: errcheck
+	p := chromePrinter{logger: test.DebugLogger()}
+	dest := dirPath + "/result.pdf"
+	// should write the destination.
+	err = p.writePDF(context.Background(), nil, dest, &page.PrintToPDFReply{Data: []byte("%PDF")})
+	assert.Nil(t, err)
+	assert.FileExists(t, dest)
+	err = os.Remove(dest)
+	assert.Nil(t, err)
+	// should not be OK as Google Chrome
+	// returned an empty PDF, which may
+	// be retried.
+	err = p.writePDF(context.Background(), nil, dest, &page.PrintToPDFReply{})
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InternalCode, xerror.Code(err))
+	assert.True(t, isRetryable(err))
+	files, err := ioutil.ReadDir(dirPath)
+	assert.Nil(t, err)
+	assert.Empty(t, files)
+}
+
+func TestChromePrinterWaitForMinRenderTime(t *testing.T) {
+	p := chromePrinter{
+		logger: test.DebugLogger(),
+		opts:   ChromePrinterOptions{MinRenderTime: 0.2},
+	}
+	// should not wait as the page took
+	// longer than the minimum render time.
+	start := time.Now()
+	err := p.waitForMinRenderTime(context.Background(), time.Second)
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) < 100*time.Millisecond)
+	// should wait for the remaining time.
+	start = time.Now()
+	err = p.waitForMinRenderTime(context.Background(), 100*time.Millisecond)
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	// should not be OK as context.Context
+	// has been canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = p.waitForMinRenderTime(ctx, 0)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,109 @@
+package printer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mafredri/cdp"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+/*
+DefaultConsentSelectors returns the CSS selectors
+of the "accept" buttons of the most common consent
+management platforms (OneTrust, Cookiebot, Didomi,
+Quantcast, TrustArc, etc.).
+*/
+func DefaultConsentSelectors() []string {
+	return []string{
+		"#onetrust-accept-btn-handler",
+		"#CybotCookiebotDialogBodyLevelButtonLevelOptinAllowAll",
+		"#CybotCookiebotDialogBodyButtonAccept",
+		"#didomi-notice-agree-button",
+		".qc-cmp2-summary-buttons button[mode='primary']",
+		"#truste-consent-button",
+		"#axeptio_btn_acceptAll",
+		".iubenda-cs-accept-btn",
+		".cky-btn-accept",
+		".cmplz-accept",
+		"#BorlabsCookieBox a._brlbs-btn-accept-all",
+		".cc-btn.cc-allow",
+	}
+}
+
+/*
+acceptConsentScript clicks the first visible element
+matching one of the given selectors, in order, and
+returns its selector, or an empty string if none
+matches. Invalid selectors are skipped.
+*/
+const acceptConsentScript string = `(selectors => {
+	for (const selector of selectors) {
+		let element = null;
+		try {
+			element = document.querySelector(selector);
+		} catch (e) {
+			continue;
+		}
+		if (element !== null && element.getClientRects().length > 0) {
+			element.click();
+			return selector;
+		}
+	}
+	return '';
+})(%s)`
+
+/*
+acceptConsent tries to accept the consent banner
+of the page by clicking one of the
+AcceptConsentSelectors. If none matches, the
+conversion goes on anyway.
+*/
+func (p chromePrinter) acceptConsent(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.acceptConsent"
+	resolver := func() error {
+		selectors, err := json.Marshal(p.opts.AcceptConsentSelectors)
+		if err != nil {
+			return err
+		}
+		result, err := evaluate(ctx, client, fmt.Sprintf(acceptConsentScript, selectors))
+		if err != nil {
+			return err
+		}
+		var clicked string
+		if err := json.Unmarshal(result.Value, &clicked); err != nil {
+			return err
+		}
+		if clicked == "" {
+			p.logger.DebugOp(op, "no consent banner found, moving on...")
+			return nil
+		}
+		p.logger.DebugfOp(op, "consent accepted thanks to '%s'", clicked)
+		// let the page reveal its content.
+		if err := waitForLayout(ctx, client); err != nil {
+			return err
+		}
+		return waitForImages(ctx, client)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+validateConsentSelectors returns a xerror.Error
+with xerror.InvalidCode if one of the given
+selectors is empty.
+*/
+func validateConsentSelectors(selectors []string) error {
+	const op string = "printer.validateConsentSelectors"
+	for _, selector := range selectors {
+		if strings.TrimSpace(selector) == "" {
+			return xerror.Invalid(op, "consent selectors should not be empty", nil)
+		}
+	}
+	return nil
+}
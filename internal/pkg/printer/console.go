@@ -0,0 +1,157 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mafredri/cdp"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+/*
+maxConsoleMessages is the maximum number of
+console messages kept by a ConsoleError, so
+that a chatty page does not flood it.
+*/
+const maxConsoleMessages int = 50
+
+/*
+ConsoleError wraps the error of a failed
+conversion with the console messages logged
+by the page while it was loaded.
+*/
+type ConsoleError struct {
+	Messages []string
+	err      error
+}
+
+func (e ConsoleError) Error() string {
+	if len(e.Messages) == 0 {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("%s (console: %s)", e.err.Error(), strings.Join(e.Messages, "; "))
+}
+
+// Unwrap returns the error of the failed conversion.
+func (e ConsoleError) Unwrap() error {
+	return e.err
+}
+
+/*
+consoleMessages records the console messages,
+the uncaught exceptions and the browser log
+entries (e.g. blocked resources) of a page
+while it is loaded.
+*/
+type consoleMessages struct {
+	mu       sync.Mutex
+	messages []string
+	closers  []func() error
+}
+
+/*
+startConsoleMessages starts recording the console
+messages of the page. The recording stops once
+closed.
+*/
+func startConsoleMessages(ctx context.Context, client *cdp.Client) (*consoleMessages, error) {
+	const op string = "printer.startConsoleMessages"
+	c := &consoleMessages{}
+	resolver := func() error {
+		// see startDiagnostics.
+		streamCtx := context.Background()
+		consoleAPICalled, err := client.Runtime.ConsoleAPICalled(streamCtx)
+		if err != nil {
+			return err
+		}
+		c.closers = append(c.closers, consoleAPICalled.Close)
+		exceptionThrown, err := client.Runtime.ExceptionThrown(streamCtx)
+		if err != nil {
+			return err
+		}
+		c.closers = append(c.closers, exceptionThrown.Close)
+		entryAdded, err := client.Log.EntryAdded(streamCtx)
+		if err != nil {
+			return err
+		}
+		c.closers = append(c.closers, entryAdded.Close)
+		// each goroutine stops once its stream is closed.
+		go func() {
+			for {
+				ev, err := consoleAPICalled.Recv()
+				if err != nil {
+					return
+				}
+				c.record(fmt.Sprintf("[%s] %s", ev.Type, consoleArgs(ev.Args)))
+			}
+		}()
+		go func() {
+			for {
+				ev, err := exceptionThrown.Recv()
+				if err != nil {
+					return
+				}
+				c.record(fmt.Sprintf("[exception] %s", exceptionMessage(&ev.ExceptionDetails)))
+			}
+		}()
+		go func() {
+			for {
+				ev, err := entryAdded.Recv()
+				if err != nil {
+					return
+				}
+				c.record(logEntryMessage(ev.Entry.Source, ev.Entry.Level, ev.Entry.Text, ev.Entry.URL))
+			}
+		}()
+		return client.Log.Enable(ctx)
+	}
+	if err := resolver(); err != nil {
+		c.close()
+		return nil, xerror.New(op, err)
+	}
+	return c, nil
+}
+
+/*
+record adds the given message, dropping the
+oldest one if maxConsoleMessages is reached.
+*/
+func (c *consoleMessages) record(message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.messages) == maxConsoleMessages {
+		c.messages = c.messages[1:]
+	}
+	c.messages = append(c.messages, message)
+}
+
+/*
+wrap wraps the given error of the conversion
+in a ConsoleError with the messages recorded
+so far.
+*/
+func (c *consoleMessages) wrap(err error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	messages := make([]string, len(c.messages))
+	copy(messages, c.messages)
+	return &ConsoleError{Messages: messages, err: err}
+}
+
+func (c *consoleMessages) close() {
+	for _, closer := range c.closers {
+		closer() // nolint: errcheck
+	}
+}
+
+// logEntryMessage returns the string
+// representation of a browser log entry.
+func logEntryMessage(source, level, text string, URL *string) string {
+	message := fmt.Sprintf("[%s:%s] %s", source, level, text)
+	if URL != nil && *URL != "" {
+		message = fmt.Sprintf("%s (%s)", message, *URL)
+	}
+	return message
+}
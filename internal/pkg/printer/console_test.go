@@ -0,0 +1,49 @@
+package printer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+func TestConsoleError(t *testing.T) {
+	err := xerror.New("foo", &ConsoleError{
+		Messages: []string{"[error] foo", "[exception] Uncaught ReferenceError: bar is not defined"},
+		err:      xerror.Timeout("bar", "context has timed out", nil),
+	})
+	// the wrapped error should keep its code and message.
+	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+	assert.Equal(t, "context has timed out", xerror.Message(err))
+	assert.Contains(t, err.Error(), "[error] foo; [exception] Uncaught ReferenceError")
+	var consoleErr *ConsoleError
+	assert.True(t, errors.As(err, &consoleErr))
+	assert.Len(t, consoleErr.Messages, 2)
+	// without messages, it should be the wrapped error.
+	err = &ConsoleError{err: xerror.Timeout("bar", "context has timed out", nil)}
+	assert.NotContains(t, err.Error(), "console")
+}
+
+func TestConsoleMessagesRecord(t *testing.T) {
+	c := &consoleMessages{}
+	for i := 0; i < maxConsoleMessages+10; i++ {
+		c.record(fmt.Sprintf("[log] %d", i))
+	}
+	var consoleErr *ConsoleError
+	assert.True(t, errors.As(c.wrap(xerror.New("foo", nil)), &consoleErr))
+	// the oldest messages should have been dropped.
+	assert.Len(t, consoleErr.Messages, maxConsoleMessages)
+	assert.Equal(t, "[log] 10", consoleErr.Messages[0])
+}
+
+func TestLogEntryMessage(t *testing.T) {
+	URL := "https://foo.bar/baz.js"
+	assert.Equal(
+		t,
+		"[network:error] Failed to load resource (https://foo.bar/baz.js)",
+		logEntryMessage("network", "error", "Failed to load resource", &URL),
+	)
+	assert.Equal(t, "[javascript:warning] foo", logEntryMessage("javascript", "warning", "foo", nil))
+}
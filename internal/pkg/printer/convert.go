@@ -0,0 +1,177 @@
+package printer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+// PDFOutputFormat is the PDF output format.
+const PDFOutputFormat string = "pdf"
+
+// OutputFormats returns a slice containing
+// all output formats Convert supports.
+func OutputFormats() []string {
+	return []string{
+		PDFOutputFormat,
+		PNGScreenshotFormat,
+		JPEGScreenshotFormat,
+	}
+}
+
+// ConvertOptions gathers the options of
+// the Printers Convert may dispatch to.
+type ConvertOptions struct {
+	Chrome     ChromePrinterOptions
+	Office     OfficePrinterOptions
+	Merge      MergePrinterOptions
+	SVG        SVGPrinterOptions
+	EPUB       EPUBPrinterOptions
+	Screenshot ScreenshotPrinterOptions
+	Rasterize  RasterizePrinterOptions
+}
+
+// DefaultConvertOptions returns the default
+// options of every Printer.
+func DefaultConvertOptions(config conf.Config) ConvertOptions {
+	return ConvertOptions{
+		Chrome:     DefaultChromePrinterOptions(config),
+		Office:     DefaultOfficePrinterOptions(config),
+		Merge:      DefaultMergePrinterOptions(config),
+		SVG:        DefaultSVGPrinterOptions(),
+		EPUB:       DefaultEPUBPrinterOptions(config),
+		Screenshot: DefaultScreenshotPrinterOptions(),
+		Rasterize:  DefaultRasterizePrinterOptions(config),
+	}
+}
+
+/*
+Convert converts the given inputs to the given
+output format into destination, thanks to the
+Printer returned by NewPrinterFor.
+*/
+func Convert(logger xlog.Logger, inputs []string, outputFormat, destination string, opts ConvertOptions) error {
+	const op string = "printer.Convert"
+	resolver := func() error {
+		p, err := NewPrinterFor(logger, inputs, outputFormat, opts)
+		if err != nil {
+			return err
+		}
+		return p.Print(destination)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+NewPrinterFor returns the Printer able to convert
+the given inputs to the given output format,
+according to their kind:
+
+	a URL: the URL Printer or, for an image
+	output format, the screenshot Printer;
+	PDF files: the merge Printer or, for a
+	single PDF and the PNG output format, the
+	rasterize Printer;
+	Office documents: the Office Printer;
+	an SVG file: the SVG Printer;
+	an EPUB file: the EPUB Printer;
+	an HTML file, alongside its assets: the
+	HTML Printer or, if one of them is a
+	Markdown file, the Markdown Printer.
+
+If the combination of inputs and output format is
+not supported, returns a xerror.Error with
+xerror.InvalidCode.
+*/
+func NewPrinterFor(logger xlog.Logger, inputs []string, outputFormat string, opts ConvertOptions) (Printer, error) {
+	const op string = "printer.NewPrinterFor"
+	unsupported := xerror.Invalid(
+		op,
+		fmt.Sprintf("converting '%v' to '%s' is not supported", inputs, outputFormat),
+		nil,
+	)
+	if len(inputs) == 0 {
+		return nil, xerror.Invalid(op, "no input to convert", nil)
+	}
+	if len(inputs) == 1 && isURL(inputs[0]) {
+		switch outputFormat {
+		case PDFOutputFormat:
+			return NewURLPrinter(logger, inputs[0], opts.Chrome)
+		case PNGScreenshotFormat, JPEGScreenshotFormat:
+			screenshotOpts := opts.Screenshot
+			screenshotOpts.Format = outputFormat
+			return NewScreenshotPrinter(logger, inputs[0], opts.Chrome, screenshotOpts), nil
+		default:
+			return nil, unsupported
+		}
+	}
+	exts := make(map[string]int)
+	for _, input := range inputs {
+		exts[strings.ToLower(filepath.Ext(input))]++
+	}
+	if exts[".pdf"] == len(inputs) {
+		switch {
+		case outputFormat == PDFOutputFormat:
+			return NewMergePrinter(logger, inputs, opts.Merge), nil
+		case outputFormat == PNGScreenshotFormat && len(inputs) == 1:
+			return NewRasterizePrinter(logger, inputs[0], opts.Rasterize), nil
+		default:
+			return nil, unsupported
+		}
+	}
+	if outputFormat != PDFOutputFormat {
+		return nil, unsupported
+	}
+	if isOfficeDocuments(inputs) {
+		return NewOfficePrinter(logger, inputs, opts.Office), nil
+	}
+	if len(inputs) == 1 {
+		switch strings.ToLower(filepath.Ext(inputs[0])) {
+		case ".svg":
+			p, err := NewSVGPrinter(logger, inputs[0], opts.Chrome, opts.SVG)
+			if err != nil {
+				return nil, xerror.New(op, err)
+			}
+			return p, nil
+		case ".epub":
+			return NewEPUBPrinter(logger, inputs[0], opts.Chrome, opts.EPUB), nil
+		}
+	}
+	if exts[".html"] != 1 {
+		return nil, unsupported
+	}
+	var index string
+	for _, input := range inputs {
+		if strings.ToLower(filepath.Ext(input)) == ".html" {
+			index = input
+		}
+	}
+	if exts[".md"] == 0 {
+		return NewHTMLPrinter(logger, index, opts.Chrome), nil
+	}
+	p, err := NewMarkdownPrinter(logger, index, opts.Chrome)
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	return p, nil
+}
+
+func isURL(input string) bool {
+	return strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://")
+}
+
+func isOfficeDocuments(inputs []string) bool {
+	for _, input := range inputs {
+		if _, err := officeDocumentType(input); err != nil {
+			return false
+		}
+	}
+	return true
+}
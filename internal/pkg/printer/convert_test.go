@@ -0,0 +1,65 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestNewPrinterFor(t *testing.T) {
+	var (
+		logger xlog.Logger    = test.DebugLogger()
+		opts   ConvertOptions = DefaultConvertOptions(conf.DefaultConfig())
+	)
+	// a URL.
+	p, err := NewPrinterFor(logger, []string{"https://google.com"}, PDFOutputFormat, opts)
+	assert.Nil(t, err)
+	assert.IsType(t, chromePrinter{}, p)
+	p, err = NewPrinterFor(logger, []string{"https://google.com"}, JPEGScreenshotFormat, opts)
+	assert.Nil(t, err)
+	assert.IsType(t, screenshotPrinter{}, p)
+	assert.Equal(t, JPEGScreenshotFormat, p.(screenshotPrinter).opts.Format)
+	// PDF files.
+	p, err = NewPrinterFor(logger, []string{"/tmp/foo.pdf", "/tmp/bar.PDF"}, PDFOutputFormat, opts)
+	assert.Nil(t, err)
+	assert.IsType(t, mergePrinter{}, p)
+	p, err = NewPrinterFor(logger, []string{"/tmp/foo.pdf"}, PNGScreenshotFormat, opts)
+	assert.Nil(t, err)
+	assert.IsType(t, rasterizePrinter{}, p)
+	// Office documents.
+	p, err = NewPrinterFor(logger, []string{"/tmp/foo.docx", "/tmp/bar.xlsx"}, PDFOutputFormat, opts)
+	assert.Nil(t, err)
+	assert.IsType(t, officePrinter{}, p)
+	// an EPUB file.
+	p, err = NewPrinterFor(logger, []string{"/tmp/foo.epub"}, PDFOutputFormat, opts)
+	assert.Nil(t, err)
+	assert.IsType(t, epubPrinter{}, p)
+	// an HTML file alongside its assets.
+	p, err = NewPrinterFor(logger, []string{"/tmp/index.html", "/tmp/style.css"}, PDFOutputFormat, opts)
+	assert.Nil(t, err)
+	assert.IsType(t, chromePrinter{}, p)
+	assert.Equal(t, "file:///tmp/index.html", p.(chromePrinter).url)
+	// unsupported combinations.
+	unsupported := []struct {
+		inputs       []string
+		outputFormat string
+	}{
+		{nil, PDFOutputFormat},
+		{[]string{"https://google.com"}, "gif"},
+		{[]string{"/tmp/foo.pdf", "/tmp/bar.pdf"}, PNGScreenshotFormat},
+		{[]string{"/tmp/foo.pdf"}, JPEGScreenshotFormat},
+		{[]string{"/tmp/foo.docx"}, PNGScreenshotFormat},
+		{[]string{"/tmp/foo.docx", "/tmp/bar.pdf"}, PDFOutputFormat},
+		{[]string{"/tmp/foo.html", "/tmp/bar.html"}, PDFOutputFormat},
+		{[]string{"/tmp/foo.txt.gz"}, PDFOutputFormat},
+	}
+	for _, tt := range unsupported {
+		_, err = NewPrinterFor(logger, tt.inputs, tt.outputFormat, opts)
+		assert.NotNil(t, err)
+		assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	}
+}
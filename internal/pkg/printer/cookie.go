@@ -0,0 +1,109 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mafredri/cdp"
+	"github.com/mafredri/cdp/protocol/network"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+// Cookie is a cookie set in Google Chrome
+// before the page is loaded.
+type Cookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Domain   string `json:"domain"`
+	Path     string `json:"path"`
+	Secure   bool   `json:"secure"`
+	HTTPOnly bool   `json:"httpOnly"`
+}
+
+/*
+validateCookies returns a xerror.Error with
+xerror.InvalidCode if one of the given cookies
+has no name or a name or value which would
+break the Cookie header.
+*/
+func validateCookies(cookies []Cookie) error {
+	const op string = "printer.validateCookies"
+	for _, cookie := range cookies {
+		if cookie.Name == "" {
+			return xerror.Invalid(op, "cookie name should not be empty", nil)
+		}
+		if strings.ContainsAny(cookie.Name, "=;,\r\n ") || strings.ContainsAny(cookie.Value, ";\r\n") {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("cookie '%s' should not contain separators or line breaks", cookie.Name),
+				nil,
+			)
+		}
+	}
+	return nil
+}
+
+/*
+cookieParams converts the given cookies into
+Google Chrome cookies. A cookie without domain
+gets the host of the target URL, and a cookie
+without path gets "/".
+*/
+func cookieParams(cookies []Cookie, targetURL string) ([]network.CookieParam, error) {
+	const op string = "printer.cookieParams"
+	var host string
+	if u, err := url.Parse(targetURL); err == nil {
+		host = u.Hostname()
+	}
+	params := make([]network.CookieParam, len(cookies))
+	for i, cookie := range cookies {
+		domain := cookie.Domain
+		if domain == "" {
+			domain = host
+		}
+		if domain == "" {
+			return nil, xerror.Invalid(
+				op,
+				fmt.Sprintf("cookie '%s' requires a domain, as the URL '%s' has no host", cookie.Name, targetURL),
+				nil,
+			)
+		}
+		path := cookie.Path
+		if path == "" {
+			path = "/"
+		}
+		secure, httpOnly := cookie.Secure, cookie.HTTPOnly
+		params[i] = network.CookieParam{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   &domain,
+			Path:     &path,
+			Secure:   &secure,
+			HTTPOnly: &httpOnly,
+		}
+	}
+	return params, nil
+}
+
+/*
+setCookies sets the Cookies option in the browser
+context of the page before it is loaded, so that
+the document request already carries them.
+*/
+func (p chromePrinter) setCookies(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.setCookies"
+	p.logger.DebugfOp(op, "setting '%d' cookie(s)...", len(p.opts.Cookies))
+	resolver := func() error {
+		params, err := cookieParams(p.opts.Cookies, p.pageURL())
+		if err != nil {
+			return err
+		}
+		return client.Network.SetCookies(ctx, network.NewSetCookiesArgs(params))
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+func TestValidateCookies(t *testing.T) {
+	// should be OK.
+	cookies := []Cookie{{Name: "session", Value: "foo=bar"}}
+	assert.Nil(t, validateCookies(cookies))
+	assert.Nil(t, validateCookies(nil))
+	// should not be OK as the name is empty.
+	cookies = []Cookie{{Name: "", Value: "foo"}}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(validateCookies(cookies)))
+	// should not be OK as the name or
+	// the value contains a separator.
+	cookies = []Cookie{{Name: "foo=bar", Value: "foo"}}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(validateCookies(cookies)))
+	cookies = []Cookie{{Name: "session", Value: "foo; bar=baz"}}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(validateCookies(cookies)))
+}
+
+func TestCookieParams(t *testing.T) {
+	cookies := []Cookie{
+		{Name: "session", Value: "foo", HTTPOnly: true},
+		{Name: "tenant", Value: "bar", Domain: ".example.com", Path: "/app", Secure: true},
+	}
+	params, err := cookieParams(cookies, "https://www.example.com:8080/foo")
+	assert.Nil(t, err)
+	assert.Len(t, params, 2)
+	// should default to the host of the URL and "/".
+	assert.Equal(t, "session", params[0].Name)
+	assert.Equal(t, "www.example.com", *params[0].Domain)
+	assert.Equal(t, "/", *params[0].Path)
+	assert.True(t, *params[0].HTTPOnly)
+	assert.False(t, *params[0].Secure)
+	// should keep the given domain and path.
+	assert.Equal(t, ".example.com", *params[1].Domain)
+	assert.Equal(t, "/app", *params[1].Path)
+	assert.True(t, *params[1].Secure)
+	// should not be OK as the URL has no host.
+	_, err = cookieParams(cookies, "file:///tmp/index.html")
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should be OK as the cookie has a domain.
+	_, err = cookieParams(cookies[1:], "file:///tmp/index.html")
+	assert.Nil(t, err)
+}
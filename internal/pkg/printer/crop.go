@@ -0,0 +1,244 @@
+package printer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+/*
+CropRegion is the rectangle a page is cropped to,
+in points from the bottom-left corner of the page
+(i.e. the PDF coordinates of its MediaBox).
+*/
+type CropRegion struct {
+	Page   int     `json:"page"`
+	Left   float64 `json:"left"`
+	Bottom float64 `json:"bottom"`
+	Right  float64 `json:"right"`
+	Top    float64 `json:"top"`
+}
+
+// mediaBoxTolerance absorbs the rounding of
+// the MediaBox coordinates printed by pdfinfo.
+const mediaBoxTolerance float64 = 0.01
+
+/*
+validateCropRegions returns a xerror.Error with
+xerror.InvalidCode if the given crop regions are
+malformed, regardless of the PDF they apply to.
+*/
+func validateCropRegions(regions []CropRegion) error {
+	const op string = "printer.validateCropRegions"
+	pages := make(map[int]bool)
+	for _, region := range regions {
+		if region.Page < 1 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("crop region page should be >= 1, got '%d'", region.Page),
+				nil,
+			)
+		}
+		if pages[region.Page] {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("page '%d' has more than one crop region", region.Page),
+				nil,
+			)
+		}
+		pages[region.Page] = true
+		if region.Left >= region.Right || region.Bottom >= region.Top {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("crop region of page '%d' should have left < right and bottom < top", region.Page),
+				nil,
+			)
+		}
+	}
+	return nil
+}
+
+/*
+validateCropRegionsBounds returns a xerror.Error
+with xerror.InvalidCode if a crop region targets
+a page which does not exist or goes beyond the
+MediaBox of its page.
+*/
+func validateCropRegionsBounds(regions []CropRegion, mediaBoxes []CropRegion) error {
+	const op string = "printer.validateCropRegionsBounds"
+	for _, region := range regions {
+		if region.Page > len(mediaBoxes) {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("crop region page '%d' is beyond the last page '%d'", region.Page, len(mediaBoxes)),
+				nil,
+			)
+		}
+		box := mediaBoxes[region.Page-1]
+		if region.Left < box.Left-mediaBoxTolerance ||
+			region.Bottom < box.Bottom-mediaBoxTolerance ||
+			region.Right > box.Right+mediaBoxTolerance ||
+			region.Top > box.Top+mediaBoxTolerance {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf(
+					"crop region of page '%d' should be within its MediaBox [%g %g %g %g]",
+					region.Page, box.Left, box.Bottom, box.Right, box.Top,
+				),
+				nil,
+			)
+		}
+	}
+	return nil
+}
+
+/*
+mediaBoxes returns the MediaBox of each page of
+the PDF file located at fpath thanks to pdfinfo.
+*/
+func mediaBoxes(ctx context.Context, logger xlog.Logger, fpath string) ([]CropRegion, error) {
+	const (
+		op             string = "printer.mediaBoxes"
+		mediaBoxSuffix string = "MediaBox:"
+	)
+	resolver := func() ([]CropRegion, error) {
+		count, err := pageCount(ctx, logger, fpath)
+		if err != nil {
+			return nil, err
+		}
+		cmd := exec.CommandContext(ctx, "pdfinfo", "-box", "-f", "1", "-l", strconv.Itoa(count), fpath)
+		xexec.LogBeforeExecute(logger, cmd)
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, err
+		}
+		var boxes []CropRegion
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			// e.g. "Page    1 MediaBox:     0.00     0.00   595.28   841.89".
+			fields := strings.Fields(scanner.Text())
+			if len(fields) != 7 || fields[0] != "Page" || fields[2] != mediaBoxSuffix {
+				continue
+			}
+			var coordinates [4]float64
+			for i := range coordinates {
+				coordinates[i], err = strconv.ParseFloat(fields[i+3], 64)
+				if err != nil {
+					return nil, err
+				}
+			}
+			boxes = append(boxes, CropRegion{
+				Page:   len(boxes) + 1,
+				Left:   coordinates[0],
+				Bottom: coordinates[1],
+				Right:  coordinates[2],
+				Top:    coordinates[3],
+			})
+		}
+		if len(boxes) != count {
+			return nil, xerror.Invalid(
+				op,
+				fmt.Sprintf("unable to find the MediaBox of every page of '%s'", fpath),
+				nil,
+			)
+		}
+		return boxes, nil
+	}
+	result, err := resolver()
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+/*
+cropPages sets the CropBox of the pages of the
+PDF file located at fpath to the given regions.
+
+qpdf lists the page objects, whose CropBox
+entries are set before qpdf updates them.
+*/
+func cropPages(ctx context.Context, logger xlog.Logger, fpath string, regions []CropRegion) error {
+	const op string = "printer.cropPages"
+	logger.DebugfOp(op, "cropping '%d' page(s) of '%s'...", len(regions), fpath)
+	resolver := func() error {
+		if err := validateCropRegions(regions); err != nil {
+			return err
+		}
+		boxes, err := mediaBoxes(ctx, logger, fpath)
+		if err != nil {
+			return err
+		}
+		if err := validateCropRegionsBounds(regions, boxes); err != nil {
+			return err
+		}
+		trailer, err := readPDFObjects(ctx, logger, fpath, "trailer")
+		if err != nil {
+			return err
+		}
+		refs, err := cropPageRefs(trailer.pages, regions)
+		if err != nil {
+			return err
+		}
+		pages, err := readPDFObjects(ctx, logger, fpath, refs...)
+		if err != nil {
+			return err
+		}
+		return updatePDFObjects(ctx, logger, fpath, pages.header, setCropBoxes(pages, regions))
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+cropPageRefs returns the references of the page
+objects of the given regions, given the
+references of all the pages.
+*/
+func cropPageRefs(pages []string, regions []CropRegion) ([]string, error) {
+	const op string = "printer.cropPageRefs"
+	refs := make([]string, len(regions))
+	for i, region := range regions {
+		if region.Page < 1 || region.Page > len(pages) {
+			return nil, xerror.Invalid(
+				op,
+				fmt.Sprintf("unable to find page '%d'", region.Page),
+				nil,
+			)
+		}
+		refs[i] = pages[region.Page-1]
+	}
+	return refs, nil
+}
+
+/*
+setCropBoxes returns the dictionaries of the
+given page objects with their CropBox entry set
+to the given regions, by reference. The page
+objects should have been read thanks to
+cropPageRefs.
+*/
+func setCropBoxes(pages pdfObjects, regions []CropRegion) map[string]interface{} {
+	updates := make(map[string]interface{})
+	for _, region := range regions {
+		ref := pages.pages[region.Page-1]
+		dict := pages.dicts[ref]
+		dict["/CropBox"] = pdfRectangle(region)
+		updates[ref] = dict
+	}
+	return updates
+}
+
+func formatPDFNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
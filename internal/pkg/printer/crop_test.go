@@ -0,0 +1,119 @@
+package printer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestValidateCropRegions(t *testing.T) {
+	// should be OK.
+	regions := []CropRegion{
+		{Page: 1, Left: 9, Bottom: 9, Right: 603, Top: 783},
+		{Page: 3, Left: 0, Bottom: 0, Right: 100, Top: 100},
+	}
+	assert.Nil(t, validateCropRegions(regions))
+	assert.Nil(t, validateCropRegions(nil))
+	// should not be OK as the page is < 1.
+	regions = []CropRegion{{Page: 0, Left: 0, Bottom: 0, Right: 100, Top: 100}}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(validateCropRegions(regions)))
+	// should not be OK as a page has
+	// two crop regions.
+	regions = []CropRegion{
+		{Page: 1, Left: 0, Bottom: 0, Right: 100, Top: 100},
+		{Page: 1, Left: 10, Bottom: 10, Right: 100, Top: 100},
+	}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(validateCropRegions(regions)))
+	// should not be OK as the rectangle is empty.
+	regions = []CropRegion{{Page: 1, Left: 100, Bottom: 0, Right: 100, Top: 100}}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(validateCropRegions(regions)))
+	regions = []CropRegion{{Page: 1, Left: 0, Bottom: 100, Right: 100, Top: 0}}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(validateCropRegions(regions)))
+}
+
+func TestValidateCropRegionsBounds(t *testing.T) {
+	boxes := []CropRegion{
+		{Page: 1, Left: 0, Bottom: 0, Right: 612, Top: 792},
+		{Page: 2, Left: 0, Bottom: 0, Right: 595.28, Top: 841.89},
+	}
+	// should be OK.
+	regions := []CropRegion{{Page: 2, Left: 9, Bottom: 9, Right: 595.276, Top: 841.89}}
+	assert.Nil(t, validateCropRegionsBounds(regions, boxes))
+	// should not be OK as the page is
+	// beyond the last page.
+	regions = []CropRegion{{Page: 3, Left: 0, Bottom: 0, Right: 100, Top: 100}}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(validateCropRegionsBounds(regions, boxes)))
+	// should not be OK as the rectangle goes
+	// beyond the MediaBox.
+	regions = []CropRegion{{Page: 1, Left: 0, Bottom: 0, Right: 700, Top: 792}}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(validateCropRegionsBounds(regions, boxes)))
+	regions = []CropRegion{{Page: 1, Left: -10, Bottom: 0, Right: 100, Top: 100}}
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(validateCropRegionsBounds(regions, boxes)))
+}
+
+func TestCropPages(t *testing.T) {
+	logger := test.DebugLogger()
+	data, err := ioutil.ReadFile(test.MergeFpaths(t)[0])
+	assert.Nil(t, err)
+	fpath := test.GenerateDestination()
+	err = ioutil.WriteFile(fpath, data, 0644)
+	assert.Nil(t, err)
+	defer os.Remove(fpath) // nolint: errcheck
+	boxes, err := mediaBoxes(context.Background(), logger, fpath)
+	assert.Nil(t, err)
+	region := CropRegion{Page: 1, Left: boxes[0].Left + 10, Bottom: boxes[0].Bottom + 10, Right: boxes[0].Right - 10, Top: boxes[0].Top - 10}
+	err = cropPages(context.Background(), logger, fpath, []CropRegion{region})
+	assert.Nil(t, err)
+	out, err := exec.Command("pdfinfo", "-box", "-f", "1", "-l", "1", fpath).Output()
+	assert.Nil(t, err)
+	assert.Regexp(
+		t,
+		fmt.Sprintf(`Page +1 CropBox: +%.2f +%.2f +%.2f +%.2f`, region.Left, region.Bottom, region.Right, region.Top),
+		string(out),
+	)
+	// should not be OK as the page does not exist.
+	err = cropPages(context.Background(), logger, fpath, []CropRegion{{Page: len(boxes) + 1, Left: 0, Bottom: 0, Right: 10, Top: 10}})
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestCropPageRefs(t *testing.T) {
+	pages := []string{"3 0 R", "5 0 R"}
+	refs, err := cropPageRefs(pages, []CropRegion{{Page: 2}, {Page: 1}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"5 0 R", "3 0 R"}, refs)
+	// should not be OK as the page does not exist.
+	_, err = cropPageRefs(pages, []CropRegion{{Page: 3}})
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestSetCropBoxes(t *testing.T) {
+	pages := pdfObjects{
+		pages: []string{"3 0 R", "5 0 R", "7 0 R"},
+		dicts: map[string]pdfDict{
+			"3 0 R": {"/Type": json.RawMessage(`"/Page"`), "/MediaBox": json.RawMessage(`[0, 0, 612, 792]`)},
+			"7 0 R": {"/Type": json.RawMessage(`"/Page"`), "/CropBox": json.RawMessage(`[0, 0, 612, 792]`)},
+		},
+	}
+	regions := []CropRegion{
+		{Page: 1, Left: 9, Bottom: 9.5, Right: 603, Top: 783},
+		{Page: 3, Left: 0, Bottom: 0, Right: 306, Top: 792},
+	}
+	b, err := json.Marshal(setCropBoxes(pages, regions))
+	assert.Nil(t, err)
+	assert.JSONEq(
+		t,
+		`{
+			"3 0 R": {"/Type": "/Page", "/MediaBox": [0, 0, 612, 792], "/CropBox": [9, 9.5, 603, 783]},
+			"7 0 R": {"/Type": "/Page", "/CropBox": [0, 0, 306, 792]}
+		}`,
+		string(b),
+	)
+}
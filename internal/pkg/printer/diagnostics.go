@@ -0,0 +1,257 @@
+package printer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mafredri/cdp"
+	"github.com/mafredri/cdp/protocol/network"
+	"github.com/mafredri/cdp/protocol/runtime"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+/*
+DiagnosticsError wraps the error of a failed
+conversion with the directory where its
+diagnostics have been written.
+*/
+type DiagnosticsError struct {
+	DirPath string
+	err     error
+}
+
+func (e DiagnosticsError) Error() string {
+	return fmt.Sprintf("%s (diagnostics written to '%s')", e.err.Error(), e.DirPath)
+}
+
+// Unwrap returns the error of the failed conversion.
+func (e DiagnosticsError) Unwrap() error {
+	return e.err
+}
+
+/*
+DiagnosticsDestination returns the directory
+where the diagnostics of a failed conversion to
+the given destination are written.
+*/
+func DiagnosticsDestination(destination string) string {
+	ext := filepath.Ext(destination)
+	return fmt.Sprintf("%s_diagnostics", strings.TrimSuffix(destination, ext))
+}
+
+/*
+diagnosticsTimeout is the time given to Google
+Chrome for capturing the state of the page, as
+the conversion may have failed because its
+context.Context has timed out.
+*/
+const diagnosticsTimeout time.Duration = 5 * time.Second
+
+/*
+diagnostics records the console messages and the
+failed requests of a page while it is loaded.
+*/
+type diagnostics struct {
+	mu             sync.Mutex
+	console        []string
+	failedRequests []string
+	urls           map[network.RequestID]string
+	closers        []func() error
+}
+
+/*
+startDiagnostics starts recording the console
+messages and the failed requests of the page.
+The recording stops once closed.
+*/
+func startDiagnostics(client *cdp.Client) (*diagnostics, error) {
+	const op string = "printer.startDiagnostics"
+	d := &diagnostics{urls: make(map[network.RequestID]string)}
+	resolver := func() error {
+		/*
+			the streams outlive the context.Context of
+			the conversion, so that the page may still
+			be diagnosed once it has timed out.
+		*/
+		ctx := context.Background()
+		consoleAPICalled, err := client.Runtime.ConsoleAPICalled(ctx)
+		if err != nil {
+			return err
+		}
+		d.closers = append(d.closers, consoleAPICalled.Close)
+		exceptionThrown, err := client.Runtime.ExceptionThrown(ctx)
+		if err != nil {
+			return err
+		}
+		d.closers = append(d.closers, exceptionThrown.Close)
+		requestWillBeSent, err := client.Network.RequestWillBeSent(ctx)
+		if err != nil {
+			return err
+		}
+		d.closers = append(d.closers, requestWillBeSent.Close)
+		loadingFailed, err := client.Network.LoadingFailed(ctx)
+		if err != nil {
+			return err
+		}
+		d.closers = append(d.closers, loadingFailed.Close)
+		responseReceived, err := client.Network.ResponseReceived(ctx)
+		if err != nil {
+			return err
+		}
+		d.closers = append(d.closers, responseReceived.Close)
+		// each goroutine stops once its stream is closed.
+		go func() {
+			for {
+				ev, err := consoleAPICalled.Recv()
+				if err != nil {
+					return
+				}
+				d.record(&d.console, fmt.Sprintf("[%s] %s", ev.Type, consoleArgs(ev.Args)))
+			}
+		}()
+		go func() {
+			for {
+				ev, err := exceptionThrown.Recv()
+				if err != nil {
+					return
+				}
+				d.record(&d.console, fmt.Sprintf("[exception] %s", exceptionMessage(&ev.ExceptionDetails)))
+			}
+		}()
+		go func() {
+			for {
+				ev, err := requestWillBeSent.Recv()
+				if err != nil {
+					return
+				}
+				d.mu.Lock()
+				d.urls[ev.RequestID] = ev.Request.URL
+				d.mu.Unlock()
+			}
+		}()
+		go func() {
+			for {
+				ev, err := loadingFailed.Recv()
+				if err != nil {
+					return
+				}
+				d.mu.Lock()
+				URL := d.urls[ev.RequestID]
+				d.mu.Unlock()
+				d.record(&d.failedRequests, fmt.Sprintf("%s %s", URL, ev.ErrorText))
+			}
+		}()
+		go func() {
+			for {
+				ev, err := responseReceived.Recv()
+				if err != nil {
+					return
+				}
+				if ev.Response.Status < 400 {
+					continue
+				}
+				d.record(&d.failedRequests, fmt.Sprintf("%s HTTP status %d", ev.Response.URL, ev.Response.Status))
+			}
+		}()
+		return nil
+	}
+	if err := resolver(); err != nil {
+		d.close()
+		return nil, xerror.New(op, err)
+	}
+	return d, nil
+}
+
+func (d *diagnostics) record(lines *[]string, line string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	*lines = append(*lines, line)
+}
+
+func (d *diagnostics) close() {
+	for _, closer := range d.closers {
+		closer() // nolint: errcheck
+	}
+}
+
+/*
+write writes the diagnostics of the page into
+the given directory, alongside its current URL
+and a screenshot of its current state:
+
+	url.txt
+	console.log
+	failed_requests.log
+	screenshot.png
+
+Every artifact is captured on a best effort
+basis, as the page may not respond anymore.
+*/
+func (d *diagnostics) write(logger xlog.Logger, client *cdp.Client, dirPath string) error {
+	const op string = "printer.diagnostics.write"
+	ctx, cancel := context.WithTimeout(context.Background(), diagnosticsTimeout)
+	defer cancel()
+	resolver := func() error {
+		if err := os.MkdirAll(dirPath, 0755); err != nil {
+			return err
+		}
+		d.mu.Lock()
+		console := strings.Join(d.console, "\n")
+		failedRequests := strings.Join(d.failedRequests, "\n")
+		d.mu.Unlock()
+		files := map[string][]byte{
+			"console.log":         []byte(console),
+			"failed_requests.log": []byte(failedRequests),
+		}
+		history, err := client.Page.GetNavigationHistory(ctx)
+		if err != nil {
+			logger.DebugfOp(op, "unable to get the current URL: %s", err.Error())
+		} else if history.CurrentIndex >= 0 && history.CurrentIndex < len(history.Entries) {
+			files["url.txt"] = []byte(history.Entries[history.CurrentIndex].URL)
+		}
+		screenshot, err := client.Page.CaptureScreenshot(ctx, screenshotArgs(PNGScreenshotFormat, 100))
+		if err != nil {
+			logger.DebugfOp(op, "unable to capture a screenshot: %s", err.Error())
+		} else {
+			files["screenshot.png"] = screenshot.Data
+		}
+		for name, data := range files {
+			if err := ioutil.WriteFile(filepath.Join(dirPath, name), data, 0644); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+// consoleArgs returns the string representation
+// of the arguments of a console call.
+func consoleArgs(args []runtime.RemoteObject) string {
+	values := make([]string, len(args))
+	for i, arg := range args {
+		var value string
+		switch {
+		case arg.Type == "string" && json.Unmarshal(arg.Value, &value) == nil:
+			values[i] = value
+		case len(arg.Value) > 0:
+			values[i] = string(arg.Value)
+		case arg.Description != nil:
+			values[i] = *arg.Description
+		default:
+			values[i] = arg.Type
+		}
+	}
+	return strings.Join(values, " ")
+}
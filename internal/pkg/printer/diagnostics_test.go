@@ -0,0 +1,41 @@
+package printer
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mafredri/cdp/protocol/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+func TestDiagnosticsDestination(t *testing.T) {
+	assert.Equal(t, "/tmp/foo_diagnostics", DiagnosticsDestination("/tmp/foo.pdf"))
+	assert.Equal(t, "/tmp/foo_diagnostics", DiagnosticsDestination("/tmp/foo"))
+}
+
+func TestDiagnosticsError(t *testing.T) {
+	err := xerror.New("foo", &DiagnosticsError{
+		DirPath: "/tmp/foo_diagnostics",
+		err:     xerror.Timeout("bar", "context has timed out", nil),
+	})
+	// the wrapped error should keep its code and message.
+	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+	assert.Equal(t, "context has timed out", xerror.Message(err))
+	assert.Contains(t, err.Error(), "/tmp/foo_diagnostics")
+	var diagErr *DiagnosticsError
+	assert.True(t, errors.As(err, &diagErr))
+	assert.Equal(t, "/tmp/foo_diagnostics", diagErr.DirPath)
+}
+
+func TestConsoleArgs(t *testing.T) {
+	description := "Error: bar"
+	args := []runtime.RemoteObject{
+		{Type: "string", Value: json.RawMessage(`"foo \"baz\""`)},
+		{Type: "number", Value: json.RawMessage(`42`)},
+		{Type: "object", Description: &description},
+		{Type: "undefined"},
+	}
+	assert.Equal(t, `foo "baz" 42 Error: bar undefined`, consoleArgs(args))
+}
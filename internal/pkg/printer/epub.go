@@ -0,0 +1,321 @@
+package printer
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+type epubPrinter struct {
+	logger   xlog.Logger
+	fpath    string
+	opts     ChromePrinterOptions
+	epubOpts EPUBPrinterOptions
+}
+
+// EPUBPrinterOptions helps customizing the
+// EPUB Printer behaviour.
+type EPUBPrinterOptions struct {
+	PaperWidth      float64
+	PaperHeight     float64
+	HonorStylesheet bool
+}
+
+// DefaultEPUBPrinterOptions returns the default
+// EPUB Printer options.
+func DefaultEPUBPrinterOptions(config conf.Config) EPUBPrinterOptions {
+	opts := DefaultChromePrinterOptions(config)
+	return EPUBPrinterOptions{
+		PaperWidth:      opts.PaperWidth,
+		PaperHeight:     opts.PaperHeight,
+		HonorStylesheet: true,
+	}
+}
+
+/*
+NewEPUBPrinter returns a Printer which is able
+to convert an EPUB file to PDF.
+
+It unpacks the EPUB, converts each document of
+its spine (in reading order) thanks to Google
+Chrome and merges the results. If the EPUB's own
+stylesheet should not be honored, the stylesheets
+declared in its manifest are removed beforehand.
+*/
+func NewEPUBPrinter(logger xlog.Logger, fpath string, opts ChromePrinterOptions, epubOpts EPUBPrinterOptions) Printer {
+	return epubPrinter{
+		logger:   requestLogger(logger, opts.RequestID),
+		fpath:    fpath,
+		opts:     opts,
+		epubOpts: epubOpts,
+	}
+}
+
+func (p epubPrinter) Print(destination string) error {
+	const op string = "printer.epubPrinter.Print"
+	logOptions(p.logger, p.epubOpts)
+	ctx, cancel := withTimeout(p.logger, p.opts.WaitTimeout)
+	defer cancel()
+	resolver := func() error {
+		if err := p.epubOpts.validate(); err != nil {
+			return err
+		}
+		dirPath := filepath.Dir(destination)
+		epubDirPath := fmt.Sprintf("%s/%s", dirPath, xrand.Get())
+		defer os.RemoveAll(epubDirPath) // nolint: errcheck
+		p.logger.DebugfOp(op, "unpacking '%s'...", p.fpath)
+		if err := unzip(p.fpath, epubDirPath); err != nil {
+			return err
+		}
+		spine, err := epubSpine(epubDirPath, p.epubOpts.HonorStylesheet)
+		if err != nil {
+			return err
+		}
+		opts := p.opts
+		opts.PaperWidth = p.epubOpts.PaperWidth
+		opts.PaperHeight = p.epubOpts.PaperHeight
+		fpaths := make([]string, len(spine))
+		for i, fpath := range spine {
+			// each document shares what remains
+			// of the overall timeout.
+			if deadline, ok := ctx.Deadline(); ok {
+				opts.WaitTimeout = time.Until(deadline).Seconds() - opts.WaitDelay
+			}
+			tmpDest := fmt.Sprintf("%s/%d%s.pdf", dirPath, i, xrand.Get())
+			p.logger.DebugfOp(op, "converting '%s' to PDF...", fpath)
+			c := chromePrinter{
+				logger: p.logger,
+				url:    fmt.Sprintf("file://%s", fpath),
+				opts:   opts,
+			}
+			if err := c.Print(tmpDest); err != nil {
+				return err
+			}
+			fpaths[i] = tmpDest
+		}
+		if len(fpaths) == 1 {
+			p.logger.DebugOp(op, "only one PDF created, nothing to merge")
+			return os.Rename(fpaths[0], destination)
+		}
+		m := mergePrinter{
+			logger: p.logger,
+			ctx:    ctx,
+			fpaths: fpaths,
+			opts:   MergePrinterOptions{MetadataPolicy: FirstMetadataPolicy},
+		}
+		return m.Print(destination)
+	}
+	if err := resolver(); err != nil {
+		return mustHandleError(
+			ctx,
+			xerror.New(requestOp(op, p.opts.RequestID), err),
+		)
+	}
+	return nil
+}
+
+func (opts EPUBPrinterOptions) validate() error {
+	const op string = "printer.EPUBPrinterOptions.validate"
+	if opts.PaperWidth <= 0 || opts.PaperHeight <= 0 {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("paper size should be > '0', got '%.2f' x '%.2f'", opts.PaperWidth, opts.PaperHeight),
+			nil,
+		)
+	}
+	return nil
+}
+
+type epubContainer struct {
+	Rootfiles []struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type epubPackage struct {
+	Items []struct {
+		ID        string `xml:"id,attr"`
+		Href      string `xml:"href,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"manifest>item"`
+	Itemrefs []struct {
+		IDRef  string `xml:"idref,attr"`
+		Linear string `xml:"linear,attr"`
+	} `xml:"spine>itemref"`
+}
+
+/*
+epubSpine returns the paths of the documents of
+the spine of the EPUB unpacked in the given
+directory, in reading order. Non-linear documents
+are skipped.
+
+If honorStylesheet is false, it also removes the
+stylesheets declared in the manifest.
+*/
+func epubSpine(dirPath string, honorStylesheet bool) ([]string, error) {
+	const op string = "printer.epubSpine"
+	resolver := func() ([]string, error) {
+		var container epubContainer
+		if err := decodeXMLFile(fmt.Sprintf("%s/META-INF/container.xml", dirPath), &container); err != nil {
+			return nil, err
+		}
+		if len(container.Rootfiles) == 0 {
+			return nil, xerror.Invalid(op, "the EPUB does not declare a package document", nil)
+		}
+		opfPath := path.Clean(container.Rootfiles[0].FullPath)
+		var pkg epubPackage
+		if err := decodeXMLFile(fmt.Sprintf("%s/%s", dirPath, opfPath), &pkg); err != nil {
+			return nil, err
+		}
+		hrefs := make(map[string]string)
+		for _, item := range pkg.Items {
+			href, err := url.PathUnescape(item.Href)
+			if err != nil {
+				return nil, xerror.Invalid(op, fmt.Sprintf("invalid manifest item '%s'", item.Href), err)
+			}
+			fpath, err := securePath(dirPath, path.Join(path.Dir(opfPath), href))
+			if err != nil {
+				return nil, err
+			}
+			hrefs[item.ID] = fpath
+			if !honorStylesheet && item.MediaType == "text/css" {
+				if err := os.RemoveAll(fpath); err != nil {
+					return nil, err
+				}
+			}
+		}
+		var spine []string
+		for _, itemref := range pkg.Itemrefs {
+			if itemref.Linear == "no" {
+				continue
+			}
+			fpath, ok := hrefs[itemref.IDRef]
+			if !ok {
+				return nil, xerror.Invalid(
+					op,
+					fmt.Sprintf("spine item '%s' is not in the manifest", itemref.IDRef),
+					nil,
+				)
+			}
+			spine = append(spine, fpath)
+		}
+		if len(spine) == 0 {
+			return nil, xerror.Invalid(op, "the EPUB spine is empty", nil)
+		}
+		return spine, nil
+	}
+	result, err := resolver()
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+func decodeXMLFile(fpath string, v interface{}) error {
+	const op string = "printer.decodeXMLFile"
+	resolver := func() error {
+		f, err := os.Open(fpath)
+		if err != nil {
+			return xerror.Invalid(op, fmt.Sprintf("'%s' not found", filepath.Base(fpath)), err)
+		}
+		defer f.Close() // nolint: errcheck
+		if err := xml.NewDecoder(f).Decode(v); err != nil {
+			return xerror.Invalid(op, fmt.Sprintf("unable to parse '%s'", filepath.Base(fpath)), err)
+		}
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+unzip extracts the given archive into the
+given directory, which is created.
+*/
+func unzip(fpath, dirPath string) error {
+	const op string = "printer.unzip"
+	resolver := func() error {
+		r, err := zip.OpenReader(fpath)
+		if err != nil {
+			return xerror.Invalid(op, fmt.Sprintf("'%s' is not a valid archive", filepath.Base(fpath)), err)
+		}
+		defer r.Close() // nolint: errcheck
+		for _, f := range r.File {
+			dst, err := securePath(dirPath, f.Name)
+			if err != nil {
+				return err
+			}
+			if f.FileInfo().IsDir() {
+				if err := os.MkdirAll(dst, 0755); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := unzipFile(f, dst); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+func unzipFile(f *zip.File, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	in, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close() // nolint: errcheck
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close() // nolint: errcheck
+	_, err = io.Copy(out, in)
+	return err
+}
+
+/*
+securePath joins the given directory and relative
+name, and returns a xerror.Error with
+xerror.InvalidCode if the result escapes the
+directory.
+*/
+func securePath(dirPath, name string) (string, error) {
+	const op string = "printer.securePath"
+	fpath := filepath.Join(dirPath, filepath.FromSlash(name))
+	if !strings.HasPrefix(fpath, filepath.Clean(dirPath)+string(os.PathSeparator)) {
+		return "", xerror.Invalid(
+			op,
+			fmt.Sprintf("'%s' is outside of the archive", name),
+			nil,
+		)
+	}
+	return fpath, nil
+}
+
+// Compile-time checks to ensure type implements desired interfaces.
+var (
+	_ = Printer(new(epubPrinter))
+)
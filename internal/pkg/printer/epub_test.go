@@ -0,0 +1,86 @@
+package printer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestEPUBPrinter(t *testing.T) {
+	var (
+		logger   xlog.Logger = test.DebugLogger()
+		config   conf.Config = conf.DefaultConfig()
+		fpath    string      = test.EPUBFpaths(t)[0]
+		opts     ChromePrinterOptions
+		epubOpts EPUBPrinterOptions
+		dest     string
+		p        Printer
+		err      error
+	)
+	// default options.
+	opts = DefaultChromePrinterOptions(config)
+	epubOpts = DefaultEPUBPrinterOptions(config)
+	p = NewEPUBPrinter(logger, fpath, opts, epubOpts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options without the EPUB stylesheet.
+	epubOpts = DefaultEPUBPrinterOptions(config)
+	epubOpts.HonorStylesheet = false
+	p = NewEPUBPrinter(logger, fpath, opts, epubOpts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the paper size is invalid.
+	epubOpts = DefaultEPUBPrinterOptions(config)
+	epubOpts.PaperWidth = 0.0
+	p = NewEPUBPrinter(logger, fpath, opts, epubOpts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestEPUBSpine(t *testing.T) {
+	dirPath := fmt.Sprintf("/tmp/%s", xrand.Get())
+	defer os.RemoveAll(dirPath) // nolint: errcheck
+	assert.Nil(t, unzip(test.EPUBFpaths(t)[0], dirPath))
+	// should follow the spine and skip
+	// non-linear documents.
+	spine, err := epubSpine(dirPath, true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dirPath, "OEBPS/text/chapter 1.xhtml"),
+		filepath.Join(dirPath, "OEBPS/text/chapter2.xhtml"),
+	}, spine)
+	assert.FileExists(t, filepath.Join(dirPath, "OEBPS/style.css"))
+	// should remove the stylesheets.
+	_, err = epubSpine(dirPath, false)
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dirPath, "OEBPS/style.css"))
+	assert.True(t, os.IsNotExist(err))
+	// should not be OK as it is not an EPUB.
+	_, err = epubSpine(filepath.Join(dirPath, "OEBPS"), true)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestSecurePath(t *testing.T) {
+	fpath, err := securePath("/tmp/foo", "bar/baz.xhtml")
+	assert.Nil(t, err)
+	assert.Equal(t, "/tmp/foo/bar/baz.xhtml", fpath)
+	// should not be OK as it escapes the directory.
+	_, err = securePath("/tmp/foo", "../bar.xhtml")
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
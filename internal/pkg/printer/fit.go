@@ -0,0 +1,105 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/mafredri/cdp"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+const (
+	// minFitScale is the minimum scale
+	// Google Chrome prints with.
+	minFitScale float64 = 0.1
+	// maxFitIterations bounds the number of
+	// prints for fitting the page count.
+	maxFitIterations int = 8
+	// minFitScaleStep makes sure each print
+	// shrinks the content a bit more.
+	minFitScaleStep float64 = 0.02
+)
+
+/*
+fitScale returns the next scale to print with,
+as a print at the given scale resulted in count
+pages instead of the target.
+
+As the content reflows, its height shrinks about
+as much as its width: the number of pages varies
+with the square of the scale.
+*/
+func fitScale(scale float64, count, target int64) float64 {
+	next := scale * math.Sqrt(float64(target)/float64(count))
+	if scale-next < minFitScaleStep {
+		next = scale - minFitScaleStep
+	}
+	return math.Max(next, minFitScale)
+}
+
+/*
+printToPageCount prints the page to PDF, shrinking
+the scale until the PDF has at most ForcePageCount
+pages. The page is loaded once and printed again
+at each iteration.
+
+If the PDF still has too many pages at the minimum
+scale or after maxFitIterations prints, returns a
+xerror.Error with xerror.InvalidCode.
+*/
+func (p chromePrinter) printToPageCount(ctx context.Context, client *cdp.Client, destination string) error {
+	const op string = "printer.chromePrinter.printToPageCount"
+	resolver := func() error {
+		scale := p.opts.Scale
+		if scale == 0 && p.opts.ContentWidthPx > 0 {
+			landscape, err := p.landscape(ctx, client)
+			if err != nil {
+				return err
+			}
+			scale, err = p.opts.contentScale(landscape)
+			if err != nil {
+				return err
+			}
+		}
+		// the Google Chrome default.
+		if scale == 0 {
+			scale = 1
+		}
+		fit := p
+		var count int64
+		for i := 0; i < maxFitIterations; i++ {
+			if i > 0 {
+				p.logger.DebugfOp(op, "'%d' page(s) at scale '%.2f', shrinking...", count, fit.opts.Scale)
+				scale = fitScale(fit.opts.Scale, count, p.opts.ForcePageCount)
+			}
+			fit.opts.Scale = scale
+			if err := fit.printToPDF(ctx, client, destination); err != nil {
+				return err
+			}
+			pages, err := pageCount(ctx, p.logger, destination)
+			if err != nil {
+				return err
+			}
+			count = int64(pages)
+			if count <= p.opts.ForcePageCount {
+				return nil
+			}
+			if scale <= minFitScale {
+				break
+			}
+		}
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf(
+				"unable to fit the content into '%d' page(s): still '%d' page(s) at scale '%.2f'",
+				p.opts.ForcePageCount, count, fit.opts.Scale,
+			),
+			nil,
+		)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFitScale(t *testing.T) {
+	// 4 pages into 1: half the scale.
+	assert.InDelta(t, 0.5, fitScale(1, 4, 1), 0.001)
+	// should shrink at least by the minimum step.
+	assert.InDelta(t, 1-minFitScaleStep, fitScale(1, 101, 100), 0.001)
+	// should not go below the minimum scale.
+	assert.Equal(t, minFitScale, fitScale(0.2, 100, 1))
+}
@@ -0,0 +1,93 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+/*
+CheckFonts checks that the given font families
+are installed thanks to fc-list.
+
+If some font families are missing, returns a
+xerror.Error with xerror.InvalidCode listing them.
+*/
+func CheckFonts(families []string) error {
+	const op string = "printer.CheckFonts"
+	if len(families) == 0 {
+		return nil
+	}
+	resolver := func() error {
+		out, err := exec.Command("fc-list", ":", "family").Output()
+		if err != nil {
+			return err
+		}
+		missing := missingFontFamilies(string(out), families)
+		if len(missing) == 0 {
+			return nil
+		}
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("missing font families '%s'", strings.Join(missing, "', '")),
+			nil,
+		)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+missingFontFamilies returns the font families
+which are not listed in the given fc-list output.
+
+Each line of this output contains the comma
+separated names of a font family. Comparison
+is case insensitive.
+*/
+func missingFontFamilies(fcList string, families []string) []string {
+	installed := make(map[string]struct{})
+	for _, line := range strings.Split(fcList, "\n") {
+		for _, name := range strings.Split(line, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name != "" {
+				installed[name] = struct{}{}
+			}
+		}
+	}
+	var missing []string
+	for _, family := range families {
+		if _, ok := installed[strings.ToLower(strings.TrimSpace(family))]; !ok {
+			missing = append(missing, family)
+		}
+	}
+	return missing
+}
+
+/*
+subsetFonts rewrites the PDF file located at
+fpath so that its embedded fonts only contain
+the glyphs actually used by the document.
+*/
+func subsetFonts(ctx context.Context, logger xlog.Logger, fpath string) error {
+	const op string = "printer.subsetFonts"
+	logger.DebugOp(op, "subsetting embedded fonts...")
+	err := ghostscript(
+		ctx,
+		logger,
+		fpath,
+		"-dSubsetFonts=true",
+		"-dEmbedAllFonts=true",
+		"-dCompressFonts=true",
+	)
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
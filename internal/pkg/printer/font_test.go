@@ -0,0 +1,26 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestCheckFonts(t *testing.T) {
+	// no font families to check.
+	err := CheckFonts(nil)
+	assert.Nil(t, err)
+	// should not be OK as the font
+	// family is not installed.
+	err = CheckFonts([]string{"Gotenberg Does Not Exist"})
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestMissingFontFamilies(t *testing.T) {
+	const fcList string = "DejaVu Sans\nNoto Sans CJK JP,Noto Sans CJK JP Regular\nNoto Color Emoji\n"
+	assert.Nil(t, missingFontFamilies(fcList, []string{"dejavu sans", "Noto Sans CJK JP Regular"}))
+	assert.Equal(t, []string{"Foo"}, missingFontFamilies(fcList, []string{"Noto Color Emoji", "Foo"}))
+}
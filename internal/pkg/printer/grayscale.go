@@ -0,0 +1,29 @@
+package printer
+
+import (
+	"context"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+/*
+convertToGrayscale rewrites the PDF file located
+at fpath so that all its colors, including the
+ones of its images, are converted to gray.
+*/
+func convertToGrayscale(ctx context.Context, logger xlog.Logger, fpath string) error {
+	const op string = "printer.convertToGrayscale"
+	logger.DebugOp(op, "converting colors to grayscale...")
+	err := ghostscript(
+		ctx,
+		logger,
+		fpath,
+		"-sColorConversionStrategy=Gray",
+		"-dProcessColorModel=/DeviceGray",
+	)
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package printer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestGrayscale(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+	)
+	// an HTML conversion.
+	chromeOpts := DefaultChromePrinterOptions(config)
+	chromeOpts.Grayscale = true
+	p := NewHTMLPrinter(logger, test.HTMLFpaths(t)[0], chromeOpts)
+	dest := test.GenerateDestination()
+	err := p.Print(dest)
+	assert.Nil(t, err)
+	assertGrayscale(t, logger, config, dest)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// a merge.
+	mergeOpts := DefaultMergePrinterOptions(config)
+	mergeOpts.Grayscale = true
+	p = NewMergePrinter(logger, test.MergeFpaths(t), mergeOpts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	assertGrayscale(t, logger, config, dest)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+}
+
+// assertGrayscale rasterizes the given PDF and
+// checks that its pages have no color content.
+func assertGrayscale(t *testing.T, logger xlog.Logger, config conf.Config, fpath string) {
+	dest := rasterizeDestination()
+	defer removeRasterizedImages(dest)
+	err := NewRasterizePrinter(logger, fpath, DefaultRasterizePrinterOptions(config)).Print(dest)
+	assert.Nil(t, err)
+	img, err := readPNG(numberedDestination(dest, 1))
+	assert.Nil(t, err)
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			if r != g || g != b {
+				t.Fatalf("pixel (%d, %d) is not gray", x, y)
+			}
+		}
+	}
+}
@@ -11,7 +11,7 @@ import (
 func NewHTMLPrinter(logger xlog.Logger, fpath string, opts ChromePrinterOptions) Printer {
 	URL := fmt.Sprintf("file://%s", fpath)
 	return chromePrinter{
-		logger: logger,
+		logger: requestLogger(logger, opts.RequestID),
 		url:    URL,
 		opts:   opts,
 	}
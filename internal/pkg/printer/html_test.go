@@ -1,7 +1,12 @@
 package printer
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -38,6 +43,431 @@ func TestHTMLPrinter(t *testing.T) {
 	assert.Nil(t, err)
 	err = os.RemoveAll(dest)
 	assert.Nil(t, err)
+	// options with an ICC profile.
+	opts = DefaultChromePrinterOptions(config)
+	opts.ICCProfilePath = "/usr/share/color/icc/ghostscript/default_rgb.icc"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the ICC
+	// profile is invalid.
+	opts = DefaultChromePrinterOptions(config)
+	opts.ICCProfilePath = fpath
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options without exact colors.
+	opts = DefaultChromePrinterOptions(config)
+	opts.ExactColors = false
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with a wait for the ready state.
+	opts = DefaultChromePrinterOptions(config)
+	opts.WaitForReadyStateComplete = true
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with a document language.
+	opts = DefaultChromePrinterOptions(config)
+	opts.DocumentLanguage = "en-US"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with a document language alongside
+	// other rewrites, which should keep it.
+	opts = DefaultChromePrinterOptions(config)
+	opts.DocumentLanguage = "en-US"
+	opts.Grayscale = true
+	opts.SubsetFonts = true
+	opts.Title = "Foo"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	assert.Equal(t, "en-US", documentLanguage(t, logger, dest))
+	info, err := exec.Command("pdfinfo", dest).Output()
+	assert.Nil(t, err)
+	assert.Contains(t, string(info), "Foo")
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the document
+	// language is invalid.
+	opts = DefaultChromePrinterOptions(config)
+	opts.DocumentLanguage = "en_US"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// options with a maximum output size.
+	opts = DefaultChromePrinterOptions(config)
+	opts.MaxOutputBytes = 100 << 20
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the PDF exceeds
+	// the maximum output size, and no
+	// partial file is left behind.
+	opts = DefaultChromePrinterOptions(config)
+	opts.MaxOutputBytes = 1
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	_, err = os.Stat(dest)
+	assert.True(t, os.IsNotExist(err))
+	// options with a QR code.
+	opts = DefaultChromePrinterOptions(config)
+	opts.QRCode = true
+	opts.QRCodeContent = "https://gotenberg.dev"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with a print area.
+	opts = DefaultChromePrinterOptions(config)
+	opts.PrintAreaSelector = "body > *"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the print
+	// area selector does not match.
+	opts = DefaultChromePrinterOptions(config)
+	opts.PrintAreaSelector = "#gotenberg-does-not-exist"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// options with page ranges.
+	opts = DefaultChromePrinterOptions(config)
+	opts.PageRanges = "1"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the page
+	// ranges are beyond the last page.
+	opts = DefaultChromePrinterOptions(config)
+	opts.PageRanges = "1000"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// options with a scale.
+	opts = DefaultChromePrinterOptions(config)
+	opts.Scale = 0.5
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with the CSS page size.
+	pageSizeFpath := fmt.Sprintf("%s/page_size.html", os.TempDir())
+	err = ioutil.WriteFile(pageSizeFpath, []byte(`<html><head><style>@page { size: A4 landscape }</style></head><body>Gotenberg</body></html>`), 0644)
+	assert.Nil(t, err)
+	defer os.Remove(pageSizeFpath) // nolint: errcheck
+	opts = DefaultChromePrinterOptions(config)
+	opts.PreferCSSPageSize = true
+	p = NewHTMLPrinter(logger, pageSizeFpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	boxes, err := mediaBoxes(context.Background(), logger, dest)
+	assert.Nil(t, err)
+	if assert.Len(t, boxes, 1) {
+		// A4 landscape, i.e. 297x210mm.
+		assert.InDelta(t, 841.89, boxes[0].Right-boxes[0].Left, 1)
+		assert.InDelta(t, 595.28, boxes[0].Top-boxes[0].Bottom, 1)
+	}
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with the slowest resources logged.
+	opts = DefaultChromePrinterOptions(config)
+	opts.SlowResources = 3
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with an expression which never
+	// becomes true.
+	opts = DefaultChromePrinterOptions(config)
+	opts.WaitTimeout = 2.0
+	opts.WaitForExpression = "window.neverReady === true"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Contains(t, err.Error(), "never became true")
+	// options with a content width.
+	opts = DefaultChromePrinterOptions(config)
+	opts.ContentWidthPx = 1200
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with a text which never appears.
+	opts = DefaultChromePrinterOptions(config)
+	opts.WaitTimeout = 2.0
+	opts.WaitForText = "Gotenberg does not contain this"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+	assert.Contains(t, err.Error(), "Gotenberg does not contain this")
+	// options with a viewport.
+	opts = DefaultChromePrinterOptions(config)
+	opts.ViewportWidth = 1280
+	opts.ViewportHeight = 1024
+	opts.DeviceScaleFactor = 2.0
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with the screen media type.
+	opts = DefaultChromePrinterOptions(config)
+	opts.EmulatedMediaType = ScreenMediaType
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options capturing the console messages
+	// of a failed conversion.
+	opts = DefaultChromePrinterOptions(config)
+	opts.WaitTimeout = 2.0
+	opts.CaptureConsole = true
+	opts.WaitForExpression = "(console.error('gotenberg console'), false)"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	var consoleErr *ConsoleError
+	assert.True(t, errors.As(err, &consoleErr))
+	assert.Contains(t, consoleErr.Messages, "[error] gotenberg console")
+	// options with blocked resource types.
+	opts = DefaultChromePrinterOptions(config)
+	opts.BlockedResourceTypes = []string{"Image", "Font"}
+	opts.FailOnSubresourceError = true
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with custom CSS.
+	opts = DefaultChromePrinterOptions(config)
+	opts.CustomCSS = "body { margin: 0; } h1 { display: none; }"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with a forced page count.
+	opts = DefaultChromePrinterOptions(config)
+	opts.ForcePageCount = 1
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	count, err := pageCount(context.Background(), logger, dest)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options disabling the third-party JavaScript.
+	opts = DefaultChromePrinterOptions(config)
+	opts.DisableThirdPartyJS = true
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with custom JavaScript.
+	opts = DefaultChromePrinterOptions(config)
+	opts.CustomJavaScript = "new Promise((resolve) => setTimeout(() => { document.body.append('Gotenberg'); resolve(); }, 100))"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the custom
+	// JavaScript throws.
+	opts = DefaultChromePrinterOptions(config)
+	opts.CustomJavaScript = "throw new Error('Gotenberg')"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	assert.Contains(t, err.Error(), "Error: Gotenberg")
+	// options with metadata.
+	opts = DefaultChromePrinterOptions(config)
+	opts.Title = "Gotenberg"
+	opts.Author = "TheCodingMachine"
+	opts.Subject = "Tests"
+	opts.Keywords = "foo, bar"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options disabling JavaScript.
+	opts = DefaultChromePrinterOptions(config)
+	opts.DisableJavaScript = true
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with a timezone and a locale.
+	opts = DefaultChromePrinterOptions(config)
+	opts.Timezone = "Europe/Madrid"
+	opts.Locale = "es-ES"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with an invalid timezone.
+	opts = DefaultChromePrinterOptions(config)
+	opts.Timezone = "Europe/Nowhere"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// options with fonts subsetting.
+	opts = DefaultChromePrinterOptions(config)
+	opts.SubsetFonts = true
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with a CPU throttling rate.
+	opts = DefaultChromePrinterOptions(config)
+	opts.CPUThrottlingRate = 2.0
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the selector never
+	// appears, but diagnostics should be written.
+	opts = DefaultChromePrinterOptions(config)
+	opts.WaitTimeout = 2.0
+	opts.WaitForSelector = "#foo"
+	opts.DiagnosticsOnError = true
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Contains(t, err.Error(), DiagnosticsDestination(dest))
+	assert.FileExists(t, fmt.Sprintf("%s/console.log", DiagnosticsDestination(dest)))
+	err = os.RemoveAll(DiagnosticsDestination(dest))
+	assert.Nil(t, err)
+	// options with subresource errors failing
+	// the conversion, but none occurs.
+	opts = DefaultChromePrinterOptions(config)
+	opts.FailOnSubresourceError = true
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as an image
+	// of the page is missing.
+	missingFpath := fmt.Sprintf("%s/missing.html", os.TempDir())
+	err = ioutil.WriteFile(missingFpath, []byte(`<html><body><img src="missing.png"></body></html>`), 0644)
+	assert.Nil(t, err)
+	opts = DefaultChromePrinterOptions(config)
+	opts.FailOnSubresourceError = true
+	opts.SubresourceErrorTypes = []string{"Image"}
+	p = NewHTMLPrinter(logger, missingFpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	assert.Contains(t, xerror.Message(err), "missing.png")
+	// options with image errors only,
+	// but a missing stylesheet.
+	err = ioutil.WriteFile(missingFpath, []byte(`<html><head><link rel="stylesheet" href="missing.css"></head><body></body></html>`), 0644)
+	assert.Nil(t, err)
+	p = NewHTMLPrinter(logger, missingFpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(missingFpath)
+	assert.Nil(t, err)
+	// options with a PDF/X standard.
+	opts = DefaultChromePrinterOptions(config)
+	opts.PDFX = PDFX32002
+	opts.ICCProfilePath = "/usr/share/color/icc/ghostscript/default_cmyk.icc"
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as PDF/X
+	// requires an ICC profile.
+	opts = DefaultChromePrinterOptions(config)
+	opts.PDFX = PDFX32002
+	p = NewHTMLPrinter(logger, fpath, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
 	// should not be OK as context.Context
 	// should timeout.
 	opts = DefaultChromePrinterOptions(config)
@@ -0,0 +1,126 @@
+package printer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"regexp"
+	"text/template"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+/*
+maxDataURLLength is the maximum length of
+the URLs Google Chrome navigates to.
+*/
+const maxDataURLLength int = 2 * 1024 * 1024
+
+var (
+	// nolint: gochecknoglobals
+	headTagRegexp = regexp.MustCompile(`(?i)<head(\s[^>]*)?>`)
+	// nolint: gochecknoglobals
+	doctypeRegexp = regexp.MustCompile(`(?i)^\s*<!doctype[^>]*>`)
+)
+
+// HTMLStringPrinterOptions helps customizing
+// the HTML string Printer behaviour.
+type HTMLStringPrinterOptions struct {
+	// BaseURL, if any, is the absolute URL the
+	// relative URLs of the HTML are resolved
+	// against.
+	BaseURL string
+}
+
+// DefaultHTMLStringPrinterOptions returns the
+// default HTML string Printer options.
+func DefaultHTMLStringPrinterOptions() HTMLStringPrinterOptions {
+	return HTMLStringPrinterOptions{
+		BaseURL: "",
+	}
+}
+
+/*
+NewHTMLStringPrinter returns a Printer which is
+able to convert an HTML string to PDF, without
+writing it to disk nor serving it.
+
+The HTML is loaded as a data URL, so that the
+navigation and the waits are the same as for
+any other URL. As such a document has no
+location, its relative URLs are resolved
+against the BaseURL option (if any), which is
+also the URL of the page for the cookies, the
+authentication and the QR code.
+*/
+func NewHTMLStringPrinter(logger xlog.Logger, html string, opts ChromePrinterOptions, htmlOpts HTMLStringPrinterOptions) (Printer, error) {
+	const op string = "printer.NewHTMLStringPrinter"
+	resolver := func() (string, error) {
+		if err := htmlOpts.validate(); err != nil {
+			return "", err
+		}
+		if htmlOpts.BaseURL != "" {
+			html = withBaseURL(html, htmlOpts.BaseURL)
+		}
+		URL := htmlDataURL(html)
+		if len(URL) > maxDataURLLength {
+			return "", xerror.Invalid(
+				op,
+				fmt.Sprintf("the HTML is too large: its data URL should be at most '%d' characters, got '%d'", maxDataURLLength, len(URL)),
+				nil,
+			)
+		}
+		return URL, nil
+	}
+	URL, err := resolver()
+	if err != nil {
+		return chromePrinter{}, xerror.New(op, err)
+	}
+	return chromePrinter{
+		logger:  requestLogger(logger, opts.RequestID),
+		url:     URL,
+		baseURL: htmlOpts.BaseURL,
+		opts:    opts,
+	}, nil
+}
+
+func (opts HTMLStringPrinterOptions) validate() error {
+	const op string = "printer.HTMLStringPrinterOptions.validate"
+	if opts.BaseURL == "" {
+		return nil
+	}
+	u, err := url.Parse(opts.BaseURL)
+	if err != nil || !u.IsAbs() {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("base URL should be an absolute URL, got '%s'", opts.BaseURL),
+			err,
+		)
+	}
+	return nil
+}
+
+/*
+htmlDataURL returns the base64 encoded data
+URL of the given HTML.
+*/
+func htmlDataURL(html string) string {
+	return fmt.Sprintf("data:text/html;charset=utf-8;base64,%s", base64.StdEncoding.EncodeToString([]byte(html)))
+}
+
+/*
+withBaseURL adds a base element with the given
+URL at the start of the head of the HTML. If there
+is no head element, it is added after the doctype
+(if any), which must remain first.
+*/
+func withBaseURL(html, baseURL string) string {
+	base := fmt.Sprintf(`<base href="%s">`, template.HTMLEscapeString(baseURL))
+	for _, r := range []*regexp.Regexp{headTagRegexp, doctypeRegexp} {
+		if loc := r.FindStringIndex(html); loc != nil {
+			return html[:loc[1]] + base + html[loc[1]:]
+		}
+	}
+	return base + html
+}
@@ -0,0 +1,77 @@
+package printer
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestHTMLStringPrinter(t *testing.T) {
+	var (
+		logger   xlog.Logger = test.DebugLogger()
+		config   conf.Config = conf.DefaultConfig()
+		html     string      = "<html><head><title>Gutenberg</title></head><body><h1>Gutenberg</h1></body></html>"
+		opts     ChromePrinterOptions
+		htmlOpts HTMLStringPrinterOptions
+		dest     string
+		p        Printer
+		err      error
+	)
+	// default options.
+	opts = DefaultChromePrinterOptions(config)
+	htmlOpts = DefaultHTMLStringPrinterOptions()
+	p, err = NewHTMLStringPrinter(logger, html, opts, htmlOpts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with a base URL.
+	htmlOpts.BaseURL = "https://google.com/"
+	p, err = NewHTMLStringPrinter(logger, html, opts, htmlOpts)
+	assert.Nil(t, err)
+	assert.Equal(t, htmlOpts.BaseURL, p.(chromePrinter).pageURL())
+	// should not be OK as the base URL is relative.
+	htmlOpts.BaseURL = "/foo"
+	_, err = NewHTMLStringPrinter(logger, html, opts, htmlOpts)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as the HTML is too large.
+	htmlOpts = DefaultHTMLStringPrinterOptions()
+	_, err = NewHTMLStringPrinter(logger, strings.Repeat("a", maxDataURLLength), opts, htmlOpts)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestHTMLDataURL(t *testing.T) {
+	URL := htmlDataURL("<p>é</p>")
+	assert.True(t, strings.HasPrefix(URL, "data:text/html;charset=utf-8;base64,"))
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(URL, "data:text/html;charset=utf-8;base64,"))
+	assert.Nil(t, err)
+	assert.Equal(t, "<p>é</p>", string(data))
+}
+
+func TestWithBaseURL(t *testing.T) {
+	const base string = `<base href="https://foo.com/?a=1&amp;b=2">`
+	baseURL := "https://foo.com/?a=1&b=2"
+	// with a head element.
+	assert.Equal(
+		t,
+		"<!DOCTYPE html><html><HEAD lang=\"en\">"+base+"<title>foo</title></HEAD></html>",
+		withBaseURL("<!DOCTYPE html><html><HEAD lang=\"en\"><title>foo</title></HEAD></html>", baseURL),
+	)
+	// with a doctype but no head element.
+	assert.Equal(t, "<!doctype html>"+base+"<p>foo</p>", withBaseURL("<!doctype html><p>foo</p>", baseURL))
+	// with neither.
+	assert.Equal(t, base+"<p>foo</p>", withBaseURL("<p>foo</p>", baseURL))
+	// a header element is not a head element.
+	assert.Equal(t, base+"<header>foo</header>", withBaseURL("<header>foo</header>", baseURL))
+}
@@ -0,0 +1,77 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+/*
+validateICCProfile checks that the file located
+at fpath exists, is readable and looks like an
+ICC profile, i.e. its header contains the "acsp"
+signature.
+*/
+func validateICCProfile(fpath string) error {
+	const (
+		op               string = "printer.validateICCProfile"
+		headerSize       int    = 128
+		signatureOffset  int    = 36
+		profileSignature string = "acsp"
+	)
+	f, err := os.Open(fpath)
+	if err != nil {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("ICC profile '%s' is not readable", fpath),
+			err,
+		)
+	}
+	defer f.Close() // nolint: errcheck
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("ICC profile '%s' is too small to be valid", fpath),
+			err,
+		)
+	}
+	signature := string(header[signatureOffset : signatureOffset+len(profileSignature)])
+	if signature != profileSignature {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("'%s' is not an ICC profile", fpath),
+			nil,
+		)
+	}
+	return nil
+}
+
+/*
+embedICCProfile converts the colors of the PDF
+file located at fpath using the given ICC profile,
+which is then embedded into the PDF file.
+
+The ICC profile should have been validated
+beforehand.
+*/
+func embedICCProfile(ctx context.Context, logger xlog.Logger, fpath, iccProfilePath string) error {
+	const op string = "printer.embedICCProfile"
+	logger.DebugfOp(op, "embedding ICC profile '%s'...", iccProfilePath)
+	err := ghostscript(
+		ctx,
+		logger,
+		fpath,
+		"-dOverrideICC",
+		"-sColorConversionStrategy=UseDeviceIndependentColor",
+		fmt.Sprintf("-sOutputICCProfile=%s", iccProfilePath),
+	)
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package printer
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestValidateICCProfile(t *testing.T) {
+	writeTmp := func(content []byte) string {
+		f, err := ioutil.TempFile("", "*.icc")
+		assert.Nil(t, err)
+		_, err = f.Write(content)
+		assert.Nil(t, err)
+		assert.Nil(t, f.Close())
+		return f.Name()
+	}
+	// valid ICC profile header.
+	header := make([]byte, 128)
+	copy(header[36:], "acsp")
+	fpath := writeTmp(header)
+	err := validateICCProfile(fpath)
+	assert.Nil(t, err)
+	os.Remove(fpath)
+	// should not be OK as the file
+	// does not exist.
+	err = validateICCProfile(fpath)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as the file
+	// is too small.
+	fpath = writeTmp([]byte("acsp"))
+	err = validateICCProfile(fpath)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	os.Remove(fpath)
+	// should not be OK as the signature
+	// is missing.
+	fpath = writeTmp(make([]byte, 128))
+	err = validateICCProfile(fpath)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	os.Remove(fpath)
+}
@@ -0,0 +1,130 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+// indexEntry is a line of an index page.
+type indexEntry struct {
+	Name      string
+	StartPage int
+}
+
+// nolint: gochecknoglobals
+var indexTemplate = template.Must(template.New("index").Parse(`<!doctype html>
+<html>
+  <head>
+    <meta charset="utf-8">
+    <style>
+      body { font-family: sans-serif; }
+      table { width: 100%; border-collapse: collapse; }
+      td { padding: 4px 0; border-bottom: 1px solid #ddd; }
+      td:last-child { text-align: right; }
+    </style>
+  </head>
+  <body>
+    <h1>Index</h1>
+    <table>
+      {{ range . }}<tr><td>{{ .Name }}</td><td>{{ .StartPage }}</td></tr>
+      {{ end }}
+    </table>
+  </body>
+</html>
+`))
+
+/*
+indexEntries returns the index entries of the
+given PDF files, whose start pages account for
+the given number of index pages.
+*/
+func indexEntries(fpaths []string, pageCounts []int, indexPages int) []indexEntry {
+	entries := make([]indexEntry, len(fpaths))
+	startPage := indexPages + 1
+	for i, fpath := range fpaths {
+		entries[i] = indexEntry{
+			Name:      filepath.Base(fpath),
+			StartPage: startPage,
+		}
+		startPage += pageCounts[i]
+	}
+	return entries
+}
+
+/*
+printIndex renders an index page listing the
+given PDF files with their start pages thanks
+to Google Chrome, and returns its path.
+
+As the start pages depend on the number of pages
+of the index itself, it is rendered again until
+this number is stable (up to three times).
+*/
+func printIndex(ctx context.Context, logger xlog.Logger, fpaths []string, opts ChromePrinterOptions) (string, error) {
+	const (
+		op          string = "printer.printIndex"
+		maxAttempts int    = 3
+	)
+	resolver := func() (string, error) {
+		pageCounts := make([]int, len(fpaths))
+		for i, fpath := range fpaths {
+			count, err := pageCount(ctx, logger, fpath)
+			if err != nil {
+				return "", err
+			}
+			pageCounts[i] = count
+		}
+		dirPath := filepath.Dir(fpaths[0])
+		baseFilename := xrand.Get()
+		htmlPath := fmt.Sprintf("%s/%s.html", dirPath, baseFilename)
+		defer os.Remove(htmlPath) // nolint: errcheck
+		dest := fmt.Sprintf("%s/%s.pdf", dirPath, baseFilename)
+		indexPages := 1
+		for attempt := 1; ; attempt++ {
+			var buffer bytes.Buffer
+			if err := indexTemplate.Execute(&buffer, indexEntries(fpaths, pageCounts, indexPages)); err != nil {
+				return "", err
+			}
+			if err := ioutil.WriteFile(htmlPath, buffer.Bytes(), 0644); err != nil {
+				return "", err
+			}
+			// the index shares what remains
+			// of the merge timeout.
+			if deadline, ok := ctx.Deadline(); ok {
+				opts.WaitTimeout = time.Until(deadline).Seconds() - opts.WaitDelay
+			}
+			p := chromePrinter{
+				logger: logger,
+				url:    fmt.Sprintf("file://%s", htmlPath),
+				opts:   opts,
+			}
+			if err := p.Print(dest); err != nil {
+				return "", err
+			}
+			count, err := pageCount(ctx, logger, dest)
+			if err != nil {
+				return "", err
+			}
+			if count == indexPages || attempt >= maxAttempts {
+				return dest, nil
+			}
+			logger.DebugfOp(op, "index has '%d' page(s) instead of '%d', rendering it again...", count, indexPages)
+			indexPages = count
+		}
+	}
+	result, err := resolver()
+	if err != nil {
+		return "", xerror.New(op, err)
+	}
+	return result, nil
+}
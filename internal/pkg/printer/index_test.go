@@ -0,0 +1,20 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexEntries(t *testing.T) {
+	entries := indexEntries(
+		[]string{"/tmp/foo/a.pdf", "/tmp/foo/b.pdf", "/tmp/foo/c.pdf"},
+		[]int{3, 1, 2},
+		2,
+	)
+	assert.Equal(t, []indexEntry{
+		{Name: "a.pdf", StartPage: 3},
+		{Name: "b.pdf", StartPage: 6},
+		{Name: "c.pdf", StartPage: 7},
+	}, entries)
+}
@@ -0,0 +1,246 @@
+package printer
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"github.com/mafredri/cdp"
+	"github.com/mafredri/cdp/protocol/fetch"
+	"github.com/mafredri/cdp/protocol/network"
+	"github.com/mafredri/cdp/protocol/page"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+/*
+BlockableResourceTypes returns a slice containing
+all resource types the requests of which may be
+blocked. The main document cannot be.
+*/
+func BlockableResourceTypes() []string {
+	var types []string
+	for _, resourceType := range SubresourceTypes() {
+		if resourceType != string(network.ResourceTypeDocument) {
+			types = append(types, resourceType)
+		}
+	}
+	return types
+}
+
+/*
+noScriptsCSP is the Content-Security-Policy added
+to the documents of the third-party frames, so
+that their scripts, inline ones included, are not
+executed. As a document enforces each of its
+policies, it only ever restricts it further.
+*/
+const noScriptsCSP string = "script-src 'none'"
+
+/*
+interception handles the requests of the page
+paused by the Fetch domain: it fails those of the
+blocked resource types and continues the others.
+If credentials are given, it also answers the
+authentication challenges thanks to basicAuth.
+
+If the scripts of the third-party frames are
+disabled, it also pauses the responses of the
+documents, so that those of the third-party
+frames get the noScriptsCSP policy.
+*/
+type interception struct {
+	auth           *basicAuth
+	blocked        map[network.ResourceType]bool
+	targetURL      string
+	mainFrameID    page.FrameID
+	noThirdPartyJS bool
+	closers        []func() error
+}
+
+/*
+startInterception starts handling the requests of
+the page according to the BlockedResourceTypes,
+Username, Password and DisableThirdPartyJS options.
+The handling stops once closed.
+
+As the Fetch domain can only be enabled once per
+page, both the request blocking and the basic
+authentication go through it.
+*/
+func startInterception(ctx context.Context, logger xlog.Logger, client *cdp.Client, targetURL string, opts ChromePrinterOptions) (*interception, error) {
+	const op string = "printer.startInterception"
+	i := &interception{
+		blocked:        make(map[network.ResourceType]bool),
+		targetURL:      targetURL,
+		noThirdPartyJS: opts.DisableThirdPartyJS,
+	}
+	for _, resourceType := range opts.BlockedResourceTypes {
+		i.blocked[network.ResourceType(resourceType)] = true
+	}
+	if opts.Username != "" {
+		i.auth = newBasicAuth(targetURL, opts.Username, opts.Password)
+	}
+	resolver := func() error {
+		if i.noThirdPartyJS {
+			tree, err := client.Page.GetFrameTree(ctx)
+			if err != nil {
+				return err
+			}
+			i.mainFrameID = tree.FrameTree.Frame.ID
+		}
+		// see startDiagnostics.
+		streamCtx := context.Background()
+		requestPaused, err := client.Fetch.RequestPaused(streamCtx)
+		if err != nil {
+			return err
+		}
+		i.closers = append(i.closers, requestPaused.Close)
+		// each goroutine stops once its stream is closed.
+		go func() {
+			for {
+				ev, err := requestPaused.Recv()
+				if err != nil {
+					return
+				}
+				// only the documents are paused once responded.
+				if ev.ResponseStatusCode != nil || ev.ResponseErrorReason != nil {
+					i.handleDocumentResponse(streamCtx, logger, client, ev)
+					continue
+				}
+				if i.blocked[ev.ResourceType] {
+					logger.DebugfOp(op, "blocking %s '%s'", ev.ResourceType, ev.Request.URL)
+					args := fetch.NewFailRequestArgs(ev.RequestID, network.ErrorReasonBlockedByClient)
+					if err := client.Fetch.FailRequest(streamCtx, args); err != nil {
+						logger.DebugfOp(op, "blocking request '%s': %s", ev.Request.URL, err.Error())
+					}
+					continue
+				}
+				args := fetch.NewContinueRequestArgs(ev.RequestID)
+				if err := client.Fetch.ContinueRequest(streamCtx, args); err != nil {
+					logger.DebugfOp(op, "continuing request '%s': %s", ev.Request.URL, err.Error())
+				}
+			}
+		}()
+		var patterns []fetch.RequestPattern
+		if i.auth != nil {
+			// the authentication challenges may come from any request.
+			all := "*"
+			patterns = append(patterns, fetch.RequestPattern{URLPattern: &all})
+		} else {
+			// only pause the requests to block.
+			for _, resourceType := range opts.BlockedResourceTypes {
+				rt := network.ResourceType(resourceType)
+				patterns = append(patterns, fetch.RequestPattern{ResourceType: &rt})
+			}
+		}
+		if i.noThirdPartyJS {
+			document := network.ResourceTypeDocument
+			patterns = append(patterns, fetch.RequestPattern{
+				ResourceType: &document,
+				RequestStage: fetch.RequestStageResponse,
+			})
+		}
+		args := fetch.NewEnableArgs().SetPatterns(patterns)
+		if i.auth == nil {
+			return client.Fetch.Enable(ctx, args)
+		}
+		authRequired, err := client.Fetch.AuthRequired(streamCtx)
+		if err != nil {
+			return err
+		}
+		i.closers = append(i.closers, authRequired.Close)
+		go func() {
+			for {
+				ev, err := authRequired.Recv()
+				if err != nil {
+					return
+				}
+				response := i.auth.response(ev.RequestID, ev.Request.URL)
+				args := fetch.NewContinueWithAuthArgs(ev.RequestID, response)
+				if err := client.Fetch.ContinueWithAuth(streamCtx, args); err != nil {
+					logger.DebugfOp(op, "answering authentication challenge of '%s': %s", ev.Request.URL, err.Error())
+				}
+			}
+		}()
+		return client.Fetch.Enable(ctx, args.SetHandleAuthRequests(true))
+	}
+	if err := resolver(); err != nil {
+		i.close()
+		return nil, xerror.New(op, err)
+	}
+	return i, nil
+}
+
+/*
+handleDocumentResponse fulfills the response of a
+document loaded in a third-party frame with the
+noScriptsCSP policy, and continues the others.
+*/
+func (i *interception) handleDocumentResponse(ctx context.Context, logger xlog.Logger, client *cdp.Client, ev *fetch.RequestPausedReply) {
+	const op string = "printer.interception.handleDocumentResponse"
+	resolver := func() error {
+		status := 0
+		if ev.ResponseStatusCode != nil {
+			status = *ev.ResponseStatusCode
+		}
+		// the redirects and errors are left as is.
+		if status < 200 || status > 299 || !isThirdPartyFrame(i.mainFrameID, ev.FrameID, i.targetURL, ev.Request.URL) {
+			return client.Fetch.ContinueRequest(ctx, fetch.NewContinueRequestArgs(ev.RequestID))
+		}
+		logger.DebugfOp(op, "disabling the scripts of third-party frame '%s'", ev.Request.URL)
+		body, err := client.Fetch.GetResponseBody(ctx, fetch.NewGetResponseBodyArgs(ev.RequestID))
+		if err != nil {
+			return err
+		}
+		data := body.Body
+		if !body.Base64Encoded {
+			data = base64.StdEncoding.EncodeToString([]byte(body.Body))
+		}
+		args := fetch.NewFulfillRequestArgs(ev.RequestID, status, withNoScriptsCSP(ev.ResponseHeaders)).SetBody(data)
+		return client.Fetch.FulfillRequest(ctx, args)
+	}
+	if err := resolver(); err != nil {
+		logger.DebugfOp(op, "handling the response of '%s': %s", ev.Request.URL, err.Error())
+	}
+}
+
+/*
+isThirdPartyFrame returns true if the given request
+of a document comes from a frame other than the
+main one, with an origin other than the target URL.
+*/
+func isThirdPartyFrame(mainFrameID, frameID page.FrameID, targetURL, requestURL string) bool {
+	if frameID == mainFrameID {
+		return false
+	}
+	return origin(requestURL) != origin(targetURL)
+}
+
+// origin returns the scheme and the
+// host (with its port) of the given URL.
+func origin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host)
+}
+
+/*
+withNoScriptsCSP returns the given response headers
+with the noScriptsCSP policy as an additional
+Content-Security-Policy header.
+*/
+func withNoScriptsCSP(headers []fetch.HeaderEntry) []fetch.HeaderEntry {
+	result := make([]fetch.HeaderEntry, len(headers), len(headers)+1)
+	copy(result, headers)
+	return append(result, fetch.HeaderEntry{Name: "Content-Security-Policy", Value: noScriptsCSP})
+}
+
+func (i *interception) close() {
+	for _, closer := range i.closers {
+		closer() // nolint: errcheck
+	}
+}
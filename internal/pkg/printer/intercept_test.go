@@ -0,0 +1,44 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/mafredri/cdp/protocol/fetch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockableResourceTypes(t *testing.T) {
+	types := BlockableResourceTypes()
+	assert.Len(t, types, len(SubresourceTypes())-1)
+	assert.Contains(t, types, "Image")
+	assert.NotContains(t, types, "Document")
+}
+
+func TestIsBlockedResourceType(t *testing.T) {
+	assert.False(t, isBlockedResourceType("Image", nil))
+	assert.True(t, isBlockedResourceType("Image", []string{"Script", "Image"}))
+	assert.False(t, isBlockedResourceType("Stylesheet", []string{"Script", "Image"}))
+}
+
+func TestIsThirdPartyFrame(t *testing.T) {
+	const targetURL string = "https://example.com/index.html"
+	// the main frame is never a third-party one.
+	assert.False(t, isThirdPartyFrame("main", "main", targetURL, "https://ads.example.net/"))
+	// a frame with the same origin.
+	assert.False(t, isThirdPartyFrame("main", "child", targetURL, "https://EXAMPLE.com/widget.html"))
+	// frames with another origin.
+	assert.True(t, isThirdPartyFrame("main", "child", targetURL, "https://ads.example.net/"))
+	assert.True(t, isThirdPartyFrame("main", "child", targetURL, "http://example.com/widget.html"))
+	assert.True(t, isThirdPartyFrame("main", "child", "file:///tmp/index.html", "https://example.com/"))
+}
+
+func TestWithNoScriptsCSP(t *testing.T) {
+	headers := []fetch.HeaderEntry{
+		{Name: "Content-Type", Value: "text/html"},
+		{Name: "Content-Security-Policy", Value: "img-src *"},
+	}
+	result := withNoScriptsCSP(headers)
+	// the existing policy should be kept.
+	assert.Len(t, headers, 2)
+	assert.Equal(t, append(headers, fetch.HeaderEntry{Name: "Content-Security-Policy", Value: noScriptsCSP}), result)
+}
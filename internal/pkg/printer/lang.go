@@ -0,0 +1,69 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+// nolint: gochecknoglobals
+var documentLanguageRegexp = regexp.MustCompile(`^[A-Za-z]{2,8}(-[A-Za-z0-9]{1,8})*$`)
+
+/*
+validateDocumentLanguage checks that the given
+language looks like a BCP 47 tag, e.g. "en" or
+"en-US".
+*/
+func validateDocumentLanguage(lang string) error {
+	const op string = "printer.validateDocumentLanguage"
+	if !documentLanguageRegexp.MatchString(lang) {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("'%s' is not a valid BCP 47 language tag", lang),
+			nil,
+		)
+	}
+	return nil
+}
+
+/*
+setDocumentLanguage writes the given language
+into the /Lang entry of the catalog of the PDF
+file located at fpath.
+
+It edits the catalog in place, so that the
+other entries (e.g. the structure tree) are
+kept. Ghostscript rewrites would drop the entry,
+hence it should come after them.
+
+The language should have been validated
+beforehand.
+*/
+func setDocumentLanguage(ctx context.Context, logger xlog.Logger, fpath, lang string) error {
+	const op string = "printer.setDocumentLanguage"
+	resolver := func() error {
+		logger.DebugfOp(op, "setting document language '%s'...", lang)
+		trailer, err := readPDFObjects(ctx, logger, fpath, "trailer")
+		if err != nil {
+			return err
+		}
+		root, ok := pdfRef(trailer.dicts["trailer"]["/Root"])
+		if !ok {
+			return xerror.Internal(op, "unable to find the catalog", nil)
+		}
+		catalog, err := readPDFObjects(ctx, logger, fpath, root)
+		if err != nil {
+			return err
+		}
+		dict := catalog.dicts[root]
+		dict["/Lang"] = pdfString(lang)
+		return updatePDFObjects(ctx, logger, fpath, catalog.header, map[string]interface{}{root: dict})
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package printer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestValidateDocumentLanguage(t *testing.T) {
+	// valid language tags.
+	assert.Nil(t, validateDocumentLanguage("en"))
+	assert.Nil(t, validateDocumentLanguage("en-US"))
+	assert.Nil(t, validateDocumentLanguage("zh-Hant-TW"))
+	// should not be OK as the language
+	// tags are invalid.
+	for _, lang := range []string{"", "e", "en_US", "en-", "en-US) /Foo (", "toolonglanguage"} {
+		err := validateDocumentLanguage(lang)
+		test.AssertError(t, err)
+		assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	}
+}
+
+func TestSetDocumentLanguage(t *testing.T) {
+	logger := test.DebugLogger()
+	data, err := ioutil.ReadFile(test.MergeFpaths(t)[0])
+	assert.Nil(t, err)
+	fpath := test.GenerateDestination()
+	err = ioutil.WriteFile(fpath, data, 0644)
+	assert.Nil(t, err)
+	defer os.Remove(fpath) // nolint: errcheck
+	err = setDocumentLanguage(context.Background(), logger, fpath, "en-US")
+	assert.Nil(t, err)
+	assert.Equal(t, "en-US", documentLanguage(t, logger, fpath))
+	// should replace the previous language.
+	err = setDocumentLanguage(context.Background(), logger, fpath, "fr")
+	assert.Nil(t, err)
+	assert.Equal(t, "fr", documentLanguage(t, logger, fpath))
+}
+
+// documentLanguage returns the /Lang entry of
+// the catalog of the given PDF.
+func documentLanguage(t *testing.T, logger xlog.Logger, fpath string) string {
+	trailer, err := readPDFObjects(context.Background(), logger, fpath, "trailer")
+	assert.Nil(t, err)
+	root, ok := pdfRef(trailer.dicts["trailer"]["/Root"])
+	assert.True(t, ok)
+	catalog, err := readPDFObjects(context.Background(), logger, fpath, root)
+	assert.Nil(t, err)
+	var lang string
+	err = json.Unmarshal(catalog.dicts[root]["/Lang"], &lang)
+	assert.Nil(t, err)
+	return strings.TrimPrefix(lang, "u:")
+}
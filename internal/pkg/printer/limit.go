@@ -0,0 +1,169 @@
+package printer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+/*
+processLimiter bounds the number of conversions
+relying on external commands (pdftk, Ghostscript,
+unoconv) which run at the same time, independently
+from the Google Chrome conversions.
+
+A slot is held by a conversion, not by a process:
+the merge and Office conversions run their commands
+one after the other, while the rasterize workers,
+which run simultaneously, each hold their own slot.
+
+Waiting conversions acquire a slot in the order
+they have asked for one.
+*/
+type processLimiter struct {
+	mu      sync.Mutex
+	running int64
+	waiting []chan struct{}
+}
+
+// nolint: gochecknoglobals
+var (
+	// commandLimiter bounds the merge and
+	// rasterize conversions.
+	commandLimiter = &processLimiter{}
+	// officeLimiter bounds the Office conversions,
+	// which are especially memory-heavy.
+	officeLimiter = &processLimiter{}
+)
+
+/*
+acquire waits until less than limit conversions
+are running or until the given context.Context
+is done. A limit <= 0 means no limit.
+
+Each successful call should be followed by a
+call to release.
+*/
+func (l *processLimiter) acquire(ctx context.Context, limit int64) error {
+	const op string = "printer.processLimiter.acquire"
+	l.mu.Lock()
+	if limit <= 0 || l.running < limit {
+		l.running++
+		l.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	l.waiting = append(l.waiting, ready)
+	l.mu.Unlock()
+	select {
+	case <-ready:
+		// the slot has been handed over by release.
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		for i, c := range l.waiting {
+			if c == ready {
+				l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+				l.mu.Unlock()
+				return xerror.New(op, ctx.Err())
+			}
+		}
+		l.mu.Unlock()
+		// the slot has been handed over in the
+		// meantime: give it to the next one.
+		l.release()
+		return xerror.New(op, ctx.Err())
+	}
+}
+
+/*
+release frees a slot, handing it over to the
+first waiting conversion (if any).
+*/
+func (l *processLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.waiting) > 0 {
+		ready := l.waiting[0]
+		l.waiting = l.waiting[1:]
+		close(ready)
+		return
+	}
+	l.running--
+}
+
+/*
+LimitDecorator decorates Printers so that at most
+a given number of them print at the same time,
+e.g. to keep Google Chrome from running out of
+memory under load.
+
+A single decorator should serve every conversion
+it bounds, as they share its slots.
+*/
+type LimitDecorator struct {
+	logger  xlog.Logger
+	limiter *processLimiter
+	limit   int64
+}
+
+// NewLimitDecorator returns a LimitDecorator
+// with the given number of slots. A limit <= 0
+// means no limit.
+func NewLimitDecorator(logger xlog.Logger, limit int64) LimitDecorator {
+	return LimitDecorator{
+		logger:  logger,
+		limiter: &processLimiter{},
+		limit:   limit,
+	}
+}
+
+type limitedPrinter struct {
+	logger      xlog.Logger
+	printer     Printer
+	limiter     *processLimiter
+	limit       int64
+	waitTimeout float64
+}
+
+/*
+Decorate returns a Printer which waits for a slot
+of the decorator before calling the given Printer,
+and frees it once done.
+
+If no slot is free after waitTimeout seconds, its
+Print returns a xerror.Error with
+xerror.TimeoutCode. The wait does not count in the
+timeout of the given Printer.
+*/
+func (d LimitDecorator) Decorate(p Printer, waitTimeout float64) Printer {
+	return limitedPrinter{
+		logger:      d.logger,
+		printer:     p,
+		limiter:     d.limiter,
+		limit:       d.limit,
+		waitTimeout: waitTimeout,
+	}
+}
+
+func (p limitedPrinter) Print(destination string) error {
+	const op string = "printer.limitedPrinter.Print"
+	ctx, cancel := withTimeout(p.logger, p.waitTimeout)
+	defer cancel()
+	p.logger.DebugOp(op, "waiting for a slot...")
+	if err := p.limiter.acquire(ctx, p.limit); err != nil {
+		return mustHandleError(ctx, xerror.New(op, err))
+	}
+	defer p.limiter.release()
+	if err := p.printer.Print(destination); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+// Compile-time checks to ensure type implements desired interfaces.
+var (
+	_ = Printer(new(limitedPrinter))
+)
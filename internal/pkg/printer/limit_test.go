@@ -0,0 +1,81 @@
+package printer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestProcessLimiter(t *testing.T) {
+	l := &processLimiter{}
+	// no limit.
+	assert.Nil(t, l.acquire(context.Background(), 0))
+	l.release()
+	// two slots available.
+	assert.Nil(t, l.acquire(context.Background(), 2))
+	assert.Nil(t, l.acquire(context.Background(), 2))
+	// should not be OK as there is no
+	// slot available before the timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	err := l.acquire(ctx, 2)
+	cancel()
+	test.AssertError(t, err)
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	assert.Empty(t, l.waiting)
+	// a slot is handed over once released.
+	acquired := make(chan error)
+	go func() {
+		acquired <- l.acquire(context.Background(), 2)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	l.release()
+	assert.Nil(t, <-acquired)
+	l.release()
+	l.release()
+	assert.Equal(t, int64(0), l.running)
+}
+
+type blockingPrinter struct {
+	started chan struct{}
+	done    chan struct{}
+}
+
+func (p blockingPrinter) Print(destination string) error {
+	p.started <- struct{}{}
+	<-p.done
+	return nil
+}
+
+func TestLimitDecorator(t *testing.T) {
+	d := NewLimitDecorator(test.DebugLogger(), 1)
+	blocking := blockingPrinter{started: make(chan struct{}), done: make(chan struct{})}
+	printed := make(chan error)
+	go func() {
+		printed <- d.Decorate(blocking, 1.0).Print("foo.pdf")
+	}()
+	<-blocking.started
+	// should not be OK as the only
+	// slot is still in use.
+	err := d.Decorate(fakePrinter{}, 0.1).Print("foo.pdf")
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	// a slot is free once the first
+	// Printer is done.
+	close(blocking.done)
+	assert.Nil(t, <-printed)
+	dest := test.GenerateDestination()
+	defer os.Remove(dest) // nolint: errcheck
+	err = d.Decorate(fakePrinter{}, 0.1).Print(dest)
+	assert.Nil(t, err)
+	// no limit.
+	d = NewLimitDecorator(test.DebugLogger(), 0)
+	err = d.Decorate(fakePrinter{}, 0.1).Print(dest)
+	assert.Nil(t, err)
+}
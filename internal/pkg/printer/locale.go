@@ -0,0 +1,81 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mafredri/cdp"
+	"github.com/mafredri/cdp/protocol/emulation"
+	"github.com/mafredri/cdp/rpcc"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+/*
+setLocaleOverrideArgs are the arguments of the
+Emulation.setLocaleOverride command, which the
+CDP client does not provide.
+*/
+type setLocaleOverrideArgs struct {
+	Locale string `json:"locale"`
+}
+
+/*
+emulateLocale overrides the timezone and the
+locale of the page before it is loaded, so that
+its dates and numbers are formatted as in the
+given timezone and locale rather than the ones
+of the Google Chrome host.
+
+If Google Chrome rejects the timezone or the
+locale, returns a xerror.Error with
+xerror.InvalidCode.
+*/
+func (p chromePrinter) emulateLocale(ctx context.Context, client *cdp.Client, conn *rpcc.Conn) error {
+	const op string = "printer.chromePrinter.emulateLocale"
+	resolver := func() error {
+		if p.opts.Timezone != "" {
+			p.logger.DebugfOp(op, "emulating the timezone '%s'...", p.opts.Timezone)
+			args := emulation.NewSetTimezoneOverrideArgs(p.opts.Timezone)
+			if err := client.Emulation.SetTimezoneOverride(ctx, args); err != nil {
+				if isChromeResponseError(err) {
+					return xerror.Invalid(op, fmt.Sprintf("invalid timezone '%s'", p.opts.Timezone), err)
+				}
+				return err
+			}
+		}
+		if p.opts.Locale != "" {
+			p.logger.DebugfOp(op, "emulating the locale '%s'...", p.opts.Locale)
+			args := &setLocaleOverrideArgs{Locale: p.opts.Locale}
+			if err := rpcc.Invoke(ctx, "Emulation.setLocaleOverride", args, nil, conn); err != nil {
+				if isChromeResponseError(err) {
+					return xerror.Invalid(op, fmt.Sprintf("invalid locale '%s'", p.opts.Locale), err)
+				}
+				return err
+			}
+		}
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+isChromeResponseError returns true if the given
+error, or one of its causes, is an error Google
+Chrome answered a command with.
+*/
+func isChromeResponseError(err error) bool {
+	for err != nil {
+		if _, ok := err.(*rpcc.ResponseError); ok {
+			return true
+		}
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			return false
+		}
+		err = cause.Cause()
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package printer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mafredri/cdp/rpcc"
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+type causeError struct {
+	err error
+}
+
+func (e causeError) Error() string {
+	return e.err.Error()
+}
+
+func (e causeError) Cause() error {
+	return e.err
+}
+
+func TestIsChromeResponseError(t *testing.T) {
+	responseErr := &rpcc.ResponseError{Code: -32000, Message: "Invalid timezone ID"}
+	assert.True(t, isChromeResponseError(responseErr))
+	// the CDP client wraps the errors.
+	assert.True(t, isChromeResponseError(causeError{err: responseErr}))
+	// should not be OK as Google Chrome
+	// did not answer.
+	assert.False(t, isChromeResponseError(nil))
+	assert.False(t, isChromeResponseError(context.DeadlineExceeded))
+	assert.False(t, isChromeResponseError(causeError{err: xerror.New("foo", context.Canceled)}))
+}
@@ -18,6 +18,7 @@ import (
 // is able to convert Markdown files to PDF.
 func NewMarkdownPrinter(logger xlog.Logger, fpath string, opts ChromePrinterOptions) (Printer, error) {
 	const op string = "printer.NewMarkdownPrinter"
+	logger = requestLogger(logger, opts.RequestID)
 	resolver := func() (string, error) {
 		tmpl, err := template.
 			New(filepath.Base(fpath)).
@@ -0,0 +1,68 @@
+package printer
+
+import (
+	"context"
+
+	"github.com/mafredri/cdp"
+	"github.com/mafredri/cdp/protocol/emulation"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+const (
+	// PrintMediaType renders the page with
+	// its print styles (default).
+	PrintMediaType string = "print"
+	// ScreenMediaType renders the page with
+	// its screen styles.
+	ScreenMediaType string = "screen"
+)
+
+// MediaTypes returns a slice containing
+// all available emulated media types.
+func MediaTypes() []string {
+	return []string{
+		PrintMediaType,
+		ScreenMediaType,
+	}
+}
+
+/*
+emulateMedia makes the page match the media
+queries of the EmulatedMediaType option instead
+of the print ones, including while printing.
+*/
+func (p chromePrinter) emulateMedia(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.emulateMedia"
+	p.logger.DebugfOp(op, "emulating '%s' media type...", p.opts.EmulatedMediaType)
+	args := emulation.NewSetEmulatedMediaArgs(p.opts.EmulatedMediaType)
+	if err := client.Emulation.SetEmulatedMedia(ctx, args); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+triggerPrintMedia emulates the print media type
+right before printing. Google Chrome applies it
+while printing without dispatching the change
+events of the media queries, so that the pages
+listening to matchMedia('print') would not render
+their print variant otherwise.
+*/
+func (p chromePrinter) triggerPrintMedia(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.triggerPrintMedia"
+	p.logger.DebugOp(op, "emulating print media type...")
+	resolver := func() error {
+		args := emulation.NewSetEmulatedMediaArgs(PrintMediaType)
+		if err := client.Emulation.SetEmulatedMedia(ctx, args); err != nil {
+			return err
+		}
+		// the change events are dispatched
+		// during the next rendering steps.
+		return waitForLayout(ctx, client)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
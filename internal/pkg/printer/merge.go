@@ -2,41 +2,193 @@ package printer
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strings"
 
 	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
-	"github.com/thecodingmachine/gotenberg/internal/pkg/xcontext"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
 )
 
+const (
+	// PDFtkMergeTool merges the PDFs
+	// thanks to PDFtk.
+	PDFtkMergeTool string = "pdftk"
+	// QPDFMergeTool merges the PDFs
+	// thanks to qpdf.
+	QPDFMergeTool string = "qpdf"
+)
+
+// MergeTools returns a slice containing
+// all available merge tools.
+func MergeTools() []string {
+	return []string{
+		PDFtkMergeTool,
+		QPDFMergeTool,
+	}
+}
+
 type mergePrinter struct {
 	ctx    context.Context
 	logger xlog.Logger
 	fpaths []string
+	// ranges, if any, are the page ranges to
+	// keep of each PDF, "" meaning all pages.
+	ranges []string
 	opts   MergePrinterOptions
 }
 
+/*
+MergeInput is a PDF to merge with
+NewMergeInputsPrinter.
+*/
+type MergeInput struct {
+	Path string
+	// Ranges are the pages to keep, following
+	// the Google Chrome syntax, e.g. "1-3,5,8-".
+	// If empty, all pages are kept.
+	Ranges string
+}
+
 // MergePrinterOptions helps customizing the
 // merge Printer behaviour.
 type MergePrinterOptions struct {
-	WaitTimeout float64
+	WaitTimeout          float64
+	Permissions          []string
+	RequestID            string
+	SanitizeJS           bool
+	Index                *ChromePrinterOptions
+	DryRun               bool
+	NormalizePageSize    string
+	Interleave           bool
+	MaxProcesses         int64
+	Grayscale            bool
+	MetadataPolicy       string
+	Metadata             map[string]string
+	CropRegions          []CropRegion
+	AlternateBackgrounds bool
+	OddPageBackground    string
+	EvenPageBackground   string
+	WriteRateLimit       int64
+	Tool                 string
+	UserPassword         string
+	OwnerPassword        string
 }
 
 // DefaultMergePrinterOptions returns the default
 // merge Printer options.
 func DefaultMergePrinterOptions(config conf.Config) MergePrinterOptions {
 	return MergePrinterOptions{
-		WaitTimeout: config.DefaultWaitTimeout(),
+		WaitTimeout:          config.DefaultWaitTimeout(),
+		Permissions:          nil,
+		RequestID:            "",
+		SanitizeJS:           false,
+		Index:                nil,
+		DryRun:               false,
+		NormalizePageSize:    "",
+		Interleave:           false,
+		MaxProcesses:         config.MaximumConcurrentProcesses(),
+		Grayscale:            false,
+		MetadataPolicy:       FirstMetadataPolicy,
+		Metadata:             nil,
+		CropRegions:          nil,
+		AlternateBackgrounds: false,
+		OddPageBackground:    DefaultOddPageBackground,
+		EvenPageBackground:   DefaultEvenPageBackground,
+		WriteRateLimit:       config.OutputWriteRateLimit(),
+		Tool:                 PDFtkMergeTool,
+		UserPassword:         "",
+		OwnerPassword:        "",
 	}
 }
 
-// NewMergePrinter returns a Printer which
-// is able to merge PDFs.
+/*
+NewMergePrinter returns a Printer which
+is able to merge PDFs.
+
+If the Index option is set, an index page listing
+the PDFs with their start pages is rendered thanks
+to Google Chrome with these options, and prepended.
+
+If the Interleave option is set, the pages are
+interleaved instead of concatenated: page 1 of each
+PDF, then page 2 of each PDF, etc. Once a PDF has
+no more pages, the others go on.
+
+If the NormalizePageSize option is set, each
+page is scaled to fit this page size thanks to
+Ghostscript.
+
+If the Grayscale option is set, the colors are
+converted to gray thanks to Ghostscript.
+
+The MetadataPolicy option tells whether the
+resulting PDF keeps the metadata of the first
+PDF, has no metadata or has the Metadata option
+as metadata.
+
+If the AlternateBackgrounds option is set, the
+OddPageBackground and EvenPageBackground colors
+are alternately put under the pages thanks to
+Ghostscript and PDFtk.
+
+If the CropRegions option is set, the CropBox
+of the given pages of the resulting PDF is set
+thanks to qpdf, e.g. to trim them before printing.
+
+If the WriteRateLimit option is set, the resulting
+PDF is written to the destination at most at this
+number of bytes per second, e.g. to not saturate
+a network storage.
+
+The Tool option tells whether the PDFs are merged
+thanks to PDFtk or qpdf, which is lighter. The
+post-processing, including the encryption, still
+relies on the tools listed above.
+
+If the UserPassword option is set, the resulting
+PDF requires this password to be opened. If the
+OwnerPassword option is set, it requires this
+password to change its permissions, otherwise a
+random one is used. Both encrypt the PDF, denying
+every feature which is not listed in the
+Permissions option.
+
+If the DryRun option is set, the merge command is
+not executed: the Printer returns a
+xexec.DryRunError holding it instead.
+*/
 func NewMergePrinter(logger xlog.Logger, fpaths []string, opts MergePrinterOptions) Printer {
 	return mergePrinter{
-		logger: logger,
+		logger: requestLogger(logger, opts.RequestID),
+		fpaths: fpaths,
+		opts:   opts,
+	}
+}
+
+/*
+NewMergeInputsPrinter is like NewMergePrinter,
+but only keeps the given page ranges of each PDF,
+e.g. to assemble a report from selected pages of
+several PDFs.
+
+When interleaving, each PDF must have at most one
+page range, as the pages of each range are
+interleaved with the others.
+*/
+func NewMergeInputsPrinter(logger xlog.Logger, inputs []MergeInput, opts MergePrinterOptions) Printer {
+	fpaths := make([]string, len(inputs))
+	ranges := make([]string, len(inputs))
+	for i, input := range inputs {
+		fpaths[i] = input.Path
+		ranges[i] = input.Ranges
+	}
+	return mergePrinter{
+		logger: requestLogger(logger, opts.RequestID),
 		fpaths: fpaths,
+		ranges: ranges,
 		opts:   opts,
 	}
 }
@@ -50,26 +202,335 @@ func (p mergePrinter) Print(destination string) error {
 	*/
 	if p.ctx == nil {
 		logOptions(p.logger, p.opts)
-		ctx, cancel := xcontext.WithTimeout(p.logger, p.opts.WaitTimeout)
+		ctx, cancel := withTimeout(p.logger, p.opts.WaitTimeout)
 		defer cancel()
 		p.ctx = ctx
+		// an officePrinter has already acquired its own slot.
+		if err := commandLimiter.acquire(p.ctx, p.opts.MaxProcesses); err != nil {
+			return mustHandleError(
+				p.ctx,
+				xerror.New(requestOp(op, p.opts.RequestID), err),
+			)
+		}
+		defer commandLimiter.release()
+	}
+	if p.opts.DryRun {
+		p.ctx = xexec.WithDryRun(p.ctx)
 	}
 	p.logger.DebugfOp(op, "merging '%v'...", p.fpaths)
-	resolver := func() error {
-		var args []string
-		args = append(args, p.fpaths...)
-		args = append(args, "cat", "output", destination)
-		return xexec.Run(p.ctx, p.logger, "pdftk", args...)
-	}
-	if err := resolver(); err != nil {
-		return xcontext.MustHandleError(
+	resolver := func(destination string) error {
+		if err := validateMergeInputs(p.fpaths); err != nil {
+			return err
+		}
+		if err := validateMergeRanges(p.ranges, p.opts.Interleave); err != nil {
+			return err
+		}
+		encryption, err := encryptionArgs(p.opts.Permissions, p.opts.UserPassword, p.opts.OwnerPassword)
+		if err != nil {
+			return err
+		}
+		if err := validatePageSize(p.opts.NormalizePageSize); err != nil {
+			return err
+		}
+		if err := validateMetadata(p.opts.MetadataPolicy, p.opts.Metadata); err != nil {
+			return err
+		}
+		if err := validateCropRegions(p.opts.CropRegions); err != nil {
+			return err
+		}
+		if err := validateWriteRateLimit(p.opts.WriteRateLimit); err != nil {
+			return err
+		}
+		if err := validateMergeTool(p.opts.Tool); err != nil {
+			return err
+		}
+		if p.opts.AlternateBackgrounds {
+			if err := validateBackgroundColor(p.opts.OddPageBackground); err != nil {
+				return err
+			}
+			if err := validateBackgroundColor(p.opts.EvenPageBackground); err != nil {
+				return err
+			}
+		}
+		if p.opts.Interleave {
+			if err := validateInterleave(p.fpaths, p.opts); err != nil {
+				return err
+			}
+		}
+		fpaths, ranges := p.fpaths, p.ranges
+		if p.opts.Index != nil {
+			p.logger.DebugOp(op, "rendering the index page...")
+			index, err := printIndex(p.ctx, p.logger, p.fpaths, *p.opts.Index)
+			if err != nil {
+				return err
+			}
+			defer os.Remove(index) // nolint: errcheck
+			fpaths = append([]string{index}, fpaths...)
+			if ranges != nil {
+				ranges = append([]string{""}, ranges...)
+			}
+		}
+		if p.opts.Tool == QPDFMergeTool {
+			if err := xexec.Run(p.ctx, p.logger, "qpdf", qpdfMergeArgs(fpaths, ranges, destination)...); err != nil {
+				return err
+			}
+		} else {
+			args := mergeArgs(fpaths, ranges, p.opts.Interleave)
+			args = append(args, "output", destination)
+			if !p.opts.SanitizeJS && p.opts.NormalizePageSize == "" && !p.opts.Grayscale && p.opts.MetadataPolicy == FirstMetadataPolicy && len(p.opts.CropRegions) == 0 && !p.opts.AlternateBackgrounds {
+				args = append(args, encryption...)
+				return xexec.Run(p.ctx, p.logger, "pdftk", args...)
+			}
+			if err := xexec.Run(p.ctx, p.logger, "pdftk", args...); err != nil {
+				return err
+			}
+		}
+		if p.opts.NormalizePageSize != "" {
+			p.logger.DebugfOp(op, "normalizing the page size to '%s'...", p.opts.NormalizePageSize)
+			if err := normalizePageSize(p.ctx, p.logger, destination, p.opts.NormalizePageSize); err != nil {
+				return err
+			}
+		}
+		if p.opts.AlternateBackgrounds {
+			err := alternateBackgrounds(p.ctx, p.logger, destination, p.opts.OddPageBackground, p.opts.EvenPageBackground)
+			if err != nil {
+				return err
+			}
+		}
+		if p.opts.Grayscale {
+			if err := convertToGrayscale(p.ctx, p.logger, destination); err != nil {
+				return err
+			}
+		}
+		if p.opts.SanitizeJS {
+			if err := sanitizeJS(p.ctx, p.logger, destination); err != nil {
+				return err
+			}
+		}
+		// Ghostscript does not keep the CropBox.
+		if len(p.opts.CropRegions) > 0 {
+			if err := cropPages(p.ctx, p.logger, destination, p.opts.CropRegions); err != nil {
+				return err
+			}
+		}
+		// Ghostscript rewrites some metadata.
+		if err := applyMetadata(p.ctx, p.logger, destination, p.opts.MetadataPolicy, p.opts.Metadata); err != nil {
+			return err
+		}
+		// neither Ghostscript nor qpdf keep the encryption,
+		// so it happens once the PDF has been post-processed.
+		return encrypt(p.ctx, p.logger, destination, encryption)
+	}
+	if err := withRateLimit(p.ctx, destination, p.opts.WriteRateLimit, resolver); err != nil {
+		return mustHandleError(
 			p.ctx,
-			xerror.New(op, err),
+			xerror.New(requestOp(op, p.opts.RequestID), err),
 		)
 	}
 	return nil
 }
 
+/*
+mergeArgs returns the PDFtk arguments for either
+concatenating or interleaving the given PDFs. If
+ranges are given, only these pages are kept.
+*/
+func mergeArgs(fpaths, ranges []string, interleave bool) []string {
+	var args []string
+	if !interleave && !hasMergeRanges(ranges) {
+		args = append(args, fpaths...)
+		return append(args, "cat")
+	}
+	// shuffle and page ranges require input handles.
+	handles := make([]string, len(fpaths))
+	for i, fpath := range fpaths {
+		handles[i] = pdftkHandle(i)
+		args = append(args, fmt.Sprintf("%s=%s", handles[i], fpath))
+	}
+	if interleave {
+		args = append(args, "shuffle")
+	} else {
+		args = append(args, "cat")
+	}
+	for i, handle := range handles {
+		if len(ranges) == 0 || ranges[i] == "" {
+			args = append(args, handle)
+			continue
+		}
+		for _, part := range strings.Split(ranges[i], ",") {
+			first, last := pageRangeBounds(part)
+			if last == "" {
+				last = "end"
+			}
+			args = append(args, handle+mergePageRange(first, last))
+		}
+	}
+	return args
+}
+
+/*
+qpdfMergeArgs returns the qpdf arguments for
+concatenating the given PDFs. The first PDF is
+also the primary input, so that the result keeps
+its metadata, as PDFtk does. If ranges are given,
+only these pages are kept.
+*/
+func qpdfMergeArgs(fpaths, ranges []string, destination string) []string {
+	args := []string{fpaths[0], "--pages"}
+	for i, fpath := range fpaths {
+		args = append(args, fpath)
+		if len(ranges) == 0 || ranges[i] == "" {
+			continue
+		}
+		parts := strings.Split(ranges[i], ",")
+		for j, part := range parts {
+			first, last := pageRangeBounds(part)
+			if last == "" {
+				last = "z"
+			}
+			parts[j] = mergePageRange(first, last)
+		}
+		args = append(args, strings.Join(parts, ","))
+	}
+	return append(args, "--", destination)
+}
+
+/*
+mergePageRange returns the first page if the
+range has a single page, or "<first>-<last>"
+otherwise.
+*/
+func mergePageRange(first, last string) string {
+	if first == last {
+		return first
+	}
+	return fmt.Sprintf("%s-%s", first, last)
+}
+
+/*
+pageRangeBounds returns the first and the last
+pages of a valid page range, the last page being
+empty if the range goes until the end.
+*/
+func pageRangeBounds(part string) (string, string) {
+	matches := pageRangeRegexp.FindStringSubmatch(strings.TrimSpace(part))
+	first := matches[1]
+	if first == "" {
+		first = "1"
+	}
+	if matches[2] == "" {
+		return first, first
+	}
+	return first, matches[3]
+}
+
+func hasMergeRanges(ranges []string) bool {
+	for _, r := range ranges {
+		if r != "" {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+validateMergeInputs returns a xerror.Error with
+xerror.InvalidCode if there is no PDF to merge or
+if one of them is not a regular file, as the
+merge tools fail with opaque errors otherwise.
+*/
+func validateMergeInputs(fpaths []string) error {
+	const op string = "printer.validateMergeInputs"
+	if len(fpaths) == 0 {
+		return xerror.Invalid(op, "no PDF to merge", nil)
+	}
+	for _, fpath := range fpaths {
+		info, err := os.Stat(fpath)
+		if err != nil {
+			return xerror.Invalid(op, fmt.Sprintf("unable to read the PDF '%s'", fpath), err)
+		}
+		if !info.Mode().IsRegular() {
+			return xerror.Invalid(op, fmt.Sprintf("'%s' is not a PDF file", fpath), nil)
+		}
+	}
+	return nil
+}
+
+/*
+validateMergeRanges returns a xerror.Error with
+xerror.InvalidCode if one of the given page ranges
+is invalid, or has several parts while the PDFs
+are interleaved.
+*/
+func validateMergeRanges(ranges []string, interleave bool) error {
+	const op string = "printer.validateMergeRanges"
+	for _, r := range ranges {
+		if r == "" {
+			continue
+		}
+		if err := validatePageRanges(r); err != nil {
+			return xerror.New(op, err)
+		}
+		// each range would be interleaved with the others.
+		if interleave && strings.Contains(r, ",") {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("'%s' should be a single page range when interleaving", r),
+				nil,
+			)
+		}
+	}
+	return nil
+}
+
+/*
+validateMergeTool returns a xerror.Error with
+xerror.InvalidCode if the given tool is not one
+of MergeTools. An empty tool stands for
+PDFtkMergeTool.
+*/
+func validateMergeTool(tool string) error {
+	const op string = "printer.validateMergeTool"
+	if tool == "" {
+		return nil
+	}
+	for _, t := range MergeTools() {
+		if tool == t {
+			return nil
+		}
+	}
+	return xerror.Invalid(
+		op,
+		fmt.Sprintf("merge tool should be one of '%v', got '%s'", MergeTools(), tool),
+		nil,
+	)
+}
+
+/*
+validateInterleave returns a xerror.Error with
+xerror.InvalidCode if the given PDFs cannot be
+interleaved with the given options.
+*/
+func validateInterleave(fpaths []string, opts MergePrinterOptions) error {
+	const op string = "printer.validateInterleave"
+	if len(fpaths) < 2 {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("interleaving requires at least '2' PDFs, got '%d'", len(fpaths)),
+			nil,
+		)
+	}
+	// the index page would be interleaved too.
+	if opts.Index != nil {
+		return xerror.Invalid(op, "an index page cannot be prepended when interleaving", nil)
+	}
+	// the pages are shuffled thanks to PDFtk.
+	if opts.Tool == QPDFMergeTool {
+		return xerror.Invalid(op, "the PDFs cannot be interleaved with qpdf", nil)
+	}
+	return nil
+}
+
 // Compile-time checks to ensure type implements desired interfaces.
 var (
 	_ = Printer(new(mergePrinter))
@@ -1,12 +1,16 @@
 package printer
 
 import (
+	"context"
+	"errors"
+	"io/ioutil"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
 	"github.com/thecodingmachine/gotenberg/test"
 )
@@ -29,6 +33,239 @@ func TestMergePrinter(t *testing.T) {
 	assert.Nil(t, err)
 	err = os.RemoveAll(dest)
 	assert.Nil(t, err)
+	// options with permissions.
+	opts = DefaultMergePrinterOptions(config)
+	opts.Permissions = []string{PrintingPermission, ScreenReadersPermission}
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as a permission
+	// is invalid.
+	opts = DefaultMergePrinterOptions(config)
+	opts.Permissions = []string{"foo"}
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as a permission
+	// is invalid, the error should contain
+	// the request ID.
+	opts = DefaultMergePrinterOptions(config)
+	opts.Permissions = []string{"foo"}
+	opts.RequestID = "bar"
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Contains(t, xerror.Op(err), "[reqID=bar]")
+	// options with cleared metadata.
+	opts = DefaultMergePrinterOptions(config)
+	opts.MetadataPolicy = ClearMetadataPolicy
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with explicit metadata.
+	opts = DefaultMergePrinterOptions(config)
+	opts.MetadataPolicy = ExplicitMetadataPolicy
+	opts.Metadata = map[string]string{"Title": "Gutenberg"}
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with JavaScript sanitizing.
+	opts = DefaultMergePrinterOptions(config)
+	opts.SanitizeJS = true
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with JavaScript sanitizing
+	// and permissions.
+	opts = DefaultMergePrinterOptions(config)
+	opts.SanitizeJS = true
+	opts.Permissions = []string{PrintingPermission}
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with an index page.
+	opts = DefaultMergePrinterOptions(config)
+	indexOpts := DefaultChromePrinterOptions(config)
+	opts.Index = &indexOpts
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not merge as it is a dry run,
+	// but return the PDFtk command.
+	opts = DefaultMergePrinterOptions(config)
+	opts.DryRun = true
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	command, ok := xexec.DryRunCommand(err)
+	assert.True(t, ok)
+	assert.Contains(t, command, "pdftk")
+	assert.Contains(t, command, dest)
+	_, err = os.Stat(dest)
+	assert.True(t, os.IsNotExist(err))
+	// options with a normalized page size.
+	opts = DefaultMergePrinterOptions(config)
+	opts.NormalizePageSize = A4PageSize
+	opts.Permissions = []string{PrintingPermission}
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the page
+	// size is invalid.
+	opts = DefaultMergePrinterOptions(config)
+	opts.NormalizePageSize = "foo"
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with crop regions.
+	opts = DefaultMergePrinterOptions(config)
+	opts.CropRegions = []CropRegion{{Page: 1, Left: 9, Bottom: 9, Right: 300, Top: 400}}
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the crop region
+	// is beyond the MediaBox of the page.
+	opts = DefaultMergePrinterOptions(config)
+	opts.CropRegions = []CropRegion{{Page: 1, Left: 0, Bottom: 0, Right: 10000, Top: 10000}}
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with alternating backgrounds.
+	opts = DefaultMergePrinterOptions(config)
+	opts.AlternateBackgrounds = true
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as a background
+	// color is invalid.
+	opts = DefaultMergePrinterOptions(config)
+	opts.AlternateBackgrounds = true
+	opts.EvenPageBackground = "grey"
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with a write rate limit.
+	opts = DefaultMergePrinterOptions(config)
+	opts.WriteRateLimit = 10485760
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	assert.FileExists(t, dest)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the write
+	// rate limit is negative.
+	opts = DefaultMergePrinterOptions(config)
+	opts.WriteRateLimit = -1
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// options with qpdf as merge tool.
+	opts = DefaultMergePrinterOptions(config)
+	opts.Tool = QPDFMergeTool
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not merge as it is a dry run,
+	// but return the qpdf command.
+	opts = DefaultMergePrinterOptions(config)
+	opts.Tool = QPDFMergeTool
+	opts.DryRun = true
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	command, ok = xexec.DryRunCommand(err)
+	assert.True(t, ok)
+	assert.Contains(t, command, "qpdf")
+	assert.Contains(t, command, dest)
+	// should not be OK as the merge
+	// tool is unknown.
+	opts = DefaultMergePrinterOptions(config)
+	opts.Tool = "pdfcpu"
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// options with passwords.
+	opts = DefaultMergePrinterOptions(config)
+	opts.UserPassword = "foo"
+	opts.OwnerPassword = "bar"
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the passwords
+	// are the same.
+	opts = DefaultMergePrinterOptions(config)
+	opts.UserPassword = "foo"
+	opts.OwnerPassword = "foo"
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// options with interleaved pages.
+	opts = DefaultMergePrinterOptions(config)
+	opts.Interleave = true
+	p = NewMergePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
 	// should not be OK as context.Context
 	// should timeout.
 	opts = DefaultMergePrinterOptions(config)
@@ -38,6 +275,97 @@ func TestMergePrinter(t *testing.T) {
 	err = p.Print(dest)
 	test.AssertError(t, err)
 	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
 	err = os.RemoveAll(dest)
 	assert.Nil(t, err)
 }
+
+func TestMergeArgs(t *testing.T) {
+	fpaths := []string{"/tmp/a.pdf", "/tmp/b.pdf"}
+	assert.Equal(t, []string{"/tmp/a.pdf", "/tmp/b.pdf", "cat"}, mergeArgs(fpaths, nil, false))
+	assert.Equal(t, []string{"A=/tmp/a.pdf", "B=/tmp/b.pdf", "shuffle", "A", "B"}, mergeArgs(fpaths, nil, true))
+	// with page ranges.
+	ranges := []string{"1-3,5", ""}
+	assert.Equal(t, []string{"/tmp/a.pdf", "/tmp/b.pdf", "cat"}, mergeArgs(fpaths, []string{"", ""}, false))
+	expected := []string{"A=/tmp/a.pdf", "B=/tmp/b.pdf", "cat", "A1-3", "A5", "B"}
+	assert.Equal(t, expected, mergeArgs(fpaths, ranges, false))
+	ranges = []string{"-2", "4-"}
+	expected = []string{"A=/tmp/a.pdf", "B=/tmp/b.pdf", "shuffle", "A1-2", "B4-end"}
+	assert.Equal(t, expected, mergeArgs(fpaths, ranges, true))
+}
+
+func TestQPDFMergeArgs(t *testing.T) {
+	fpaths := []string{"/tmp/a.pdf", "/tmp/b.pdf"}
+	expected := []string{"/tmp/a.pdf", "--pages", "/tmp/a.pdf", "/tmp/b.pdf", "--", "/tmp/c.pdf"}
+	assert.Equal(t, expected, qpdfMergeArgs(fpaths, nil, "/tmp/c.pdf"))
+	// with page ranges.
+	ranges := []string{"", "-2,4,6-"}
+	expected = []string{"/tmp/a.pdf", "--pages", "/tmp/a.pdf", "/tmp/b.pdf", "1-2,4,6-z", "--", "/tmp/c.pdf"}
+	assert.Equal(t, expected, qpdfMergeArgs(fpaths, ranges, "/tmp/c.pdf"))
+}
+
+func TestValidateMergeRanges(t *testing.T) {
+	assert.Nil(t, validateMergeRanges(nil, false))
+	assert.Nil(t, validateMergeRanges([]string{"", "1-3,5"}, false))
+	assert.Nil(t, validateMergeRanges([]string{"", "2-"}, true))
+	// should not be OK as a page range is invalid.
+	err := validateMergeRanges([]string{"", "4-2"}, false)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as a page range has
+	// several parts while interleaving.
+	err = validateMergeRanges([]string{"1-3,5", ""}, true)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestNewMergeInputsPrinter(t *testing.T) {
+	inputs := []MergeInput{{Path: "/tmp/a.pdf", Ranges: "1-3"}, {Path: "/tmp/b.pdf"}}
+	p := NewMergeInputsPrinter(test.DebugLogger(), inputs, MergePrinterOptions{})
+	m, ok := p.(mergePrinter)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"/tmp/a.pdf", "/tmp/b.pdf"}, m.fpaths)
+	assert.Equal(t, []string{"1-3", ""}, m.ranges)
+}
+
+func TestValidateMergeInputs(t *testing.T) {
+	fpath := test.GenerateDestination()
+	err := ioutil.WriteFile(fpath, []byte("foo"), 0644)
+	assert.Nil(t, err)
+	defer os.Remove(fpath) // nolint: errcheck
+	fpaths := []string{fpath}
+	assert.Nil(t, validateMergeInputs(fpaths))
+	// should not be OK as there is no PDF.
+	err = validateMergeInputs(nil)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as a PDF does not exist.
+	err = validateMergeInputs(append(fpaths, "/tmp/foo.pdf"))
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	assert.Contains(t, xerror.Message(err), "/tmp/foo.pdf")
+	// should not be OK as a PDF is a directory.
+	err = validateMergeInputs(append(fpaths, os.TempDir()))
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestValidateMergeTool(t *testing.T) {
+	for _, tool := range MergeTools() {
+		assert.Nil(t, validateMergeTool(tool))
+	}
+	// an empty tool stands for PDFtk.
+	assert.Nil(t, validateMergeTool(""))
+	// should not be OK as the tool is unknown.
+	err := validateMergeTool("pdfcpu")
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestValidateInterleave(t *testing.T) {
+	fpaths := []string{"/tmp/a.pdf", "/tmp/b.pdf"}
+	assert.Nil(t, validateInterleave(fpaths, MergePrinterOptions{}))
+	// should not be OK as there is only one PDF.
+	err := validateInterleave(fpaths[:1], MergePrinterOptions{})
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as there is an index page.
+	err = validateInterleave(fpaths, MergePrinterOptions{Index: &ChromePrinterOptions{}})
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as qpdf cannot shuffle the pages.
+	err = validateInterleave(fpaths, MergePrinterOptions{Tool: QPDFMergeTool})
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
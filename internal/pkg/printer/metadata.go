@@ -0,0 +1,161 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+const (
+	// FirstMetadataPolicy keeps the metadata
+	// of the first merged PDF.
+	FirstMetadataPolicy string = "first"
+	// ClearMetadataPolicy removes the
+	// metadata of the merged PDF.
+	ClearMetadataPolicy string = "clear"
+	// ExplicitMetadataPolicy replaces the
+	// metadata of the merged PDF with the
+	// given one.
+	ExplicitMetadataPolicy string = "explicit"
+)
+
+// MetadataPolicies returns a slice containing
+// all available merge metadata policies.
+func MetadataPolicies() []string {
+	return []string{
+		FirstMetadataPolicy,
+		ClearMetadataPolicy,
+		ExplicitMetadataPolicy,
+	}
+}
+
+// nolint: gochecknoglobals
+var standardMetadataKeys = []string{
+	"Title",
+	"Author",
+	"Subject",
+	"Keywords",
+	"Creator",
+	"Producer",
+	"CreationDate",
+	"ModDate",
+}
+
+/*
+validateMetadata returns a xerror.Error with
+xerror.InvalidCode if the given metadata cannot
+be applied with the given policy.
+*/
+func validateMetadata(policy string, metadata map[string]string) error {
+	const op string = "printer.validateMetadata"
+	switch policy {
+	case FirstMetadataPolicy, ClearMetadataPolicy:
+		if len(metadata) > 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("metadata requires the '%s' metadata policy, got '%s'", ExplicitMetadataPolicy, policy),
+				nil,
+			)
+		}
+		return nil
+	case ExplicitMetadataPolicy:
+		if len(metadata) == 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("the '%s' metadata policy requires metadata", ExplicitMetadataPolicy),
+				nil,
+			)
+		}
+		for key, value := range metadata {
+			if key == "" || strings.ContainsAny(key, "\r\n") || strings.ContainsAny(value, "\r\n") {
+				return xerror.Invalid(
+					op,
+					fmt.Sprintf("metadata '%s' should have a key and no line breaks", key),
+					nil,
+				)
+			}
+		}
+		return nil
+	default:
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("metadata policy should be one of '%v', got '%s'", MetadataPolicies(), policy),
+			nil,
+		)
+	}
+}
+
+/*
+metadataData returns the PDFtk info data for
+the given policy. As PDFtk removes the entries
+with an empty value, clearing the metadata means
+emptying the standard entries.
+*/
+func metadataData(policy string, metadata map[string]string) string {
+	if policy == ClearMetadataPolicy {
+		metadata = make(map[string]string, len(standardMetadataKeys))
+		for _, key := range standardMetadataKeys {
+			metadata[key] = ""
+		}
+	}
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var data strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&data, "InfoBegin\nInfoKey: %s\nInfoValue: %s\n", key, metadata[key])
+	}
+	return data.String()
+}
+
+/*
+applyMetadata applies the given metadata policy
+to the PDF file located at fpath. The "first"
+policy does nothing, as it is what PDFtk does
+when merging.
+*/
+func applyMetadata(ctx context.Context, logger xlog.Logger, fpath, policy string, metadata map[string]string) error {
+	const op string = "printer.applyMetadata"
+	if policy == FirstMetadataPolicy {
+		return nil
+	}
+	logger.DebugfOp(op, "applying the '%s' metadata policy...", policy)
+	if err := updateInfo(ctx, logger, fpath, metadataData(policy, metadata)); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+updateInfo updates the Info dictionary and the
+bookmarks of the PDF file located at fpath with
+the given PDFtk info data.
+*/
+func updateInfo(ctx context.Context, logger xlog.Logger, fpath, data string) error {
+	const op string = "printer.updateInfo"
+	dataPath := fmt.Sprintf("%s/%s.txt", filepath.Dir(fpath), xrand.Get())
+	resolver := func() error {
+		if err := ioutil.WriteFile(dataPath, []byte(data), 0644); err != nil {
+			return err
+		}
+		defer os.Remove(dataPath) // nolint: errcheck
+		return postProcess(fpath, func(tmpDest string) error {
+			return xexec.Run(ctx, logger, "pdftk", fpath, "update_info_utf8", dataPath, "output", tmpDest)
+		})
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	assert.Nil(t, validateMetadata(FirstMetadataPolicy, nil))
+	assert.Nil(t, validateMetadata(ClearMetadataPolicy, nil))
+	assert.Nil(t, validateMetadata(ExplicitMetadataPolicy, map[string]string{"Title": "foo"}))
+	// should not be OK as the policy
+	// and the metadata do not match.
+	for _, tc := range []struct {
+		policy   string
+		metadata map[string]string
+	}{
+		{FirstMetadataPolicy, map[string]string{"Title": "foo"}},
+		{ExplicitMetadataPolicy, nil},
+		{ExplicitMetadataPolicy, map[string]string{"Title": "foo\nInfoBegin"}},
+		{ExplicitMetadataPolicy, map[string]string{"": "foo"}},
+		{"foo", nil},
+	} {
+		err := validateMetadata(tc.policy, tc.metadata)
+		test.AssertError(t, err)
+		assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	}
+}
+
+func TestMetadataData(t *testing.T) {
+	assert.Equal(
+		t,
+		"InfoBegin\nInfoKey: Author\nInfoValue: bar\nInfoBegin\nInfoKey: Title\nInfoValue: foo\n",
+		metadataData(ExplicitMetadataPolicy, map[string]string{"Title": "foo", "Author": "bar"}),
+	)
+	// the standard entries are emptied.
+	data := metadataData(ClearMetadataPolicy, nil)
+	assert.Contains(t, data, "InfoKey: Title\nInfoValue: \n")
+	assert.Contains(t, data, "InfoKey: Producer\nInfoValue: \n")
+}
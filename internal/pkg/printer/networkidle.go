@@ -0,0 +1,153 @@
+package printer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mafredri/cdp"
+	"github.com/mafredri/cdp/protocol/network"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xtime"
+)
+
+/*
+networkActivity keeps track of the requests of a
+page which are in flight, and of the last time a
+request started or ended.
+*/
+type networkActivity struct {
+	mu           sync.Mutex
+	inFlight     map[network.RequestID]struct{}
+	lastActivity time.Time
+	now          func() time.Time
+	closers      []func() error
+}
+
+/*
+startNetworkActivity starts tracking the requests
+of the page. The tracking stops once closed.
+*/
+func startNetworkActivity(client *cdp.Client) (*networkActivity, error) {
+	const op string = "printer.startNetworkActivity"
+	na := &networkActivity{
+		inFlight:     make(map[network.RequestID]struct{}),
+		lastActivity: time.Now(),
+		now:          time.Now,
+	}
+	resolver := func() error {
+		// see startDiagnostics.
+		ctx := context.Background()
+		requestWillBeSent, err := client.Network.RequestWillBeSent(ctx)
+		if err != nil {
+			return err
+		}
+		na.closers = append(na.closers, requestWillBeSent.Close)
+		loadingFinished, err := client.Network.LoadingFinished(ctx)
+		if err != nil {
+			return err
+		}
+		na.closers = append(na.closers, loadingFinished.Close)
+		loadingFailed, err := client.Network.LoadingFailed(ctx)
+		if err != nil {
+			return err
+		}
+		na.closers = append(na.closers, loadingFailed.Close)
+		// each goroutine stops once its stream is closed.
+		go func() {
+			for {
+				ev, err := requestWillBeSent.Recv()
+				if err != nil {
+					return
+				}
+				na.start(ev.RequestID)
+			}
+		}()
+		go func() {
+			for {
+				ev, err := loadingFinished.Recv()
+				if err != nil {
+					return
+				}
+				na.end(ev.RequestID)
+			}
+		}()
+		go func() {
+			for {
+				ev, err := loadingFailed.Recv()
+				if err != nil {
+					return
+				}
+				na.end(ev.RequestID)
+			}
+		}()
+		return nil
+	}
+	if err := resolver(); err != nil {
+		na.close()
+		return nil, xerror.New(op, err)
+	}
+	return na, nil
+}
+
+func (na *networkActivity) start(ID network.RequestID) {
+	na.mu.Lock()
+	defer na.mu.Unlock()
+	na.inFlight[ID] = struct{}{}
+	na.lastActivity = na.now()
+}
+
+func (na *networkActivity) end(ID network.RequestID) {
+	na.mu.Lock()
+	defer na.mu.Unlock()
+	if _, ok := na.inFlight[ID]; !ok {
+		return
+	}
+	delete(na.inFlight, ID)
+	na.lastActivity = na.now()
+}
+
+/*
+isIdle returns true if no request has been in
+flight for at least the given duration.
+*/
+func (na *networkActivity) isIdle(idle time.Duration) bool {
+	na.mu.Lock()
+	defer na.mu.Unlock()
+	return len(na.inFlight) == 0 && na.now().Sub(na.lastActivity) >= idle
+}
+
+func (na *networkActivity) close() {
+	for _, closer := range na.closers {
+		closer() // nolint: errcheck
+	}
+}
+
+/*
+waitForNetworkIdle waits until no request of the
+page has been in flight for the network idle
+duration.
+*/
+func (p chromePrinter) waitForNetworkIdle(ctx context.Context, activity *networkActivity) error {
+	const op string = "printer.chromePrinter.waitForNetworkIdle"
+	p.logger.DebugfOp(op, "waiting for '%.2fs' of network idle...", p.opts.NetworkIdleDuration)
+	resolver := func() error {
+		idle := xtime.Duration(p.opts.NetworkIdleDuration)
+		err := poll(ctx, p.opts.PollInterval, func() (bool, error) {
+			return activity.isIdle(idle), nil
+		})
+		if err != nil && xerror.Code(err) == xerror.TimeoutCode {
+			return xerror.Timeout(
+				op,
+				"network did not become idle before the deadline",
+				err,
+			)
+		}
+		return err
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	p.logger.DebugOp(op, "network idle reached")
+	return nil
+}
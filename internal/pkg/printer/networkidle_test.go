@@ -0,0 +1,36 @@
+package printer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mafredri/cdp/protocol/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkActivity(t *testing.T) {
+	const idle time.Duration = 500 * time.Millisecond
+	clock := &fakeClock{now: time.Now()}
+	na := &networkActivity{
+		inFlight:     make(map[network.RequestID]struct{}),
+		lastActivity: clock.now,
+		now:          func() time.Time { return clock.now },
+	}
+	// not idle for long enough.
+	assert.False(t, na.isIdle(idle))
+	clock.advance(idle)
+	assert.True(t, na.isIdle(idle))
+	// a request in flight.
+	na.start("1")
+	clock.advance(2 * idle)
+	assert.False(t, na.isIdle(idle))
+	// unknown requests are ignored.
+	na.end("2")
+	assert.False(t, na.isIdle(idle))
+	// the idle window starts once
+	// the last request has ended.
+	na.end("1")
+	assert.False(t, na.isIdle(idle))
+	clock.advance(idle)
+	assert.True(t, na.isIdle(idle))
+}
@@ -5,16 +5,35 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/phayes/freeport"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
-	"github.com/thecodingmachine/gotenberg/internal/pkg/xcontext"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
 )
 
+const (
+	// UnoconvOfficeConverter converts the Office
+	// documents thanks to unoconv.
+	UnoconvOfficeConverter string = "unoconv"
+	// SofficeOfficeConverter converts the Office
+	// documents thanks to a headless LibreOffice
+	// process (soffice) per document.
+	SofficeOfficeConverter string = "soffice"
+)
+
+// OfficeConverters returns a slice containing
+// all available Office converters.
+func OfficeConverters() []string {
+	return []string{
+		UnoconvOfficeConverter,
+		SofficeOfficeConverter,
+	}
+}
+
 type officePrinter struct {
 	logger xlog.Logger
 	fpaths []string
@@ -24,24 +43,116 @@ type officePrinter struct {
 // OfficePrinterOptions helps customizing the
 // Office Printer behaviour.
 type OfficePrinterOptions struct {
-	WaitTimeout float64
-	Landscape   bool
+	WaitTimeout  float64
+	Landscape    bool
+	RequestID    string
+	DryRun       bool
+	MaxProcesses int64
+	Converter    string
 }
 
 // DefaultOfficePrinterOptions returns the default
 // Office Printer options.
 func DefaultOfficePrinterOptions(config conf.Config) OfficePrinterOptions {
 	return OfficePrinterOptions{
-		WaitTimeout: config.DefaultWaitTimeout(),
-		Landscape:   false,
+		WaitTimeout:  config.DefaultWaitTimeout(),
+		Landscape:    false,
+		RequestID:    "",
+		DryRun:       false,
+		MaxProcesses: config.MaximumConcurrentOfficeProcesses(),
+		Converter:    UnoconvOfficeConverter,
 	}
 }
 
-// NewOfficePrinter returns a Printer which
-// is able to convert Office documents to PDF.
+/*
+officeDocument associates a file extension
+with the unoconv document type which selects
+the LibreOffice PDF export filter, e.g.
+"document" selects "writer_pdf_Export", and
+with this filter, for soffice.
+*/
+type officeDocument struct {
+	ext     string
+	docType string
+	filter  string
+}
+
+// nolint: gochecknoglobals
+var officeDocuments = []officeDocument{
+	{ext: ".txt", docType: "document", filter: "writer_pdf_Export"},
+	{ext: ".rtf", docType: "document", filter: "writer_pdf_Export"},
+	{ext: ".fodt", docType: "document", filter: "writer_pdf_Export"},
+	{ext: ".doc", docType: "document", filter: "writer_pdf_Export"},
+	{ext: ".docx", docType: "document", filter: "writer_pdf_Export"},
+	{ext: ".odt", docType: "document", filter: "writer_pdf_Export"},
+	{ext: ".xls", docType: "spreadsheet", filter: "calc_pdf_Export"},
+	{ext: ".xlsx", docType: "spreadsheet", filter: "calc_pdf_Export"},
+	{ext: ".ods", docType: "spreadsheet", filter: "calc_pdf_Export"},
+	{ext: ".ppt", docType: "presentation", filter: "impress_pdf_Export"},
+	{ext: ".pptx", docType: "presentation", filter: "impress_pdf_Export"},
+	{ext: ".odp", docType: "presentation", filter: "impress_pdf_Export"},
+}
+
+// OfficeExtensions returns a slice containing
+// all file extensions the Office Printer
+// is able to convert.
+func OfficeExtensions() []string {
+	exts := make([]string, len(officeDocuments))
+	for i, doc := range officeDocuments {
+		exts[i] = doc.ext
+	}
+	return exts
+}
+
+/*
+officeDocumentType returns the unoconv document
+type of the given file according to its extension.
+
+If the extension is not supported, returns a
+xerror.Error with xerror.InvalidCode.
+*/
+func officeDocumentType(fpath string) (string, error) {
+	const op string = "printer.officeDocumentType"
+	doc, err := findOfficeDocument(fpath)
+	if err != nil {
+		return "", xerror.New(op, err)
+	}
+	return doc.docType, nil
+}
+
+func findOfficeDocument(fpath string) (officeDocument, error) {
+	const op string = "printer.findOfficeDocument"
+	ext := strings.ToLower(filepath.Ext(fpath))
+	for _, doc := range officeDocuments {
+		if doc.ext == ext {
+			return doc, nil
+		}
+	}
+	return officeDocument{}, xerror.Invalid(
+		op,
+		fmt.Sprintf("'%s' is not a supported Office document: expected one of '%v'", filepath.Base(fpath), OfficeExtensions()),
+		nil,
+	)
+}
+
+/*
+NewOfficePrinter returns a Printer which
+is able to convert Office documents to PDF.
+
+The Converter option tells whether the documents
+are converted thanks to unoconv or soffice, which
+does not require a listener but does not support
+the Landscape option. If there are several
+documents, the resulting PDFs are merged thanks
+to PDFtk.
+
+If the DryRun option is set, the conversion command
+of the first document is not executed: the Printer
+returns a xexec.DryRunError holding it instead.
+*/
 func NewOfficePrinter(logger xlog.Logger, fpaths []string, opts OfficePrinterOptions) Printer {
 	return officePrinter{
-		logger: logger,
+		logger: requestLogger(logger, opts.RequestID),
 		fpaths: fpaths,
 		opts:   opts,
 	}
@@ -50,16 +161,37 @@ func NewOfficePrinter(logger xlog.Logger, fpaths []string, opts OfficePrinterOpt
 func (p officePrinter) Print(destination string) error {
 	const op string = "printer.officePrinter.Print"
 	logOptions(p.logger, p.opts)
-	ctx, cancel := xcontext.WithTimeout(p.logger, p.opts.WaitTimeout)
+	ctx, cancel := withTimeout(p.logger, p.opts.WaitTimeout)
 	defer cancel()
+	if p.opts.DryRun {
+		ctx = xexec.WithDryRun(ctx)
+	}
 	resolver := func() error {
+		if err := officeLimiter.acquire(ctx, p.opts.MaxProcesses); err != nil {
+			return err
+		}
+		defer officeLimiter.release()
+		if err := p.opts.validate(); err != nil {
+			return err
+		}
+		// fail early if one of the files
+		// is not supported.
+		for _, fpath := range p.fpaths {
+			if _, err := officeDocumentType(fpath); err != nil {
+				return err
+			}
+		}
 		fpaths := make([]string, len(p.fpaths))
 		dirPath := filepath.Dir(destination)
 		for i, fpath := range p.fpaths {
 			baseFilename := xrand.Get()
 			tmpDest := fmt.Sprintf("%s/%d%s.pdf", dirPath, i, baseFilename)
 			p.logger.DebugfOp(op, "converting '%s' to PDF...", fpath)
-			if err := unoconv(ctx, p.logger, fpath, tmpDest, p.opts); err != nil {
+			convert := unoconv
+			if p.opts.Converter == SofficeOfficeConverter {
+				convert = soffice
+			}
+			if err := convert(ctx, p.logger, fpath, tmpDest, p.opts); err != nil {
 				return err
 			}
 			p.logger.DebugfOp(op, "'%s.pdf' created", baseFilename)
@@ -73,13 +205,14 @@ func (p officePrinter) Print(destination string) error {
 			logger: p.logger,
 			ctx:    ctx,
 			fpaths: fpaths,
+			opts:   MergePrinterOptions{MetadataPolicy: FirstMetadataPolicy},
 		}
 		return m.Print(destination)
 	}
 	if err := resolver(); err != nil {
-		return xcontext.MustHandleError(
+		return mustHandleError(
 			ctx,
-			xerror.New(op, err),
+			xerror.New(requestOp(op, p.opts.RequestID), err),
 		)
 	}
 	return nil
@@ -88,6 +221,10 @@ func (p officePrinter) Print(destination string) error {
 func unoconv(ctx context.Context, logger xlog.Logger, fpath, destination string, opts OfficePrinterOptions) error {
 	const op string = "printer.unoconv"
 	resolver := func() error {
+		docType, err := officeDocumentType(fpath)
+		if err != nil {
+			return err
+		}
 		port, err := freeport.GetFreePort()
 		if err != nil {
 			return err
@@ -97,6 +234,8 @@ func unoconv(ctx context.Context, logger xlog.Logger, fpath, destination string,
 			fmt.Sprintf("///tmp/%d", port),
 			"--port",
 			fmt.Sprintf("%d", port),
+			"--doctype",
+			docType,
 			"--format",
 			"pdf",
 		}
@@ -112,6 +251,76 @@ func unoconv(ctx context.Context, logger xlog.Logger, fpath, destination string,
 	return nil
 }
 
+/*
+soffice converts the given document to PDF
+thanks to a headless LibreOffice process with
+its own user profile, so that several processes
+may run at the same time.
+*/
+func soffice(ctx context.Context, logger xlog.Logger, fpath, destination string, opts OfficePrinterOptions) error {
+	const op string = "printer.soffice"
+	resolver := func() error {
+		doc, err := findOfficeDocument(fpath)
+		if err != nil {
+			return err
+		}
+		profilePath := fmt.Sprintf("%s/%s", os.TempDir(), xrand.Get())
+		defer os.RemoveAll(profilePath) // nolint: errcheck
+		// soffice names the PDF after the document.
+		outDirPath := fmt.Sprintf("%s/%s", filepath.Dir(destination), xrand.Get())
+		if err := os.MkdirAll(outDirPath, 0755); err != nil {
+			return err
+		}
+		defer os.RemoveAll(outDirPath) // nolint: errcheck
+		args := []string{
+			"--headless",
+			"--norestore",
+			"--nolockcheck",
+			fmt.Sprintf("-env:UserInstallation=file://%s", profilePath),
+			"--convert-to",
+			fmt.Sprintf("pdf:%s", doc.filter),
+			"--outdir",
+			outDirPath,
+			fpath,
+		}
+		if err := xexec.Run(ctx, logger, "soffice", args...); err != nil {
+			return err
+		}
+		baseFilename := strings.TrimSuffix(filepath.Base(fpath), filepath.Ext(fpath))
+		result := fmt.Sprintf("%s/%s.pdf", outDirPath, baseFilename)
+		// soffice may exit successfully without
+		// converting a document it cannot read.
+		if _, err := os.Stat(result); err != nil {
+			return xerror.Invalid(op, fmt.Sprintf("LibreOffice failed to convert '%s'", filepath.Base(fpath)), err)
+		}
+		return os.Rename(result, destination)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+func (opts OfficePrinterOptions) validate() error {
+	const op string = "printer.OfficePrinterOptions.validate"
+	switch opts.Converter {
+	// an empty converter stands for unoconv.
+	case "", UnoconvOfficeConverter:
+		return nil
+	case SofficeOfficeConverter:
+		if opts.Landscape {
+			return xerror.Invalid(op, "the landscape orientation requires the unoconv converter", nil)
+		}
+		return nil
+	default:
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("converter should be one of '%v', got '%s'", OfficeConverters(), opts.Converter),
+			nil,
+		)
+	}
+}
+
 // Compile-time checks to ensure type implements desired interfaces.
 var (
 	_ = Printer(new(officePrinter))
@@ -1,6 +1,9 @@
 package printer
 
 import (
+	"context"
+	"errors"
+	"io/ioutil"
 	"os"
 	"testing"
 
@@ -46,6 +49,38 @@ func TestOfficePrinter(t *testing.T) {
 	assert.Nil(t, err)
 	err = os.RemoveAll(dest)
 	assert.Nil(t, err)
+	// options with soffice.
+	opts = DefaultOfficePrinterOptions(config)
+	opts.Converter = SofficeOfficeConverter
+	p = NewOfficePrinter(logger, fpaths, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as soffice cannot
+	// read the document.
+	f, err := ioutil.TempFile("", "*.docx")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name()) // nolint: errcheck
+	_, err = f.Write([]byte("foo"))
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+	opts = DefaultOfficePrinterOptions(config)
+	opts.Converter = SofficeOfficeConverter
+	p = NewOfficePrinter(logger, []string{f.Name()}, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as a file
+	// is not an Office document.
+	opts = DefaultOfficePrinterOptions(config)
+	p = NewOfficePrinter(logger, append(fpaths, test.MergeFpaths(t)[0]), opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
 	// should not be OK as context.Context
 	// should timeout.
 	opts = DefaultOfficePrinterOptions(config)
@@ -55,6 +90,45 @@ func TestOfficePrinter(t *testing.T) {
 	err = p.Print(dest)
 	test.AssertError(t, err)
 	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
 	err = os.RemoveAll(dest)
 	assert.Nil(t, err)
 }
+
+func TestOfficeDocumentType(t *testing.T) {
+	docType, err := officeDocumentType("/tmp/document.doc")
+	assert.Nil(t, err)
+	assert.Equal(t, "document", docType)
+	docType, err = officeDocumentType("/tmp/DOCUMENT.RTF")
+	assert.Nil(t, err)
+	assert.Equal(t, "document", docType)
+	docType, err = officeDocumentType("/tmp/sheet.ods")
+	assert.Nil(t, err)
+	assert.Equal(t, "spreadsheet", docType)
+	// should not be OK as the file
+	// extension is not supported.
+	_, err = officeDocumentType("/tmp/document.pdf")
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestOfficePrinterOptionsValidate(t *testing.T) {
+	opts := DefaultOfficePrinterOptions(conf.DefaultConfig())
+	assert.Nil(t, opts.validate())
+	// an empty converter stands for unoconv.
+	assert.Nil(t, OfficePrinterOptions{}.validate())
+	opts.Converter = SofficeOfficeConverter
+	assert.Nil(t, opts.validate())
+	// should not be OK as soffice does not
+	// support the landscape orientation.
+	opts.Landscape = true
+	err := opts.validate()
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as the converter is unknown.
+	opts = DefaultOfficePrinterOptions(conf.DefaultConfig())
+	opts.Converter = "foo"
+	err = opts.validate()
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
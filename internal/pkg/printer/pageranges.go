@@ -0,0 +1,53 @@
+package printer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+// nolint: gochecknoglobals
+var pageRangeRegexp = regexp.MustCompile(`^(\d+)?(-(\d+)?)?$`)
+
+/*
+validatePageRanges checks that the given page
+ranges follow the Google Chrome syntax, e.g.
+"1-3,5,8-".
+*/
+func validatePageRanges(ranges string) error {
+	const op string = "printer.validatePageRanges"
+	invalid := func(reason string) error {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("'%s' is not a valid page ranges value: %s", ranges, reason),
+			nil,
+		)
+	}
+	for _, part := range strings.Split(ranges, ",") {
+		part = strings.TrimSpace(part)
+		matches := pageRangeRegexp.FindStringSubmatch(part)
+		if matches == nil || (matches[1] == "" && matches[3] == "") {
+			return invalid(fmt.Sprintf("'%s' is not a page or a range of pages", part))
+		}
+		first, last := 0, 0
+		if matches[1] != "" {
+			first, _ = strconv.Atoi(matches[1])
+			if first < 1 {
+				return invalid("pages start at '1'")
+			}
+		}
+		if matches[3] != "" {
+			last, _ = strconv.Atoi(matches[3])
+			if last < 1 {
+				return invalid("pages start at '1'")
+			}
+		}
+		if first > 0 && last > 0 && first > last {
+			return invalid(fmt.Sprintf("'%s' is a decreasing range", part))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestValidatePageRanges(t *testing.T) {
+	// valid page ranges.
+	assert.Nil(t, validatePageRanges("1"))
+	assert.Nil(t, validatePageRanges("2-4"))
+	assert.Nil(t, validatePageRanges("1-3,5,8-"))
+	assert.Nil(t, validatePageRanges("-3, 5-6"))
+	// should not be OK as the page
+	// ranges are invalid.
+	for _, ranges := range []string{"", ",", "-", "0", "foo", "1-2-3", "4-2", "1,,2"} {
+		err := validatePageRanges(ranges)
+		test.AssertError(t, err)
+		assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	}
+}
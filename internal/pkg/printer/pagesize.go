@@ -0,0 +1,134 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+const (
+	// A3PageSize is 297 x 420 mm.
+	A3PageSize string = "A3"
+	// A4PageSize is 210 x 297 mm.
+	A4PageSize string = "A4"
+	// A5PageSize is 148 x 210 mm.
+	A5PageSize string = "A5"
+	// LetterPageSize is 8.5 x 11 inches.
+	LetterPageSize string = "Letter"
+	// LegalPageSize is 8.5 x 14 inches.
+	LegalPageSize string = "Legal"
+	// TabloidPageSize is 11 x 17 inches.
+	TabloidPageSize string = "Tabloid"
+)
+
+// nolint: gochecknoglobals
+var ghostscriptPaperSizes = map[string]string{
+	A3PageSize:      "a3",
+	A4PageSize:      "a4",
+	A5PageSize:      "a5",
+	LetterPageSize:  "letter",
+	LegalPageSize:   "legal",
+	TabloidPageSize: "11x17",
+}
+
+// paperSize is a paper size in inches.
+type paperSize struct {
+	width  float64
+	height float64
+}
+
+// nolint: gochecknoglobals
+var paperSizes = map[string]paperSize{
+	A3PageSize:      {width: 11.7, height: 16.54},
+	A4PageSize:      {width: 8.27, height: 11.7},
+	A5PageSize:      {width: 5.83, height: 8.27},
+	LetterPageSize:  {width: 8.5, height: 11},
+	LegalPageSize:   {width: 8.5, height: 14},
+	TabloidPageSize: {width: 11, height: 17},
+}
+
+/*
+PageSizes returns a slice containing
+all page sizes the merged pages may
+be normalized to.
+*/
+func PageSizes() []string {
+	return []string{
+		A3PageSize,
+		A4PageSize,
+		A5PageSize,
+		LetterPageSize,
+		LegalPageSize,
+		TabloidPageSize,
+	}
+}
+
+/*
+validatePageSize returns a xerror.Error with
+xerror.InvalidCode if the given page size is
+neither empty nor one of PageSizes.
+*/
+func validatePageSize(pageSize string) error {
+	const op string = "printer.validatePageSize"
+	if pageSize == "" {
+		return nil
+	}
+	if _, ok := ghostscriptPaperSizes[pageSize]; !ok {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("page size should be one of '%v', got '%s'", PageSizes(), pageSize),
+			nil,
+		)
+	}
+	return nil
+}
+
+/*
+PaperSize returns the width and the height in
+inches, as expected by Google Chrome, of the
+given page size.
+
+If the page size is not one of PageSizes,
+returns a xerror.Error with xerror.InvalidCode.
+*/
+func PaperSize(name string) (width, height float64, err error) {
+	const op string = "printer.PaperSize"
+	size, ok := paperSizes[name]
+	if !ok {
+		return 0, 0, xerror.Invalid(
+			op,
+			fmt.Sprintf("page size should be one of '%v', got '%s'", PageSizes(), name),
+			nil,
+		)
+	}
+	return size.width, size.height, nil
+}
+
+/*
+normalizePageSize scales each page of the PDF
+file located at fpath to fit the given page
+size thanks to Ghostscript. The aspect ratio
+of the pages is preserved and they are centered.
+*/
+func normalizePageSize(ctx context.Context, logger xlog.Logger, fpath, pageSize string) error {
+	const op string = "printer.normalizePageSize"
+	resolver := func() error {
+		if err := validatePageSize(pageSize); err != nil {
+			return err
+		}
+		return ghostscript(
+			ctx,
+			logger,
+			fpath,
+			fmt.Sprintf("-sPAPERSIZE=%s", ghostscriptPaperSizes[pageSize]),
+			"-dFIXEDMEDIA",
+			"-dPDFFitPage",
+		)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+func TestValidatePageSize(t *testing.T) {
+	// no normalization.
+	assert.Nil(t, validatePageSize(""))
+	// every page size should have a Ghostscript paper size.
+	for _, pageSize := range PageSizes() {
+		assert.Nil(t, validatePageSize(pageSize))
+	}
+	// should not be OK as the page
+	// size is unknown.
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(validatePageSize("a4")))
+}
+
+func TestPaperSize(t *testing.T) {
+	width, height, err := PaperSize(LetterPageSize)
+	assert.Nil(t, err)
+	assert.Equal(t, 8.5, width)
+	assert.Equal(t, 11.0, height)
+	// every page size should have a paper size.
+	for _, pageSize := range PageSizes() {
+		_, _, err := PaperSize(pageSize)
+		assert.Nil(t, err)
+	}
+	// should not be OK as the page
+	// size is unknown.
+	_, _, err = PaperSize("a4")
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
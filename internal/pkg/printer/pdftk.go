@@ -0,0 +1,159 @@
+package printer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+const (
+	// PrintingPermission allows top quality printing.
+	PrintingPermission string = "Printing"
+	// DegradedPrintingPermission allows lower quality printing.
+	DegradedPrintingPermission string = "DegradedPrinting"
+	// ModifyContentsPermission allows modifying the contents.
+	ModifyContentsPermission string = "ModifyContents"
+	// AssemblyPermission allows assembling the document.
+	AssemblyPermission string = "Assembly"
+	// CopyContentsPermission allows copying the contents.
+	CopyContentsPermission string = "CopyContents"
+	// ScreenReadersPermission allows screen readers.
+	ScreenReadersPermission string = "ScreenReaders"
+	// ModifyAnnotationsPermission allows modifying the annotations.
+	ModifyAnnotationsPermission string = "ModifyAnnotations"
+	// FillInPermission allows filling in form fields.
+	FillInPermission string = "FillIn"
+	// AllFeaturesPermission allows all of the above.
+	AllFeaturesPermission string = "AllFeatures"
+)
+
+/*
+Permissions returns a slice containing
+all permissions' names PDFtk understands
+with its "allow" keyword.
+*/
+func Permissions() []string {
+	return []string{
+		PrintingPermission,
+		DegradedPrintingPermission,
+		ModifyContentsPermission,
+		AssemblyPermission,
+		CopyContentsPermission,
+		ScreenReadersPermission,
+		ModifyAnnotationsPermission,
+		FillInPermission,
+		AllFeaturesPermission,
+	}
+}
+
+/*
+encryptionArgs returns the PDFtk arguments
+for encrypting the resulting PDF.
+
+If no permissions nor passwords are given,
+returns no arguments so that the resulting PDF
+is not encrypted. Otherwise, every feature which
+is not explicitly allowed is denied.
+
+The user password is required to open the PDF,
+the owner password to change its permissions.
+If the latter is not given, a random one is
+generated, as readers would otherwise open the
+PDF with every permission. As PDFtk rejects
+identical passwords, returns a xerror.Error
+with xerror.InvalidCode in such a case, without
+the passwords in its message.
+*/
+func encryptionArgs(permissions []string, userPassword, ownerPassword string) ([]string, error) {
+	const op string = "printer.encryptionArgs"
+	if len(permissions) == 0 && userPassword == "" && ownerPassword == "" {
+		return nil, nil
+	}
+	if userPassword != "" && userPassword == ownerPassword {
+		return nil, xerror.Invalid(op, "the user and owner passwords should be different", nil)
+	}
+	for _, permission := range permissions {
+		if !isPermission(permission) {
+			return nil, xerror.Invalid(
+				op,
+				fmt.Sprintf("permission should be one of '%v', got '%s'", Permissions(), permission),
+				nil,
+			)
+		}
+	}
+	if ownerPassword == "" {
+		password, err := randomPassword()
+		if err != nil {
+			return nil, xerror.New(op, err)
+		}
+		ownerPassword = password
+	}
+	args := []string{"encrypt_128bit", "owner_pw", ownerPassword}
+	if userPassword != "" {
+		args = append(args, "user_pw", userPassword)
+	}
+	if len(permissions) == 0 {
+		return args, nil
+	}
+	args = append(args, "allow")
+	args = append(args, permissions...)
+	return args, nil
+}
+
+// randomPassword returns a random password
+// nobody knows, e.g. for the owner password.
+func randomPassword() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+/*
+encrypt encrypts the PDF file located at fpath
+thanks to PDFtk and the given encryption
+arguments. If there are none, it does nothing.
+*/
+func encrypt(ctx context.Context, logger xlog.Logger, fpath string, encryption []string) error {
+	const op string = "printer.encrypt"
+	if len(encryption) == 0 {
+		return nil
+	}
+	err := postProcess(fpath, func(tmpDest string) error {
+		args := []string{fpath, "output", tmpDest}
+		args = append(args, encryption...)
+		return xexec.Run(ctx, logger, "pdftk", args...)
+	})
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+pdftkHandle returns the PDFtk input handle of the
+i-th PDF, i.e. "A" to "Z", then "AA", "AB", etc.
+*/
+func pdftkHandle(i int) string {
+	handle := string(rune('A' + i%26))
+	for i /= 26; i > 0; i /= 26 {
+		i--
+		handle = string(rune('A'+i%26)) + handle
+	}
+	return handle
+}
+
+func isPermission(permission string) bool {
+	for _, p := range Permissions() {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package printer
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestEncryptionArgs(t *testing.T) {
+	// no permissions, no encryption.
+	args, err := encryptionArgs(nil, "", "")
+	assert.Nil(t, err)
+	assert.Empty(t, args)
+	// with permissions but no owner password,
+	// which should be random so that readers do
+	// not get every permission.
+	args, err = encryptionArgs([]string{PrintingPermission, CopyContentsPermission}, "", "")
+	assert.Nil(t, err)
+	if assert.Len(t, args, 6) {
+		assert.Equal(t, []string{"encrypt_128bit", "owner_pw"}, args[:2])
+		assert.Len(t, args[2], 32)
+		assert.Equal(t, []string{"allow", "Printing", "CopyContents"}, args[3:])
+	}
+	otherArgs, err := encryptionArgs([]string{PrintingPermission, CopyContentsPermission}, "", "")
+	assert.Nil(t, err)
+	assert.NotEqual(t, args, otherArgs)
+	// with a user password but no owner password.
+	args, err = encryptionArgs(nil, "foo", "")
+	assert.Nil(t, err)
+	if assert.Len(t, args, 5) {
+		assert.Equal(t, "owner_pw", args[1])
+		assert.NotEmpty(t, args[2])
+		assert.NotEqual(t, "foo", args[2])
+		assert.Equal(t, []string{"user_pw", "foo"}, args[3:])
+	}
+	// should not be OK as a permission
+	// is invalid.
+	_, err = encryptionArgs([]string{PrintingPermission, "foo"}, "", "")
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// with passwords.
+	args, err = encryptionArgs(nil, "foo", "bar")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"encrypt_128bit", "owner_pw", "bar", "user_pw", "foo"}, args)
+	args, err = encryptionArgs([]string{PrintingPermission}, "", "bar")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"encrypt_128bit", "owner_pw", "bar", "allow", "Printing"}, args)
+	// should not be OK as the passwords
+	// are the same, which the error should
+	// not tell.
+	_, err = encryptionArgs(nil, "foo", "foo")
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	assert.NotContains(t, err.Error(), "foo")
+}
+
+func TestEncrypt(t *testing.T) {
+	// the logger writes to the standard error.
+	logs, err := ioutil.TempFile("", "logs")
+	assert.Nil(t, err)
+	defer os.Remove(logs.Name()) // nolint: errcheck
+	defer logs.Close()           // nolint: errcheck
+	stderr := os.Stderr
+	os.Stderr = logs
+	logger := test.DebugLogger()
+	os.Stderr = stderr
+	data, err := ioutil.ReadFile(test.MergeFpaths(t)[0])
+	assert.Nil(t, err)
+	fpath := test.GenerateDestination()
+	err = ioutil.WriteFile(fpath, data, 0644)
+	assert.Nil(t, err)
+	defer os.Remove(fpath) // nolint: errcheck
+	encryption, err := encryptionArgs([]string{PrintingPermission}, "usersecret", "ownersecret")
+	assert.Nil(t, err)
+	err = encrypt(context.Background(), logger, fpath, encryption)
+	assert.Nil(t, err)
+	// the passwords should not end up in the logs.
+	content, err := ioutil.ReadFile(logs.Name())
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "pdftk")
+	assert.NotContains(t, string(content), "usersecret")
+	assert.NotContains(t, string(content), "ownersecret")
+}
+
+func TestPdftkHandle(t *testing.T) {
+	assert.Equal(t, "A", pdftkHandle(0))
+	assert.Equal(t, "Z", pdftkHandle(25))
+	assert.Equal(t, "AA", pdftkHandle(26))
+	assert.Equal(t, "AZ", pdftkHandle(51))
+	assert.Equal(t, "BA", pdftkHandle(52))
+}
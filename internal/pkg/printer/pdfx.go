@@ -0,0 +1,190 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+const (
+	// PDFX32002 is the PDF/X-3:2002 standard.
+	PDFX32002 string = "PDF/X-3:2002"
+	// PDFX32003 is the PDF/X-3:2003 standard.
+	PDFX32003 string = "PDF/X-3:2003"
+)
+
+// PDFXVersions returns a slice containing
+// all PDF/X standards the resulting PDF
+// may comply with.
+func PDFXVersions() []string {
+	return []string{
+		PDFX32002,
+		PDFX32003,
+	}
+}
+
+/*
+iccColorSpace describes how Ghostscript
+converts the colors for an ICC profile
+of a given color space.
+*/
+type iccColorSpace struct {
+	components        int
+	conversion        string
+	processColorModel string
+}
+
+// nolint: gochecknoglobals
+var iccColorSpaces = map[string]iccColorSpace{
+	"CMYK": {components: 4, conversion: "CMYK", processColorModel: "DeviceCMYK"},
+	"RGB ": {components: 3, conversion: "RGB", processColorModel: "DeviceRGB"},
+	"GRAY": {components: 1, conversion: "Gray", processColorModel: "DeviceGray"},
+}
+
+/*
+readICCColorSpace returns the color space of the
+ICC profile located at fpath, according to its
+header.
+
+The ICC profile should have been validated
+beforehand.
+*/
+func readICCColorSpace(fpath string) (iccColorSpace, error) {
+	const (
+		op               string = "printer.readICCColorSpace"
+		colorSpaceOffset int64  = 16
+		colorSpaceSize   int    = 4
+	)
+	resolver := func() (iccColorSpace, error) {
+		f, err := os.Open(fpath)
+		if err != nil {
+			return iccColorSpace{}, err
+		}
+		defer f.Close() // nolint: errcheck
+		signature := make([]byte, colorSpaceSize)
+		if _, err := f.ReadAt(signature, colorSpaceOffset); err != nil && err != io.EOF {
+			return iccColorSpace{}, err
+		}
+		colorSpace, ok := iccColorSpaces[string(signature)]
+		if !ok {
+			return iccColorSpace{}, xerror.Invalid(
+				op,
+				fmt.Sprintf("ICC profile color space should be CMYK, RGB or Gray, got '%s'", strings.TrimSpace(string(signature))),
+				nil,
+			)
+		}
+		return colorSpace, nil
+	}
+	result, err := resolver()
+	if err != nil {
+		return iccColorSpace{}, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+/*
+validatePDFX checks that the given PDF/X standard
+is one of PDFXVersions and that an ICC profile is
+given, as PDF/X requires an output intent.
+*/
+func validatePDFX(version, iccProfilePath string) error {
+	const op string = "printer.validatePDFX"
+	resolver := func() error {
+		if !isPDFXVersion(version) {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("PDF/X standard should be one of '%v', got '%s'", PDFXVersions(), version),
+				nil,
+			)
+		}
+		if iccProfilePath == "" {
+			return xerror.Invalid(op, "PDF/X requires an ICC profile for its output intent", nil)
+		}
+		if err := validateICCProfile(iccProfilePath); err != nil {
+			return err
+		}
+		_, err := readICCColorSpace(iccProfilePath)
+		return err
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+convertToPDFX converts the PDF file located at
+fpath to the given PDF/X standard thanks to
+Ghostscript: its colors are converted using the
+given ICC profile, which is also embedded as the
+output intent.
+
+The standard and the ICC profile should have
+been validated beforehand.
+*/
+func convertToPDFX(ctx context.Context, logger xlog.Logger, fpath, version, iccProfilePath string) error {
+	const op string = "printer.convertToPDFX"
+	logger.DebugfOp(op, "converting to '%s' with ICC profile '%s'...", version, iccProfilePath)
+	resolver := func() error {
+		colorSpace, err := readICCColorSpace(iccProfilePath)
+		if err != nil {
+			return err
+		}
+		return ghostscript(
+			ctx,
+			logger,
+			fpath,
+			"-dPDFX",
+			fmt.Sprintf("-sColorConversionStrategy=%s", colorSpace.conversion),
+			fmt.Sprintf("-sProcessColorModel=%s", colorSpace.processColorModel),
+			fmt.Sprintf("-sOutputICCProfile=%s", iccProfilePath),
+			fmt.Sprintf("--permit-file-read=%s", iccProfilePath),
+			"-c",
+			pdfxDefinition(version, iccProfilePath, colorSpace.components),
+			"-f",
+		)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+pdfxDefinition returns the PostScript which declares
+the PDF/X standard and the output intent, in the
+manner of the PDFX_def.ps file shipped with
+Ghostscript.
+*/
+func pdfxDefinition(version, iccProfilePath string, components int) string {
+	return strings.Join([]string{
+		fmt.Sprintf("[ /GTS_PDFXVersion %s /Trapped /False /DOCINFO pdfmark", psString(version)),
+		"[ /_objdef {icc_PDFX} /type /stream /OBJ pdfmark",
+		fmt.Sprintf("[ {icc_PDFX} << /N %d >> /PUT pdfmark", components),
+		fmt.Sprintf("[ {icc_PDFX} %s (r) file /PUT pdfmark", psString(iccProfilePath)),
+		"[ /_objdef {OutputIntent_PDFX} /type /dict /OBJ pdfmark",
+		"[ {OutputIntent_PDFX} << /Type /OutputIntent /S /GTS_PDFX /OutputConditionIdentifier (Custom) /Info (Custom) /DestOutputProfile {icc_PDFX} >> /PUT pdfmark",
+		"[ {Catalog} << /OutputIntents [ {OutputIntent_PDFX} ] >> /PUT pdfmark",
+	}, " ")
+}
+
+// psString returns the given string as a
+// PostScript string literal.
+func psString(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+	return fmt.Sprintf("(%s)", replacer.Replace(s))
+}
+
+func isPDFXVersion(version string) bool {
+	for _, v := range PDFXVersions() {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package printer
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+func TestValidatePDFX(t *testing.T) {
+	writeTmp := func(colorSpace string) string {
+		header := make([]byte, 128)
+		copy(header[16:], colorSpace)
+		copy(header[36:], "acsp")
+		f, err := ioutil.TempFile("", "*.icc")
+		assert.Nil(t, err)
+		_, err = f.Write(header)
+		assert.Nil(t, err)
+		assert.Nil(t, f.Close())
+		return f.Name()
+	}
+	// CMYK ICC profile.
+	fpath := writeTmp("CMYK")
+	defer os.Remove(fpath) // nolint: errcheck
+	assert.Nil(t, validatePDFX(PDFX32002, fpath))
+	// should not be OK as the standard is unknown.
+	err := validatePDFX("PDF/X-1a:2001", fpath)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as there is no ICC profile.
+	err = validatePDFX(PDFX32003, "")
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as the ICC profile
+	// color space is not supported.
+	labFpath := writeTmp("Lab ")
+	defer os.Remove(labFpath) // nolint: errcheck
+	err = validatePDFX(PDFX32003, labFpath)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestPDFXDefinition(t *testing.T) {
+	def := pdfxDefinition(PDFX32002, "/tmp/profile (1).icc", 4)
+	assert.Contains(t, def, "/GTS_PDFXVersion (PDF/X-3:2002)")
+	assert.Contains(t, def, "<< /N 4 >>")
+	assert.Contains(t, def, `(/tmp/profile \(1\).icc) (r) file`)
+}
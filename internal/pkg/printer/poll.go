@@ -0,0 +1,62 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xtime"
+)
+
+/*
+poll calls the given condition every interval
+(in seconds) until it returns true or an error.
+
+The number of attempts is derived from the time
+remaining before the context.Context deadline,
+so that we give up right before it. If the
+condition is still not satisfied after the last
+attempt, returns a xerror.Error with
+xerror.TimeoutCode.
+*/
+func poll(ctx context.Context, interval float64, condition func() (bool, error)) error {
+	const op string = "printer.poll"
+	if interval <= 0.0 {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("poll interval should be > '0', got '%.2f'", interval),
+			nil,
+		)
+	}
+	maxAttempts := 0
+	if deadline, ok := ctx.Deadline(); ok {
+		maxAttempts = int(time.Until(deadline) / xtime.Duration(interval))
+		if maxAttempts < 1 {
+			maxAttempts = 1
+		}
+	}
+	ticker := time.NewTicker(xtime.Duration(interval))
+	defer ticker.Stop()
+	for attempt := 1; ; attempt++ {
+		ok, err := condition()
+		if err != nil {
+			return xerror.New(op, err)
+		}
+		if ok {
+			return nil
+		}
+		if maxAttempts != 0 && attempt >= maxAttempts {
+			return xerror.Timeout(
+				op,
+				fmt.Sprintf("condition not satisfied after '%d' attempt(s)", attempt),
+				nil,
+			)
+		}
+		select {
+		case <-ctx.Done():
+			return xerror.New(op, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package printer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xcontext"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestPoll(t *testing.T) {
+	var (
+		logger   xlog.Logger = test.DebugLogger()
+		attempts int
+		err      error
+	)
+	// condition satisfied right away.
+	ctx, cancel := xcontext.WithTimeout(logger, 1.0)
+	attempts = 0
+	err = poll(ctx, 0.1, func() (bool, error) {
+		attempts++
+		return true, nil
+	})
+	cancel()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, attempts)
+	// condition satisfied after a few attempts.
+	ctx, cancel = xcontext.WithTimeout(logger, 1.0)
+	attempts = 0
+	err = poll(ctx, 0.1, func() (bool, error) {
+		attempts++
+		return attempts == 3, nil
+	})
+	cancel()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+	// should not be OK as condition
+	// is never satisfied.
+	ctx, cancel = xcontext.WithTimeout(logger, 0.5)
+	attempts = 0
+	err = poll(ctx, 0.1, func() (bool, error) {
+		attempts++
+		return false, nil
+	})
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+	// we should give up before the deadline.
+	assert.Nil(t, ctx.Err())
+	assert.True(t, attempts <= 5)
+	cancel()
+	// should not be OK as condition
+	// returns an error.
+	ctx, cancel = xcontext.WithTimeout(logger, 1.0)
+	err = poll(ctx, 0.1, func() (bool, error) {
+		return false, errors.New("foo")
+	})
+	cancel()
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InternalCode, xerror.Code(err))
+	// should not be OK as poll
+	// interval is invalid.
+	ctx, cancel = xcontext.WithTimeout(logger, 1.0)
+	err = poll(ctx, 0.0, func() (bool, error) {
+		return true, nil
+	})
+	cancel()
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
@@ -0,0 +1,206 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/mafredri/cdp"
+	"github.com/mafredri/cdp/devtool"
+	"github.com/mafredri/cdp/rpcc"
+	"github.com/mafredri/cdp/session"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+type pooledConn struct {
+	conn       io.Closer
+	debugURL   string
+	bufferSize int
+	createdAt  time.Time
+	idleSince  time.Time
+}
+
+/*
+devtConn is a connection to the browser target
+of Google Chrome. The page targets are attached
+thanks to its session manager, so that they share
+its WebSocket instead of dialing their own.
+*/
+type devtConn struct {
+	*rpcc.Conn
+	sessions *session.Manager
+}
+
+// Close closes the sessions of the page
+// targets, then the connection.
+func (c *devtConn) Close() error {
+	c.sessions.Close() // nolint: errcheck
+	return c.Conn.Close()
+}
+
+/*
+connPool keeps the connections to Google Chrome
+which are not in use, so that the next conversions
+do not have to dial again.
+
+A connection is closed instead of being handed
+out if it has been idle for too long, if it has
+reached its maximum lifetime or if it does not
+respond anymore.
+
+The connections are only handed out for the
+DevTools endpoint and the write buffer size they
+have been dialed with.
+*/
+type connPool struct {
+	mu      sync.Mutex
+	idle    []*pooledConn
+	dial    func(ctx context.Context, debugURL string, bufferSize int) (io.Closer, error)
+	isAlive func(ctx context.Context, conn io.Closer) bool
+	now     func() time.Time
+}
+
+// nolint: gochecknoglobals
+var devtPool = &connPool{
+	dial:    dialDevtConn,
+	isAlive: isDevtConnAlive,
+	now:     time.Now,
+}
+
+/*
+get returns an idle connection to the given
+DevTools endpoint, with the given write buffer
+size, which is still usable according to the given
+idle timeout and maximum lifetime, or a new one.
+*/
+func (p *connPool) get(ctx context.Context, debugURL string, bufferSize int, idleTimeout, maxLifetime time.Duration) (*pooledConn, error) {
+	const op string = "printer.connPool.get"
+	for {
+		pc := p.pop(debugURL, bufferSize)
+		if pc == nil {
+			break
+		}
+		now := p.now()
+		if now.Sub(pc.idleSince) > idleTimeout || now.Sub(pc.createdAt) > maxLifetime {
+			pc.conn.Close() // nolint: errcheck
+			continue
+		}
+		if !p.isAlive(ctx, pc.conn) {
+			pc.conn.Close() // nolint: errcheck
+			continue
+		}
+		return pc, nil
+	}
+	conn, err := p.dial(ctx, debugURL, bufferSize)
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	return &pooledConn{
+		conn:       conn,
+		debugURL:   debugURL,
+		bufferSize: bufferSize,
+		createdAt:  p.now(),
+	}, nil
+}
+
+/*
+put gives back a connection to the pool. If the
+pool already holds size idle connections, if the
+idle timeout is 0 or if the connection has reached
+its maximum lifetime, the connection is closed
+instead.
+*/
+func (p *connPool) put(pc *pooledConn, size int, idleTimeout, maxLifetime time.Duration) {
+	now := p.now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.idle) >= size || idleTimeout <= 0 || now.Sub(pc.createdAt) > maxLifetime {
+		pc.conn.Close() // nolint: errcheck
+		return
+	}
+	pc.idleSince = now
+	p.idle = append(p.idle, pc)
+}
+
+func (p *connPool) pop(debugURL string, bufferSize int) *pooledConn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// the most recently used connection
+	// is the most likely to be alive.
+	for i := len(p.idle) - 1; i >= 0; i-- {
+		pc := p.idle[i]
+		if pc.debugURL != debugURL || pc.bufferSize != bufferSize {
+			continue
+		}
+		p.idle = append(p.idle[:i], p.idle[i+1:]...)
+		return pc
+	}
+	return nil
+}
+
+/*
+dialDevtConn connects to the browser WebSocket URL
+advertised by the given DevTools endpoint, with the
+given write buffer size, and starts the session
+manager of the connection.
+*/
+func dialDevtConn(ctx context.Context, debugURL string, bufferSize int) (io.Closer, error) {
+	devt, err := devtool.New(debugURL).Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// connect to WebSocket URL (browser) that speaks the Chrome DevTools Protocol.
+	conn, err := rpcc.DialContext(
+		ctx,
+		devt.WebSocketDebuggerURL,
+		/*
+			the messages of the page targets go through
+			this connection.
+			see:
+			https://github.com/thecodingmachine/gotenberg/issues/108
+			https://github.com/mafredri/cdp/issues/4
+			https://github.com/ChromeDevTools/devtools-protocol/issues/24
+		*/
+		rpcc.WithWriteBufferSize(bufferSize),
+		rpcc.WithCompression(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := session.NewManager(cdp.NewClient(conn))
+	if err != nil {
+		conn.Close() // nolint: errcheck
+		return nil, err
+	}
+	return &devtConn{Conn: conn, sessions: sessions}, nil
+}
+
+func validateDebugURL(debugURL string) error {
+	const op string = "printer.validateDebugURL"
+	u, err := url.Parse(debugURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("DevTools endpoint should be an HTTP URL, got '%s'", debugURL),
+			err,
+		)
+	}
+	return nil
+}
+
+/*
+isDevtConnAlive checks that the connection has
+not been closed and that Google Chrome still
+answers a cheap CDP call.
+*/
+func isDevtConnAlive(ctx context.Context, conn io.Closer) bool {
+	dc, ok := conn.(*devtConn)
+	if !ok || dc.Context().Err() != nil {
+		return false
+	}
+	_, err := cdp.NewClient(dc.Conn).Browser.GetVersion(ctx)
+	return err == nil
+}
@@ -0,0 +1,153 @@
+package printer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+type fakeConn struct {
+	id     int
+	alive  bool
+	closed bool
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newFakePool() (*connPool, *fakeClock) {
+	clock := &fakeClock{now: time.Now()}
+	dialed := 0
+	return &connPool{
+		dial: func(ctx context.Context, debugURL string, bufferSize int) (io.Closer, error) {
+			dialed++
+			return &fakeConn{id: dialed, alive: true}, nil
+		},
+		isAlive: func(ctx context.Context, conn io.Closer) bool {
+			return conn.(*fakeConn).alive
+		},
+		now: func() time.Time { return clock.now },
+	}, clock
+}
+
+func TestConnPool(t *testing.T) {
+	const (
+		idleTimeout time.Duration = 30 * time.Second
+		maxLifetime time.Duration = 5 * time.Minute
+		debugURL    string        = "http://localhost:9222"
+		bufferSize  int           = 1048576
+		size        int           = 2
+	)
+	ctx := context.Background()
+	// an idle connection should be reused.
+	pool, clock := newFakePool()
+	pc, err := pool.get(ctx, debugURL, bufferSize, idleTimeout, maxLifetime)
+	assert.Nil(t, err)
+	first := pc.conn.(*fakeConn)
+	pool.put(pc, size, idleTimeout, maxLifetime)
+	clock.advance(10 * time.Second)
+	pc, err = pool.get(ctx, debugURL, bufferSize, idleTimeout, maxLifetime)
+	assert.Nil(t, err)
+	assert.Equal(t, first, pc.conn)
+	assert.False(t, first.closed)
+	// a connection idle for too long
+	// should be closed and recreated.
+	pool.put(pc, size, idleTimeout, maxLifetime)
+	clock.advance(idleTimeout + time.Second)
+	pc, err = pool.get(ctx, debugURL, bufferSize, idleTimeout, maxLifetime)
+	assert.Nil(t, err)
+	assert.True(t, first.closed)
+	assert.NotEqual(t, first, pc.conn)
+	// a stale connection should be
+	// closed and recreated.
+	stale := pc.conn.(*fakeConn)
+	pool.put(pc, size, idleTimeout, maxLifetime)
+	stale.alive = false
+	pc, err = pool.get(ctx, debugURL, bufferSize, idleTimeout, maxLifetime)
+	assert.Nil(t, err)
+	assert.True(t, stale.closed)
+	assert.NotEqual(t, stale, pc.conn)
+	// a connection which has reached its
+	// maximum lifetime should not be pooled.
+	old := pc.conn.(*fakeConn)
+	clock.advance(maxLifetime + time.Second)
+	pool.put(pc, size, idleTimeout, maxLifetime)
+	assert.True(t, old.closed)
+	assert.Empty(t, pool.idle)
+	// a connection should not be pooled
+	// if the idle timeout is 0.
+	pc, err = pool.get(ctx, debugURL, bufferSize, 0, maxLifetime)
+	assert.Nil(t, err)
+	pool.put(pc, size, 0, maxLifetime)
+	assert.True(t, pc.conn.(*fakeConn).closed)
+	assert.Empty(t, pool.idle)
+	// a connection should not be pooled
+	// if the pool is full.
+	var pcs []*pooledConn
+	for i := 0; i < 3; i++ {
+		pc, err = pool.get(ctx, debugURL, bufferSize, idleTimeout, maxLifetime)
+		assert.Nil(t, err)
+		pcs = append(pcs, pc)
+	}
+	for _, pc := range pcs {
+		pool.put(pc, size, idleTimeout, maxLifetime)
+	}
+	assert.Len(t, pool.idle, 2)
+	assert.True(t, pcs[2].conn.(*fakeConn).closed)
+	// a connection should not be handed
+	// out for another DevTools endpoint.
+	pc, err = pool.get(ctx, "http://chrome:9222", bufferSize, idleTimeout, maxLifetime)
+	assert.Nil(t, err)
+	assert.NotEqual(t, pcs[0].conn, pc.conn)
+	assert.NotEqual(t, pcs[1].conn, pc.conn)
+	assert.Len(t, pool.idle, 2)
+	// a connection should not be handed out
+	// for another write buffer size.
+	pc, err = pool.get(ctx, debugURL, 2*bufferSize, idleTimeout, maxLifetime)
+	assert.Nil(t, err)
+	assert.NotEqual(t, pcs[0].conn, pc.conn)
+	assert.NotEqual(t, pcs[1].conn, pc.conn)
+	assert.Len(t, pool.idle, 2)
+	// a connection should not be pooled
+	// if the pool size is 0.
+	pool, _ = newFakePool()
+	pc, err = pool.get(ctx, debugURL, bufferSize, idleTimeout, maxLifetime)
+	assert.Nil(t, err)
+	pool.put(pc, 0, idleTimeout, maxLifetime)
+	assert.True(t, pc.conn.(*fakeConn).closed)
+	assert.Empty(t, pool.idle)
+	// should not be OK as dialing fails.
+	pool, _ = newFakePool()
+	pool.dial = func(ctx context.Context, debugURL string, bufferSize int) (io.Closer, error) {
+		return nil, errors.New("foo")
+	}
+	_, err = pool.get(ctx, debugURL, bufferSize, idleTimeout, maxLifetime)
+	test.AssertError(t, err)
+}
+
+func TestValidateDebugURL(t *testing.T) {
+	// should be OK.
+	err := validateDebugURL("http://localhost:9222")
+	assert.Nil(t, err)
+	// should not be OK as the scheme is not HTTP.
+	err = validateDebugURL("ws://localhost:9222")
+	test.AssertError(t, err)
+	// should not be OK as the host is missing.
+	err = validateDebugURL("foo")
+	test.AssertError(t, err)
+}
@@ -0,0 +1,66 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+/*
+postProcess calls the given function with
+the path of a temporary file located next to
+the PDF file at fpath. Once the function has
+written its result into this temporary file,
+it replaces the original PDF file.
+
+If the function fails, the original PDF file
+is left untouched.
+*/
+func postProcess(fpath string, fn func(tmpDest string) error) error {
+	const op string = "printer.postProcess"
+	tmpDest := fmt.Sprintf("%s/%s.pdf", filepath.Dir(fpath), xrand.Get())
+	resolver := func() error {
+		if err := fn(tmpDest); err != nil {
+			return err
+		}
+		return os.Rename(tmpDest, fpath)
+	}
+	if err := resolver(); err != nil {
+		os.Remove(tmpDest) // nolint: errcheck
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+ghostscript rewrites the PDF file located at
+fpath thanks to the Ghostscript pdfwrite device
+and the given arguments, which come right
+before the PDF file (e.g. "-c <PostScript> -f").
+*/
+func ghostscript(ctx context.Context, logger xlog.Logger, fpath string, args ...string) error {
+	const op string = "printer.ghostscript"
+	err := postProcess(fpath, func(tmpDest string) error {
+		gsArgs := []string{
+			"-dSAFER",
+			"-dBATCH",
+			"-dNOPAUSE",
+			"-dQUIET",
+			"-sDEVICE=pdfwrite",
+			fmt.Sprintf("-sOutputFile=%s", tmpDest),
+		}
+		gsArgs = append(gsArgs, args...)
+		gsArgs = append(gsArgs, fpath)
+		return xexec.Run(ctx, logger, "gs", gsArgs...)
+	})
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
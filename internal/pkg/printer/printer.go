@@ -1,7 +1,15 @@
 package printer
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
 )
 
 // Printer is a type that can create a PDF file from a source.
@@ -10,7 +18,107 @@ type Printer interface {
 	Print(destination string) error
 }
 
+/*
+PrintInDirectory prints into a unique destination
+within the given directory and returns its path.
+The directory is created if it does not exist.
+
+The destination is the base name (".pdf" if it has
+no extension) suffixed with a timestamp and a random
+string, e.g. "report_20060102T150405Z_<random>.pdf".
+*/
+func PrintInDirectory(p Printer, dirPath, baseName string) (string, error) {
+	const op string = "printer.PrintInDirectory"
+	resolver := func() (string, error) {
+		if err := os.MkdirAll(dirPath, 0755); err != nil {
+			return "", err
+		}
+		destination := uniqueDestination(dirPath, baseName, time.Now())
+		if err := p.Print(destination); err != nil {
+			return "", err
+		}
+		return destination, nil
+	}
+	destination, err := resolver()
+	if err != nil {
+		return "", xerror.New(op, err)
+	}
+	return destination, nil
+}
+
+func uniqueDestination(dirPath, baseName string, now time.Time) string {
+	baseName = filepath.Base(baseName)
+	ext := filepath.Ext(baseName)
+	if ext == "" {
+		ext = ".pdf"
+	}
+	return fmt.Sprintf(
+		"%s/%s_%s_%s%s",
+		dirPath,
+		strings.TrimSuffix(baseName, ext),
+		now.UTC().Format("20060102T150405Z"),
+		xrand.Get(),
+		ext,
+	)
+}
+
+/*
+requestOp appends the given request ID (if any)
+to the logical operation, so that an error can be
+correlated with a specific request.
+*/
+func requestOp(op, requestID string) string {
+	if requestID == "" {
+		return op
+	}
+	return fmt.Sprintf("%s[reqID=%s]", op, requestID)
+}
+
+// requestLogger adds the given request ID
+// (if any) to the log entries.
+func requestLogger(logger xlog.Logger, requestID string) xlog.Logger {
+	if requestID == "" {
+		return logger
+	}
+	return logger.WithFields(map[string]interface{}{"request_id": requestID})
+}
+
 func logOptions(logger xlog.Logger, opts interface{}) {
 	const op string = "printer.logOptions"
+	/*
+		the password, the HTTP headers' values (e.g.
+		Authorization) and the cookies' values must
+		not end up in the logs.
+	*/
+	if chromeOpts, ok := opts.(ChromePrinterOptions); ok {
+		if chromeOpts.Password != "" {
+			chromeOpts.Password = "***"
+		}
+		if len(chromeOpts.ExtraHTTPHeaders) > 0 {
+			headers := make(map[string]string, len(chromeOpts.ExtraHTTPHeaders))
+			for name := range chromeOpts.ExtraHTTPHeaders {
+				headers[name] = "***"
+			}
+			chromeOpts.ExtraHTTPHeaders = headers
+		}
+		if len(chromeOpts.Cookies) > 0 {
+			cookies := make([]Cookie, len(chromeOpts.Cookies))
+			for i, cookie := range chromeOpts.Cookies {
+				cookie.Value = "***"
+				cookies[i] = cookie
+			}
+			chromeOpts.Cookies = cookies
+		}
+		opts = chromeOpts
+	}
+	if mergeOpts, ok := opts.(MergePrinterOptions); ok {
+		if mergeOpts.UserPassword != "" {
+			mergeOpts.UserPassword = "***"
+		}
+		if mergeOpts.OwnerPassword != "" {
+			mergeOpts.OwnerPassword = "***"
+		}
+		opts = mergeOpts
+	}
 	logger.DebugfOp(op, "options: %+v", opts)
 }
@@ -0,0 +1,81 @@
+package printer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestRequestOp(t *testing.T) {
+	assert.Equal(t, "printer.foo", requestOp("printer.foo", ""))
+	assert.Equal(t, "printer.foo[reqID=bar]", requestOp("printer.foo", "bar"))
+}
+
+func TestLogOptions(t *testing.T) {
+	// the logger writes to the standard error.
+	logs, err := ioutil.TempFile("", "logs")
+	assert.Nil(t, err)
+	defer os.Remove(logs.Name()) // nolint: errcheck
+	defer logs.Close()           // nolint: errcheck
+	stderr := os.Stderr
+	os.Stderr = logs
+	logger := test.DebugLogger()
+	os.Stderr = stderr
+	opts := DefaultChromePrinterOptions(conf.DefaultConfig())
+	opts.Username = "foo"
+	opts.Password = "passwordsecret"
+	opts.ExtraHTTPHeaders = map[string]string{"Authorization": "Bearer headersecret"}
+	opts.Cookies = []Cookie{{Name: "session", Value: "cookiesecret", Domain: "gotenberg.dev"}}
+	logOptions(logger, opts)
+	// the secrets should not end up in the logs.
+	content, err := ioutil.ReadFile(logs.Name())
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "Authorization")
+	assert.Contains(t, string(content), "session")
+	assert.NotContains(t, string(content), "passwordsecret")
+	assert.NotContains(t, string(content), "headersecret")
+	assert.NotContains(t, string(content), "cookiesecret")
+	// the options should not be altered.
+	assert.Equal(t, "Bearer headersecret", opts.ExtraHTTPHeaders["Authorization"])
+	assert.Equal(t, "cookiesecret", opts.Cookies[0].Value)
+}
+
+type fakePrinter struct{}
+
+func (p fakePrinter) Print(destination string) error {
+	return ioutil.WriteFile(destination, []byte("foo"), 0644)
+}
+
+func TestPrintInDirectory(t *testing.T) {
+	dirPath := fmt.Sprintf("/tmp/%s/foo", xrand.Get())
+	defer os.RemoveAll(filepath.Dir(dirPath)) // nolint: errcheck
+	// should create the directory.
+	dest1, err := PrintInDirectory(fakePrinter{}, dirPath, "report")
+	assert.Nil(t, err)
+	assert.FileExists(t, dest1)
+	assert.Equal(t, dirPath, filepath.Dir(dest1))
+	assert.Equal(t, ".pdf", filepath.Ext(dest1))
+	// should not collide with a previous destination.
+	dest2, err := PrintInDirectory(fakePrinter{}, dirPath, "report")
+	assert.Nil(t, err)
+	assert.NotEqual(t, dest1, dest2)
+}
+
+func TestUniqueDestination(t *testing.T) {
+	now := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	dest := uniqueDestination("/tmp", "report.png", now)
+	assert.True(t, strings.HasPrefix(dest, "/tmp/report_20060102T150405Z_"))
+	assert.Equal(t, ".png", filepath.Ext(dest))
+	dest = uniqueDestination("/tmp", "../report", now)
+	assert.True(t, strings.HasPrefix(dest, "/tmp/report_20060102T150405Z_"))
+	assert.Equal(t, ".pdf", filepath.Ext(dest))
+}
@@ -0,0 +1,209 @@
+package printer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+/*
+pdfDict is a PDF dictionary in the qpdf JSON
+format (version 2), e.g. names are "/Type" and
+strings "u:foo". Its values are kept as is, so
+that they are written back unchanged.
+*/
+type pdfDict map[string]json.RawMessage
+
+/*
+qpdfJSON is the qpdf JSON output (version 2)
+restricted to the objects and the pages.
+*/
+type qpdfJSON struct {
+	Pages []struct {
+		Object string `json:"object"`
+	} `json:"pages"`
+	QPDF []json.RawMessage `json:"qpdf"`
+}
+
+type qpdfObject struct {
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+/*
+pdfObjects describes the given objects of a PDF,
+as read by readPDFObjects.
+*/
+type pdfObjects struct {
+	// header is the qpdf JSON header, which an
+	// update requires.
+	header json.RawMessage
+	// pages are the references of the page
+	// objects, e.g. "3 0 R".
+	pages []string
+	// dicts are the dictionaries of the given
+	// objects, by reference or "trailer".
+	dicts map[string]pdfDict
+	// values are the values of all the objects
+	// read but the streams, by reference.
+	values map[string]json.RawMessage
+}
+
+/*
+readPDFObjects returns the pages and the given
+objects (either a reference like "1 0 R" or
+"trailer") of the PDF file located at fpath
+thanks to qpdf JSON output. If no object is
+given, all of them are read.
+
+The given objects should be dictionaries.
+*/
+func readPDFObjects(ctx context.Context, logger xlog.Logger, fpath string, refs ...string) (pdfObjects, error) {
+	const op string = "printer.readPDFObjects"
+	resolver := func() (pdfObjects, error) {
+		args := []string{"--json=2", "--json-key=qpdf", "--json-key=pages"}
+		for _, ref := range refs {
+			args = append(args, fmt.Sprintf("--json-object=%s", qpdfObjectArg(ref)))
+		}
+		args = append(args, fpath)
+		cmd := exec.CommandContext(ctx, "qpdf", args...)
+		xexec.LogBeforeExecute(logger, cmd)
+		out, err := cmd.Output()
+		if err != nil {
+			return pdfObjects{}, err
+		}
+		var result qpdfJSON
+		if err := json.Unmarshal(out, &result); err != nil {
+			return pdfObjects{}, err
+		}
+		if len(result.QPDF) != 2 {
+			return pdfObjects{}, xerror.Internal(op, "unexpected qpdf JSON output", nil)
+		}
+		var objects map[string]qpdfObject
+		if err := json.Unmarshal(result.QPDF[1], &objects); err != nil {
+			return pdfObjects{}, err
+		}
+		read := pdfObjects{
+			header: result.QPDF[0],
+			dicts:  make(map[string]pdfDict),
+			values: make(map[string]json.RawMessage),
+		}
+		for key, object := range objects {
+			if strings.HasPrefix(key, "obj:") && object.Value != nil {
+				read.values[strings.TrimPrefix(key, "obj:")] = object.Value
+			}
+		}
+		for _, page := range result.Pages {
+			read.pages = append(read.pages, page.Object)
+		}
+		for _, ref := range refs {
+			var dict pdfDict
+			object, ok := objects[qpdfObjectKey(ref)]
+			if !ok || json.Unmarshal(object.Value, &dict) != nil || dict == nil {
+				return pdfObjects{}, xerror.Internal(op, fmt.Sprintf("unable to find the dictionary '%s'", ref), nil)
+			}
+			read.dicts[ref] = dict
+		}
+		return read, nil
+	}
+	result, err := resolver()
+	if err != nil {
+		return pdfObjects{}, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+/*
+updatePDFObjects replaces the given objects of
+the PDF file located at fpath, keyed by
+reference, thanks to qpdf JSON update mode.
+The other objects are left unchanged.
+*/
+func updatePDFObjects(ctx context.Context, logger xlog.Logger, fpath string, header json.RawMessage, values map[string]interface{}) error {
+	const op string = "printer.updatePDFObjects"
+	resolver := func() error {
+		objects := make(map[string]map[string]interface{})
+		for ref, value := range values {
+			objects[qpdfObjectKey(ref)] = map[string]interface{}{"value": value}
+		}
+		b, err := json.Marshal(map[string][]interface{}{"qpdf": {header, objects}})
+		if err != nil {
+			return err
+		}
+		updatePath := fmt.Sprintf("%s/%s.json", filepath.Dir(fpath), xrand.Get())
+		if err := ioutil.WriteFile(updatePath, b, 0644); err != nil {
+			return err
+		}
+		defer os.Remove(updatePath) // nolint: errcheck
+		return postProcess(fpath, func(tmpDest string) error {
+			return xexec.Run(ctx, logger, "qpdf", fpath, fmt.Sprintf("--update-from-json=%s", updatePath), tmpDest)
+		})
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+pdfRef returns the reference held by the given
+value of a dictionary, e.g. "1 0 R".
+*/
+func pdfRef(value json.RawMessage) (string, bool) {
+	var ref string
+	if err := json.Unmarshal(value, &ref); err != nil || !strings.HasSuffix(ref, " R") {
+		return "", false
+	}
+	return ref, true
+}
+
+/*
+pdfString returns the given text as a PDF text
+string value.
+*/
+func pdfString(text string) json.RawMessage {
+	b, _ := json.Marshal(fmt.Sprintf("u:%s", text)) // nolint: errcheck
+	return b
+}
+
+/*
+pdfRectangle returns the given region as a PDF
+rectangle value.
+*/
+func pdfRectangle(region CropRegion) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(
+		"[%s, %s, %s, %s]",
+		formatPDFNumber(region.Left),
+		formatPDFNumber(region.Bottom),
+		formatPDFNumber(region.Right),
+		formatPDFNumber(region.Top),
+	))
+}
+
+// qpdfObjectKey returns the key of the given
+// object in qpdf JSON, e.g. "obj:1 0 R".
+func qpdfObjectKey(ref string) string {
+	if ref == "trailer" {
+		return ref
+	}
+	return fmt.Sprintf("obj:%s", ref)
+}
+
+// qpdfObjectArg returns the value of --json-object
+// for the given object, e.g. "1,0".
+func qpdfObjectArg(ref string) string {
+	fields := strings.Fields(ref)
+	if len(fields) != 3 {
+		return ref
+	}
+	return fmt.Sprintf("%s,%s", fields[0], fields[1])
+}
@@ -0,0 +1,156 @@
+package printer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xassert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+const (
+	// TopLeftQRCodePosition displays the QR
+	// code in the top left corner (header).
+	TopLeftQRCodePosition string = "top-left"
+	// TopRightQRCodePosition displays the QR
+	// code in the top right corner (header).
+	TopRightQRCodePosition string = "top-right"
+	// BottomLeftQRCodePosition displays the QR
+	// code in the bottom left corner (footer).
+	BottomLeftQRCodePosition string = "bottom-left"
+	// BottomRightQRCodePosition displays the QR
+	// code in the bottom right corner (footer).
+	BottomRightQRCodePosition string = "bottom-right"
+)
+
+// QRCodePositions returns a slice containing
+// all available QR code positions.
+func QRCodePositions() []string {
+	return []string{
+		TopLeftQRCodePosition,
+		TopRightQRCodePosition,
+		BottomLeftQRCodePosition,
+		BottomRightQRCodePosition,
+	}
+}
+
+const (
+	// qrCodeModuleSize is the size in pixels of a
+	// module of the QR code image, which is then
+	// scaled to the QRCodeSize.
+	qrCodeModuleSize int = 8
+	// qrCodeMaxContentBytes is the capacity of a
+	// version 10 QR code with the medium error
+	// correction: larger codes do not remain
+	// readable once printed in a corner.
+	qrCodeMaxContentBytes int = 213
+)
+
+/*
+validateQRCode checks the position and the size
+of the QR code.
+*/
+func validateQRCode(position string, size float64) error {
+	const op string = "printer.validateQRCode"
+	if _, err := xassert.String(
+		"qrCodePosition",
+		position,
+		"",
+		xassert.StringOneOf(QRCodePositions()),
+	); err != nil {
+		return xerror.New(op, err)
+	}
+	if size <= 0 {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("QR code size should be > '0', got '%.2f'", size),
+			nil,
+		)
+	}
+	return nil
+}
+
+/*
+qrCodeHTML returns an image element displaying a
+QR code of the given content in the given corner
+of the header or the footer, the size being in
+inches.
+*/
+func qrCodeHTML(content, position string, size float64) (string, error) {
+	const op string = "printer.qrCodeHTML"
+	resolver := func() (string, error) {
+		if len(content) > qrCodeMaxContentBytes {
+			return "", xerror.Invalid(
+				op,
+				fmt.Sprintf("content of '%d' bytes is too long for a QR code, maximum is '%d'", len(content), qrCodeMaxContentBytes),
+				nil,
+			)
+		}
+		code, err := qrcode.New(content, qrcode.Medium)
+		if err != nil {
+			return "", err
+		}
+		// a negative size sets the size of a module.
+		data, err := code.PNG(-qrCodeModuleSize)
+		if err != nil {
+			return "", err
+		}
+		vertical, horizontal := "bottom", "right"
+		if strings.HasPrefix(position, "top") {
+			vertical = "top"
+		}
+		if strings.HasSuffix(position, "left") {
+			horizontal = "left"
+		}
+		return fmt.Sprintf(
+			"<img src=\"data:image/png;base64,%s\" style=\"position: absolute; %s: 0; %s: 0.4in; width: %gin; height: %gin; image-rendering: pixelated;\">",
+			base64.StdEncoding.EncodeToString(data),
+			vertical,
+			horizontal,
+			size,
+			size,
+		), nil
+	}
+	result, err := resolver()
+	if err != nil {
+		return "", xerror.New(op, err)
+	}
+	return result, nil
+}
+
+// withQRCode appends the given QR code image
+// to the body of the given template.
+func withQRCode(template, img string) string {
+	if i := strings.LastIndex(strings.ToLower(template), "</body>"); i >= 0 {
+		return template[:i] + img + template[i:]
+	}
+	return template + img
+}
+
+/*
+headerFooterHTML returns the header and footer
+templates, one of them holding the QR code (if
+requested). The QR code content defaults to the
+URL of the page.
+*/
+func (p chromePrinter) headerFooterHTML() (string, string, error) {
+	const op string = "printer.chromePrinter.headerFooterHTML"
+	header, footer := p.opts.headerHTML(), p.opts.footerHTML()
+	if !p.opts.QRCode {
+		return header, footer, nil
+	}
+	content := p.opts.QRCodeContent
+	if content == "" {
+		content = p.pageURL()
+	}
+	img, err := qrCodeHTML(content, p.opts.QRCodePosition, p.opts.QRCodeSize)
+	if err != nil {
+		return "", "", xerror.New(op, err)
+	}
+	if strings.HasPrefix(p.opts.QRCodePosition, "top") {
+		return withQRCode(header, img), footer, nil
+	}
+	return header, withQRCode(footer, img), nil
+}
@@ -0,0 +1,55 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestValidateQRCode(t *testing.T) {
+	assert.Nil(t, validateQRCode(BottomRightQRCodePosition, 0.6))
+	// should not be OK as the
+	// position is unknown.
+	err := validateQRCode("middle", 0.6)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as the
+	// size is not positive.
+	err = validateQRCode(TopLeftQRCodePosition, 0)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestChromePrinterHeaderFooterHTML(t *testing.T) {
+	opts := DefaultChromePrinterOptions(conf.DefaultConfig())
+	p := chromePrinter{url: "https://gotenberg.dev", opts: opts}
+	// no QR code.
+	header, footer, err := p.headerFooterHTML()
+	assert.Nil(t, err)
+	assert.Equal(t, defaultHeaderFooterHTML, header)
+	assert.Equal(t, defaultHeaderFooterHTML, footer)
+	// a QR code in the footer.
+	p.opts.QRCode = true
+	header, footer, err = p.headerFooterHTML()
+	assert.Nil(t, err)
+	assert.Equal(t, defaultHeaderFooterHTML, header)
+	assert.True(t, strings.HasSuffix(footer, "</body></html>"))
+	assert.Contains(t, footer, "<img src=\"data:image/png;base64,")
+	assert.Contains(t, footer, "bottom: 0; right: 0.4in; width: 0.6in;")
+	// a QR code in the header.
+	p.opts.QRCodePosition = TopLeftQRCodePosition
+	header, footer, err = p.headerFooterHTML()
+	assert.Nil(t, err)
+	assert.Contains(t, header, "top: 0; left: 0.4in;")
+	assert.Equal(t, defaultHeaderFooterHTML, footer)
+	// should not be OK as the content
+	// is too long for a QR code.
+	p.opts.QRCodeContent = strings.Repeat("a", 300)
+	_, _, err = p.headerFooterHTML()
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
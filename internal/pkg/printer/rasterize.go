@@ -0,0 +1,262 @@
+package printer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+type rasterizePrinter struct {
+	logger xlog.Logger
+	fpath  string
+	opts   RasterizePrinterOptions
+}
+
+// RasterizePrinterOptions helps customizing the
+// rasterize Printer behaviour.
+type RasterizePrinterOptions struct {
+	WaitTimeout  float64
+	Resolution   int64
+	MaxWorkers   int64
+	RequestID    string
+	DryRun       bool
+	MaxProcesses int64
+}
+
+// DefaultRasterizePrinterOptions returns the default
+// rasterize Printer options.
+func DefaultRasterizePrinterOptions(config conf.Config) RasterizePrinterOptions {
+	return RasterizePrinterOptions{
+		WaitTimeout:  config.DefaultWaitTimeout(),
+		Resolution:   150,
+		MaxWorkers:   int64(runtime.NumCPU()),
+		RequestID:    "",
+		DryRun:       false,
+		MaxProcesses: config.MaximumConcurrentProcesses(),
+	}
+}
+
+/*
+NewRasterizePrinter returns a Printer which
+is able to rasterize the pages of a PDF into
+PNG images.
+
+Each page is written next to the destination
+with a numbered suffix (e.g. "result_1.png").
+The page range is split across at most
+MaxWorkers pdftoppm processes running
+simultaneously, each of them waiting for a slot
+of the MaxProcesses limit.
+
+If the DryRun option is set, the pdftoppm
+commands are not executed: the Printer returns a
+xexec.DryRunError holding one of them instead.
+The number of pages is still read thanks to
+pdfinfo, as the commands depend on it.
+*/
+func NewRasterizePrinter(logger xlog.Logger, fpath string, opts RasterizePrinterOptions) Printer {
+	return rasterizePrinter{
+		logger: requestLogger(logger, opts.RequestID),
+		fpath:  fpath,
+		opts:   opts,
+	}
+}
+
+// pageRange is an inclusive range of pages.
+type pageRange struct {
+	first int
+	last  int
+}
+
+func (p rasterizePrinter) Print(destination string) error {
+	const op string = "printer.rasterizePrinter.Print"
+	logOptions(p.logger, p.opts)
+	ctx, cancel := withTimeout(p.logger, p.opts.WaitTimeout)
+	defer cancel()
+	if p.opts.DryRun {
+		ctx = xexec.WithDryRun(ctx)
+	}
+	resolver := func() error {
+		if err := p.opts.validate(); err != nil {
+			return err
+		}
+		if err := commandLimiter.acquire(ctx, p.opts.MaxProcesses); err != nil {
+			return err
+		}
+		pages, err := pageCount(ctx, p.logger, p.fpath)
+		commandLimiter.release()
+		if err != nil {
+			return err
+		}
+		ranges := pageRanges(pages, int(p.opts.MaxWorkers))
+		p.logger.DebugfOp(op, "rasterizing '%d' page(s) of '%s' with '%d' worker(s)...", pages, p.fpath, len(ranges))
+		var workers []func() error
+		for _, r := range ranges {
+			r := r
+			// each pdftoppm process requires a slot.
+			workers = append(workers, func() error {
+				if err := commandLimiter.acquire(ctx, p.opts.MaxProcesses); err != nil {
+					return err
+				}
+				defer commandLimiter.release()
+				return p.rasterize(ctx, r, pages, destination)
+			})
+		}
+		return runBatch(workers...)
+	}
+	if err := resolver(); err != nil {
+		return mustHandleError(
+			ctx,
+			xerror.New(requestOp(op, p.opts.RequestID), err),
+		)
+	}
+	return nil
+}
+
+func (opts RasterizePrinterOptions) validate() error {
+	const op string = "printer.RasterizePrinterOptions.validate"
+	if opts.Resolution <= 0 {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("resolution should be > '0', got '%d'", opts.Resolution),
+			nil,
+		)
+	}
+	if opts.MaxWorkers <= 0 {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("maximum workers should be > '0', got '%d'", opts.MaxWorkers),
+			nil,
+		)
+	}
+	return nil
+}
+
+/*
+rasterize renders the given page range thanks to
+pdftoppm, then moves each resulting image to its
+numbered destination.
+*/
+func (p rasterizePrinter) rasterize(ctx context.Context, r pageRange, pages int, destination string) error {
+	const op string = "printer.rasterizePrinter.rasterize"
+	prefix := fmt.Sprintf("%s/%s", filepath.Dir(destination), xrand.Get())
+	resolver := func() error {
+		if err := xexec.Run(
+			ctx,
+			p.logger,
+			"pdftoppm",
+			"-f", strconv.Itoa(r.first),
+			"-l", strconv.Itoa(r.last),
+			"-r", strconv.FormatInt(p.opts.Resolution, 10),
+			"-png",
+			p.fpath,
+			prefix,
+		); err != nil {
+			return err
+		}
+		for page := r.first; page <= r.last; page++ {
+			if err := os.Rename(
+				pdftoppmFpath(prefix, page, pages),
+				numberedDestination(destination, page),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+pdftoppmFpath returns the path of the image
+written by pdftoppm for the given page. pdftoppm
+pads the page number with as many digits as
+the total number of pages has.
+*/
+func pdftoppmFpath(prefix string, page, pages int) string {
+	return fmt.Sprintf("%s-%0*d.png", prefix, len(strconv.Itoa(pages)), page)
+}
+
+/*
+pageRanges splits the pages 1 to pages into at
+most workers contiguous ranges of nearly equal
+size.
+*/
+func pageRanges(pages, workers int) []pageRange {
+	if pages <= 0 || workers <= 0 {
+		return nil
+	}
+	if workers > pages {
+		workers = pages
+	}
+	ranges := make([]pageRange, 0, workers)
+	first := 1
+	for i := 0; i < workers; i++ {
+		// spread the remaining pages over the first ranges.
+		size := pages / workers
+		if i < pages%workers {
+			size++
+		}
+		ranges = append(ranges, pageRange{first: first, last: first + size - 1})
+		first += size
+	}
+	return ranges
+}
+
+/*
+pageCount returns the number of pages of
+the PDF file located at fpath thanks to pdfinfo.
+*/
+func pageCount(ctx context.Context, logger xlog.Logger, fpath string) (int, error) {
+	const (
+		op          string = "printer.pageCount"
+		pagesPrefix string = "Pages:"
+	)
+	resolver := func() (int, error) {
+		cmd := exec.CommandContext(ctx, "pdfinfo", fpath)
+		xexec.LogBeforeExecute(logger, cmd)
+		out, err := cmd.Output()
+		if err != nil {
+			return 0, err
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, pagesPrefix) {
+				continue
+			}
+			return strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, pagesPrefix)))
+		}
+		return 0, xerror.Invalid(
+			op,
+			fmt.Sprintf("unable to find the number of pages of '%s'", fpath),
+			nil,
+		)
+	}
+	result, err := resolver()
+	if err != nil {
+		return 0, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+// Compile-time checks to ensure type implements desired interfaces.
+var (
+	_ = Printer(new(rasterizePrinter))
+)
@@ -0,0 +1,135 @@
+package printer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestRasterizePrinter(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+		fpath  string      = test.RasterizeFpath(t)
+		opts   RasterizePrinterOptions
+		p      Printer
+		err    error
+	)
+	// sequential and parallel rasterizations
+	// should give the same images.
+	opts = DefaultRasterizePrinterOptions(config)
+	opts.MaxWorkers = 1
+	p = NewRasterizePrinter(logger, fpath, opts)
+	sequentialDest := rasterizeDestination()
+	err = p.Print(sequentialDest)
+	assert.Nil(t, err)
+	sequential := rasterizedImages(t, sequentialDest)
+	assert.NotEmpty(t, sequential)
+	opts = DefaultRasterizePrinterOptions(config)
+	opts.MaxWorkers = 4
+	p = NewRasterizePrinter(logger, fpath, opts)
+	parallelDest := rasterizeDestination()
+	err = p.Print(parallelDest)
+	assert.Nil(t, err)
+	parallel := rasterizedImages(t, parallelDest)
+	assert.Equal(t, sequential, parallel)
+	// the workers should wait for the slots
+	// of the processes limit.
+	opts = DefaultRasterizePrinterOptions(config)
+	opts.MaxWorkers = 4
+	opts.MaxProcesses = 1
+	p = NewRasterizePrinter(logger, fpath, opts)
+	limitedDest := rasterizeDestination()
+	err = p.Print(limitedDest)
+	assert.Nil(t, err)
+	limited := rasterizedImages(t, limitedDest)
+	assert.Equal(t, sequential, limited)
+	// should not be OK as the
+	// resolution is invalid.
+	opts = DefaultRasterizePrinterOptions(config)
+	opts.Resolution = 0
+	p = NewRasterizePrinter(logger, fpath, opts)
+	err = p.Print(rasterizeDestination())
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as context.Context
+	// should timeout.
+	opts = DefaultRasterizePrinterOptions(config)
+	opts.WaitTimeout = 0.0
+	p = NewRasterizePrinter(logger, fpath, opts)
+	err = p.Print(rasterizeDestination())
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+}
+
+func TestPageRanges(t *testing.T) {
+	assert.Equal(t, []pageRange{{1, 10}}, pageRanges(10, 1))
+	assert.Equal(t, []pageRange{{1, 4}, {5, 7}, {8, 10}}, pageRanges(10, 3))
+	assert.Equal(t, []pageRange{{1, 1}, {2, 2}}, pageRanges(2, 4))
+	assert.Nil(t, pageRanges(0, 4))
+}
+
+func TestPdftoppmFpath(t *testing.T) {
+	assert.Equal(t, "/tmp/foo-7.png", pdftoppmFpath("/tmp/foo", 7, 9))
+	assert.Equal(t, "/tmp/foo-007.png", pdftoppmFpath("/tmp/foo", 7, 500))
+}
+
+func BenchmarkRasterizePrinter(b *testing.B) {
+	var (
+		logger xlog.Logger = test.ErrorLogger()
+		config conf.Config = conf.DefaultConfig()
+		fpath  string      = test.RasterizeFpath(b)
+	)
+	for _, workers := range []int64{1, 4} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			opts := DefaultRasterizePrinterOptions(config)
+			opts.MaxWorkers = workers
+			p := NewRasterizePrinter(logger, fpath, opts)
+			for i := 0; i < b.N; i++ {
+				dest := rasterizeDestination()
+				if err := p.Print(dest); err != nil {
+					b.Fatal(err)
+				}
+				removeRasterizedImages(dest)
+			}
+		})
+	}
+}
+
+func rasterizeDestination() string {
+	return strings.TrimSuffix(test.GenerateDestination(), ".pdf") + ".png"
+}
+
+// rasterizedImages returns the content of each
+// image of the given destination, then removes them.
+func rasterizedImages(t *testing.T, destination string) [][]byte {
+	var images [][]byte
+	for page := 1; ; page++ {
+		fpath := numberedDestination(destination, page)
+		if _, err := os.Stat(fpath); os.IsNotExist(err) {
+			break
+		}
+		content, err := ioutil.ReadFile(fpath)
+		assert.Nil(t, err)
+		images = append(images, content)
+	}
+	removeRasterizedImages(destination)
+	return images
+}
+
+func removeRasterizedImages(destination string) {
+	ext := filepath.Ext(destination)
+	fpaths, _ := filepath.Glob(fmt.Sprintf("%s_*%s", strings.TrimSuffix(destination, ext), ext))
+	for _, fpath := range fpaths {
+		os.Remove(fpath) // nolint: errcheck
+	}
+}
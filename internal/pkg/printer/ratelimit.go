@@ -0,0 +1,170 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+/*
+validateWriteRateLimit returns a xerror.Error with
+xerror.InvalidCode if the given number of bytes
+per second is negative.
+*/
+func validateWriteRateLimit(bytesPerSecond int64) error {
+	const op string = "printer.validateWriteRateLimit"
+	if bytesPerSecond < 0 {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("write rate limit should be >= '0', got '%d'", bytesPerSecond),
+			nil,
+		)
+	}
+	return nil
+}
+
+/*
+rateLimitedWriter writes to the underlying
+io.Writer at most bytesPerSecond bytes per
+second, on average since its first write.
+*/
+type rateLimitedWriter struct {
+	ctx            context.Context
+	w              io.Writer
+	bytesPerSecond int64
+	start          time.Time
+	written        int64
+}
+
+/*
+newRateLimitedWriter returns an io.Writer which
+writes to w at most bytesPerSecond bytes per
+second. If bytesPerSecond is 0, it returns w.
+
+The writes stop as soon as the given
+context.Context is done.
+*/
+func newRateLimitedWriter(ctx context.Context, w io.Writer, bytesPerSecond int64) io.Writer {
+	if bytesPerSecond <= 0 {
+		return w
+	}
+	return &rateLimitedWriter{
+		ctx:            ctx,
+		w:              w,
+		bytesPerSecond: bytesPerSecond,
+	}
+}
+
+func (rw *rateLimitedWriter) Write(p []byte) (int, error) {
+	if rw.start.IsZero() {
+		rw.start = time.Now()
+	}
+	var n int
+	for n < len(p) {
+		// write at most one second worth of bytes at once.
+		chunk := p[n:]
+		if int64(len(chunk)) > rw.bytesPerSecond {
+			chunk = chunk[:rw.bytesPerSecond]
+		}
+		if err := rw.wait(); err != nil {
+			return n, err
+		}
+		written, err := rw.w.Write(chunk)
+		n += written
+		rw.written += int64(written)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+/*
+wait blocks until the bytes written so far
+are within the rate limit.
+*/
+func (rw *rateLimitedWriter) wait() error {
+	due := rw.start.Add(time.Duration(float64(rw.written) / float64(rw.bytesPerSecond) * float64(time.Second)))
+	delay := time.Until(due)
+	if delay <= 0 {
+		return rw.ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-rw.ctx.Done():
+		return rw.ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+/*
+writeFile writes the content of r to the file
+located at fpath, at most bytesPerSecond bytes
+per second (if any).
+*/
+func writeFile(ctx context.Context, fpath string, r io.Reader, bytesPerSecond int64) error {
+	const op string = "printer.writeFile"
+	resolver := func() error {
+		f, err := os.Create(fpath)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(newRateLimitedWriter(ctx, f, bytesPerSecond), r); err != nil {
+			f.Close() // nolint: errcheck
+			return err
+		}
+		return f.Close()
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+withRateLimit calls the given function with the
+destination it should write its result into.
+
+If bytesPerSecond is 0, it is the destination
+itself. Otherwise, it is a temporary file of the
+system temporary directory, which is then written
+to the destination at most bytesPerSecond bytes
+per second, so that the tools writing the result
+do not compete with other writes at full speed.
+*/
+func withRateLimit(ctx context.Context, destination string, bytesPerSecond int64, fn func(destination string) error) error {
+	const op string = "printer.withRateLimit"
+	if bytesPerSecond <= 0 {
+		return fn(destination)
+	}
+	tmpDest := fmt.Sprintf("%s/%s%s", os.TempDir(), xrand.Get(), filepath.Ext(destination))
+	defer os.Remove(tmpDest) // nolint: errcheck
+	resolver := func() error {
+		if err := fn(tmpDest); err != nil {
+			return err
+		}
+		f, err := os.Open(tmpDest)
+		if err != nil {
+			return err
+		}
+		defer f.Close() // nolint: errcheck
+		if err := writeFile(ctx, destination, f, bytesPerSecond); err != nil {
+			// do not leave a partial file behind.
+			os.Remove(destination) // nolint: errcheck
+			return err
+		}
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
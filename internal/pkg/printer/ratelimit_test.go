@@ -0,0 +1,75 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestValidateWriteRateLimit(t *testing.T) {
+	assert.Nil(t, validateWriteRateLimit(0))
+	assert.Nil(t, validateWriteRateLimit(1048576))
+	err := validateWriteRateLimit(-1)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestRateLimitedWriter(t *testing.T) {
+	var buffer bytes.Buffer
+	// no rate limit.
+	assert.Equal(t, &buffer, newRateLimitedWriter(context.Background(), &buffer, 0))
+	// the second half should wait for a second.
+	w := newRateLimitedWriter(context.Background(), &buffer, 1000)
+	start := time.Now()
+	n, err := w.Write(make([]byte, 1500))
+	assert.Nil(t, err)
+	assert.Equal(t, 1500, n)
+	assert.Equal(t, 1500, buffer.Len())
+	assert.True(t, time.Since(start) >= time.Second)
+	// should stop once the context.Context is done.
+	buffer.Reset()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	w = newRateLimitedWriter(ctx, &buffer, 1000)
+	n, err = w.Write(make([]byte, 5000))
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 1000, n)
+}
+
+func TestWithRateLimit(t *testing.T) {
+	destination := fmt.Sprintf("%s/%s.pdf", os.TempDir(), xrand.Get())
+	defer os.Remove(destination) // nolint: errcheck
+	write := func(fpath string) error {
+		return ioutil.WriteFile(fpath, []byte("foo"), 0644)
+	}
+	// no rate limit: the destination is written as is.
+	var written string
+	err := withRateLimit(context.Background(), destination, 0, func(fpath string) error {
+		written = fpath
+		return write(fpath)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, destination, written)
+	// with a rate limit: a temporary file is
+	// written, then copied to the destination.
+	err = withRateLimit(context.Background(), destination, 1000, func(fpath string) error {
+		written = fpath
+		return write(fpath)
+	})
+	assert.Nil(t, err)
+	assert.NotEqual(t, destination, written)
+	_, err = os.Stat(written)
+	assert.True(t, os.IsNotExist(err))
+	b, err := ioutil.ReadFile(destination)
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", string(b))
+}
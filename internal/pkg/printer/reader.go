@@ -0,0 +1,85 @@
+package printer
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mafredri/cdp"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+/*
+readerModeScript is a Readability-style extraction:
+it scores the elements containing paragraphs by the
+length of their text, favoring <article> and <main>,
+and replaces the body by a cleaned-up copy of the
+best candidate. It returns false if no candidate has
+enough text, leaving the page untouched.
+*/
+const readerModeScript string = `(() => {
+	const minTextLength = 250;
+	const noise = 'script, style, noscript, iframe, nav, aside, footer, form, button, [role="navigation"], [role="complementary"], [aria-hidden="true"]';
+	const scores = new Map();
+	for (const p of document.querySelectorAll('p, pre, blockquote')) {
+		const length = p.textContent.trim().length;
+		if (length < 25) {
+			continue;
+		}
+		for (let el = p.parentElement, depth = 0; el !== null && el !== document.body && depth < 3; el = el.parentElement, depth++) {
+			scores.set(el, (scores.get(el) || 0) + length / (depth + 1));
+		}
+	}
+	let best = null;
+	let bestScore = 0;
+	for (const [el, score] of scores) {
+		const weighted = /^(ARTICLE|MAIN)$/.test(el.tagName) ? score * 1.5 : score;
+		if (weighted > bestScore) {
+			best = el;
+			bestScore = weighted;
+		}
+	}
+	if (best === null || best.textContent.trim().length < minTextLength) {
+		return false;
+	}
+	const content = best.cloneNode(true);
+	content.querySelectorAll(noise).forEach(el => el.remove());
+	const container = document.createElement('article');
+	if (document.title) {
+		const title = document.createElement('h1');
+		title.textContent = document.title;
+		container.appendChild(title);
+	}
+	container.appendChild(content);
+	document.body.innerHTML = '';
+	document.body.appendChild(container);
+	const style = document.createElement('style');
+	style.textContent = 'body { max-width: 40em; margin: 0 auto; font-family: Georgia, serif; font-size: 12pt; line-height: 1.5; } img, video, figure { max-width: 100%; height: auto; } [style*="position: fixed"], [style*="position:fixed"] { position: static !important; }';
+	(document.head || document.documentElement).appendChild(style);
+	return true;
+})()`
+
+/*
+applyReaderMode simplifies the page to its main
+content. If the extraction fails, it falls back
+to the full page.
+*/
+func (p chromePrinter) applyReaderMode(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.chromePrinter.applyReaderMode"
+	p.logger.DebugOp(op, "extracting the main content...")
+	result, err := evaluate(ctx, client, readerModeScript)
+	if err != nil {
+		// the page may have been canceled or timed out.
+		if ctx.Err() != nil {
+			return xerror.New(op, err)
+		}
+		p.logger.DebugfOp(op, "extraction failed, printing the full page: %s", err.Error())
+		return nil
+	}
+	var extracted bool
+	if err := json.Unmarshal(result.Value, &extracted); err != nil || !extracted {
+		p.logger.DebugOp(op, "no main content found, printing the full page")
+		return nil
+	}
+	p.logger.DebugOp(op, "main content extracted")
+	return nil
+}
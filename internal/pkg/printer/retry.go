@@ -0,0 +1,114 @@
+package printer
+
+import (
+	"time"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xtime"
+)
+
+// RetryOptions helps customizing how
+// a Printer is retried.
+type RetryOptions struct {
+	MaxRetries int64
+	Backoff    float64
+}
+
+// DefaultRetryOptions returns the default
+// retry options, i.e. no retry.
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{
+		MaxRetries: 0,
+		Backoff:    1.0,
+	}
+}
+
+/*
+RetryDecorator decorates Printers so that a
+failed conversion is retried.
+
+A single decorator may serve every conversion:
+its options are the default retry policy, which
+a conversion may override as a whole.
+*/
+type RetryDecorator struct {
+	logger xlog.Logger
+	opts   RetryOptions
+}
+
+// NewRetryDecorator returns a RetryDecorator
+// with the given default retry policy.
+func NewRetryDecorator(logger xlog.Logger, opts RetryOptions) RetryDecorator {
+	return RetryDecorator{
+		logger: logger,
+		opts:   opts,
+	}
+}
+
+type retryPrinter struct {
+	logger  xlog.Logger
+	printer Printer
+	opts    RetryOptions
+	sleep   func(d time.Duration)
+}
+
+/*
+Decorate returns a Printer which retries the
+given Printer up to MaxRetries times, waiting
+Backoff seconds before the first retry and twice
+as long before each following one.
+
+If override is not nil, it takes precedence over
+the options of the decorator. Otherwise, they
+apply.
+
+Errors with xerror.InvalidCode or
+xerror.CanceledCode are not retried, as another
+attempt would fail the same way.
+*/
+func (d RetryDecorator) Decorate(p Printer, override *RetryOptions) Printer {
+	opts := d.opts
+	if override != nil {
+		opts = *override
+	}
+	return retryPrinter{
+		logger:  d.logger,
+		printer: p,
+		opts:    opts,
+		sleep:   time.Sleep,
+	}
+}
+
+func (p retryPrinter) Print(destination string) error {
+	const op string = "printer.retryPrinter.Print"
+	backoff := xtime.Duration(p.opts.Backoff)
+	var err error
+	for attempt := int64(0); ; attempt++ {
+		err = p.printer.Print(destination)
+		if err == nil || attempt >= p.opts.MaxRetries || !isRetryable(err) {
+			break
+		}
+		p.logger.DebugfOp(op, "attempt '%d' failed, retrying in '%s': %s", attempt+1, backoff, err)
+		p.sleep(backoff)
+		backoff *= 2
+	}
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+func isRetryable(err error) bool {
+	switch xerror.Code(err) {
+	case xerror.InvalidCode, xerror.CanceledCode:
+		return false
+	default:
+		return true
+	}
+}
+
+// Compile-time checks to ensure type implements desired interfaces.
+var (
+	_ = Printer(new(retryPrinter))
+)
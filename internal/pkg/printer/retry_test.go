@@ -0,0 +1,60 @@
+package printer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+type flakyPrinter struct {
+	attempts *int
+	failures int
+	err      error
+}
+
+func (p flakyPrinter) Print(destination string) error {
+	*p.attempts++
+	if *p.attempts <= p.failures {
+		return p.err
+	}
+	return nil
+}
+
+func TestRetryDecorator(t *testing.T) {
+	d := NewRetryDecorator(test.DebugLogger(), RetryOptions{MaxRetries: 2, Backoff: 1.0})
+	decorate := func(failures int, err error, override *RetryOptions) (Printer, *int, *[]time.Duration) {
+		attempts := 0
+		var sleeps []time.Duration
+		p := d.Decorate(flakyPrinter{attempts: &attempts, failures: failures, err: err}, override).(retryPrinter)
+		p.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+		return p, &attempts, &sleeps
+	}
+	// should succeed after retries with
+	// an exponential backoff.
+	p, attempts, sleeps := decorate(2, errors.New("foo"), nil)
+	assert.Nil(t, p.Print("foo.pdf"))
+	assert.Equal(t, 3, *attempts)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *sleeps)
+	// should fail once the retries are exhausted.
+	p, attempts, _ = decorate(3, errors.New("foo"), nil)
+	test.AssertError(t, p.Print("foo.pdf"))
+	assert.Equal(t, 3, *attempts)
+	// should not retry an invalid conversion.
+	p, attempts, _ = decorate(3, xerror.Invalid("foo", "foo", nil), nil)
+	err := p.Print("foo.pdf")
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	assert.Equal(t, 1, *attempts)
+	// should honor the per-conversion options
+	// over the decorator ones.
+	p, attempts, _ = decorate(3, errors.New("foo"), &RetryOptions{MaxRetries: 0})
+	test.AssertError(t, p.Print("foo.pdf"))
+	assert.Equal(t, 1, *attempts)
+	p, attempts, sleeps = decorate(3, errors.New("foo"), &RetryOptions{MaxRetries: 3, Backoff: 0.5})
+	assert.Nil(t, p.Print("foo.pdf"))
+	assert.Equal(t, 4, *attempts)
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second}, *sleeps)
+}
@@ -0,0 +1,107 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+/*
+sanitizeJS removes the JavaScript from the PDF
+file located at fpath, i.e. the document level
+scripts and the JavaScript actions, wherever
+they are (e.g. /OpenAction, /AA or the actions
+of the annotations).
+
+qpdf lists the objects, whose JavaScript is
+removed before qpdf updates them. It does not
+write the scripts left unreferenced.
+*/
+func sanitizeJS(ctx context.Context, logger xlog.Logger, fpath string) error {
+	const op string = "printer.sanitizeJS"
+	logger.DebugfOp(op, "sanitizing JavaScript of '%s'...", fpath)
+	resolver := func() error {
+		objects, err := readPDFObjects(ctx, logger, fpath)
+		if err != nil {
+			return err
+		}
+		updates := make(map[string]interface{})
+		for ref, raw := range objects.values {
+			var value interface{}
+			decoder := json.NewDecoder(bytes.NewReader(raw))
+			// keeps the numbers as written.
+			decoder.UseNumber()
+			if err := decoder.Decode(&value); err != nil {
+				return err
+			}
+			if sanitized, removed := removeJS(value); removed {
+				updates[ref] = sanitized
+			}
+		}
+		if len(updates) == 0 {
+			logger.DebugfOp(op, "no JavaScript found in '%s'", fpath)
+			return nil
+		}
+		return updatePDFObjects(ctx, logger, fpath, objects.header, updates)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+removeJS returns the given value (in the qpdf
+JSON format) without its JavaScript actions,
+which become null, and without the /JavaScript
+name tree of the document level scripts. It
+returns true if something has been removed.
+
+Other actions may carry JavaScript too (e.g.
+a /Rendition action with a /JS entry): they
+are removed as well.
+*/
+func removeJS(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v["/S"] == "/JavaScript" {
+			return nil, true
+		}
+		if _, ok := v["/JS"]; ok {
+			return nil, true
+		}
+		removed := false
+		if _, ok := v["/JavaScript"]; ok {
+			delete(v, "/JavaScript")
+			removed = true
+		}
+		for key, entry := range v {
+			sanitized, ok := removeJS(entry)
+			if !ok {
+				continue
+			}
+			removed = true
+			// a null entry is the same as none.
+			if sanitized == nil {
+				delete(v, key)
+				continue
+			}
+			v[key] = sanitized
+		}
+		return v, removed
+	case []interface{}:
+		removed := false
+		for i, entry := range v {
+			if sanitized, ok := removeJS(entry); ok {
+				v[i] = sanitized
+				removed = true
+			}
+		}
+		return v, removed
+	default:
+		return value, false
+	}
+}
@@ -0,0 +1,91 @@
+package printer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+// jsPDF is a PDF in the qpdf JSON format with
+// JavaScript in most places it may be found.
+const jsPDF string = `{"qpdf": [{"jsonversion": 2, "pushedinheritedpageresources": false, "calledgetallpages": false, "maxobjectid": 6}, {
+	"obj:1 0 R": {"value": {"/Type": "/Catalog", "/Pages": "2 0 R", "/OpenAction": "4 0 R", "/Names": {"/JavaScript": "5 0 R"}}},
+	"obj:2 0 R": {"value": {"/Type": "/Pages", "/Kids": ["3 0 R"], "/Count": 1}},
+	"obj:3 0 R": {"value": {"/Type": "/Page", "/Parent": "2 0 R", "/MediaBox": [0, 0, 612, 792.5],
+		"/AA": {"/O": {"/S": "/JavaScript", "/JS": "u:app.alert(2)"}},
+		"/Annots": [{"/Type": "/Annot", "/Subtype": "/Link", "/Rect": [0, 0, 10, 10], "/A": {"/S": "/JavaScript", "/JS": "u:app.alert(3)"}},
+			{"/Type": "/Annot", "/Subtype": "/Screen", "/Rect": [10, 10, 20, 20], "/A": {"/S": "/Rendition", "/OP": 0, "/JS": "u:app.alert(5)"}}]}},
+	"obj:4 0 R": {"value": {"/S": "/JavaScript", "/JS": "u:app.alert(1)"}},
+	"obj:5 0 R": {"value": {"/Names": ["u:foo", "6 0 R"]}},
+	"obj:6 0 R": {"value": {"/S": "/JavaScript", "/JS": "u:app.alert(4)"}},
+	"trailer": {"value": {"/Root": "1 0 R", "/Size": 7}}
+}]}`
+
+func TestSanitizeJS(t *testing.T) {
+	logger := test.DebugLogger()
+	f, err := ioutil.TempFile("", "*.json")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name()) // nolint: errcheck
+	_, err = f.Write([]byte(jsPDF))
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+	fpath := test.GenerateDestination()
+	err = exec.Command("qpdf", "--json-input", f.Name(), fpath).Run()
+	assert.Nil(t, err)
+	defer os.Remove(fpath) // nolint: errcheck
+	err = sanitizeJS(context.Background(), logger, fpath)
+	assert.Nil(t, err)
+	// no JavaScript should remain.
+	objects, err := readPDFObjects(context.Background(), logger, fpath)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, objects.pages)
+	for ref, value := range objects.values {
+		assert.NotContains(t, string(value), "/JS", ref)
+		assert.NotContains(t, string(value), "/JavaScript", ref)
+		assert.NotContains(t, string(value), "/OpenAction", ref)
+	}
+}
+
+func TestRemoveJS(t *testing.T) {
+	decode := func(s string) interface{} {
+		var value interface{}
+		d := json.NewDecoder(strings.NewReader(s))
+		d.UseNumber()
+		assert.Nil(t, d.Decode(&value))
+		return value
+	}
+	encode := func(value interface{}) string {
+		b, err := json.Marshal(value)
+		assert.Nil(t, err)
+		return string(b)
+	}
+	// a JavaScript action.
+	value, removed := removeJS(decode(`{"/S": "/JavaScript", "/JS": "u:app.alert(1)"}`))
+	assert.True(t, removed)
+	assert.Nil(t, value)
+	// direct JavaScript actions and the
+	// document level scripts.
+	value, removed = removeJS(decode(`{"/Type": "/Catalog", "/OpenAction": {"/S": "/JavaScript", "/JS": "4 0 R"}, "/Names": {"/Dests": "6 0 R", "/JavaScript": "5 0 R"}}`))
+	assert.True(t, removed)
+	assert.Equal(t, `{"/Names":{"/Dests":"6 0 R"},"/Type":"/Catalog"}`, encode(value))
+	value, removed = removeJS(decode(`{"/Next": [{"/S": "/URI", "/URI": "u:https://foo.com"}, {"/S": "/JavaScript", "/JS": "u:app.alert(1)"}]}`))
+	assert.True(t, removed)
+	assert.Equal(t, `{"/Next":[{"/S":"/URI","/URI":"u:https://foo.com"},null]}`, encode(value))
+	// another action carrying JavaScript.
+	value, removed = removeJS(decode(`{"/Type": "/Annot", "/Subtype": "/Screen", "/A": {"/S": "/Rendition", "/OP": 0, "/JS": "u:app.alert(1)"}}`))
+	assert.True(t, removed)
+	assert.Equal(t, `{"/Subtype":"/Screen","/Type":"/Annot"}`, encode(value))
+	// should keep the other actions, strings
+	// and numbers as is.
+	in := `{"/A":{"/S":"/URI","/URI":"u:https://foo.com/JavaScript"},"/MediaBox":[0,0,612,792.50],"/S":"u:/JavaScript"}`
+	value, removed = removeJS(decode(in))
+	assert.False(t, removed)
+	assert.Equal(t, in, encode(value))
+}
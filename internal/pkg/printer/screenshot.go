@@ -0,0 +1,456 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/mafredri/cdp"
+	"github.com/mafredri/cdp/protocol/emulation"
+	"github.com/mafredri/cdp/protocol/page"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xassert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+type screenshotPrinter struct {
+	chrome chromePrinter
+	opts   ScreenshotPrinterOptions
+}
+
+// ScreenshotPrinterOptions helps customizing the
+// screenshot Printer behaviour.
+type ScreenshotPrinterOptions struct {
+	Format            string
+	Quality           int64
+	ViewportWidths    []int64
+	DeviceScaleFactor float64
+	FullPage          bool
+	MaxTileHeight     int64
+	Clip              *ScreenshotClip
+}
+
+/*
+ScreenshotClip is the rectangle of the page to
+capture, in CSS pixels from the top-left corner
+of the page.
+*/
+type ScreenshotClip struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+const (
+	// PNGScreenshotFormat is the PNG
+	// screenshot format.
+	PNGScreenshotFormat string = "png"
+	// JPEGScreenshotFormat is the JPEG
+	// screenshot format.
+	JPEGScreenshotFormat string = "jpeg"
+)
+
+// ScreenshotFormats returns a slice containing
+// all available screenshot formats.
+func ScreenshotFormats() []string {
+	return []string{
+		PNGScreenshotFormat,
+		JPEGScreenshotFormat,
+	}
+}
+
+// DefaultScreenshotPrinterOptions returns the default
+// screenshot Printer options.
+func DefaultScreenshotPrinterOptions() ScreenshotPrinterOptions {
+	return ScreenshotPrinterOptions{
+		Format:            PNGScreenshotFormat,
+		Quality:           100,
+		ViewportWidths:    nil,
+		DeviceScaleFactor: 1.0,
+		FullPage:          false,
+		MaxTileHeight:     16384,
+		Clip:              nil,
+	}
+}
+
+/*
+NewScreenshotPrinter returns a Printer which
+is able to capture screenshots of a URL.
+
+The page is loaded only once using the given
+Google Chrome options. If viewport widths are
+given, the Printer captures one screenshot per
+width, writing them next to the destination with
+a numbered suffix (e.g. "result_1.png") instead of
+the destination itself.
+
+The device scale factor multiplies the size of
+the screenshots (e.g. 2.0 for retina displays).
+
+If a clip is given, the screenshots only contain
+this rectangle of the page.
+
+If full page is enabled, the screenshots contain
+the whole page instead of the viewport. As Google
+Chrome cannot capture very tall pages at once, the
+page is then captured in vertical tiles of at most
+MaxTileHeight pixels, which are stitched together.
+*/
+func NewScreenshotPrinter(logger xlog.Logger, url string, chromeOpts ChromePrinterOptions, opts ScreenshotPrinterOptions) Printer {
+	return screenshotPrinter{
+		chrome: chromePrinter{
+			logger:            requestLogger(logger, chromeOpts.RequestID),
+			url:               url,
+			opts:              chromeOpts,
+			deviceScaleFactor: opts.DeviceScaleFactor,
+		},
+		opts: opts,
+	}
+}
+
+func (p screenshotPrinter) Print(destination string) error {
+	const op string = "printer.screenshotPrinter.Print"
+	logOptions(p.chrome.logger, p.opts)
+	resolver := func() error {
+		if err := p.opts.validate(); err != nil {
+			return err
+		}
+		return p.chrome.render(destination, p.capture, nil)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(requestOp(op, p.chrome.opts.RequestID), err)
+	}
+	return nil
+}
+
+func (opts ScreenshotPrinterOptions) validate() error {
+	const op string = "printer.ScreenshotPrinterOptions.validate"
+	resolver := func() error {
+		if _, err := xassert.String(
+			"format",
+			opts.Format,
+			"",
+			xassert.StringOneOf(ScreenshotFormats()),
+		); err != nil {
+			return err
+		}
+		if opts.Quality < 0 || opts.Quality > 100 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("screenshot quality should be between '0' and '100', got '%d'", opts.Quality),
+				nil,
+			)
+		}
+		if opts.DeviceScaleFactor <= 0.0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("device scale factor should be > '0', got '%.2f'", opts.DeviceScaleFactor),
+				nil,
+			)
+		}
+		if opts.FullPage && opts.MaxTileHeight <= 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("maximum tile height should be > '0', got '%d'", opts.MaxTileHeight),
+				nil,
+			)
+		}
+		if opts.Clip != nil {
+			if opts.FullPage {
+				return xerror.Invalid(
+					op,
+					"clip and full page are mutually exclusive",
+					nil,
+				)
+			}
+			if opts.Clip.X < 0 || opts.Clip.Y < 0 || opts.Clip.Width <= 0 || opts.Clip.Height <= 0 {
+				return xerror.Invalid(
+					op,
+					fmt.Sprintf(
+						"clip should have a position >= '0' and a size > '0', got '%gx%g' at '%g,%g'",
+						opts.Clip.Width, opts.Clip.Height, opts.Clip.X, opts.Clip.Y,
+					),
+					nil,
+				)
+			}
+		}
+		for _, width := range opts.ViewportWidths {
+			if width <= 0 {
+				return xerror.Invalid(
+					op,
+					fmt.Sprintf("viewport width should be > '0', got '%d'", width),
+					nil,
+				)
+			}
+		}
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+func (p screenshotPrinter) capture(ctx context.Context, client *cdp.Client, destination string) error {
+	const op string = "printer.screenshotPrinter.capture"
+	resolver := func() error {
+		// the device scale factor has been
+		// emulated before loading the page.
+		if len(p.opts.ViewportWidths) == 0 {
+			return p.screenshot(ctx, client, destination)
+		}
+		for i, width := range p.opts.ViewportWidths {
+			p.chrome.logger.DebugfOp(op, "resizing viewport to a width of '%dpx'...", width)
+			if err := p.emulateViewport(ctx, client, width); err != nil {
+				return err
+			}
+			if err := p.screenshot(ctx, client, numberedDestination(destination, i+1)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+func (p screenshotPrinter) emulateViewport(ctx context.Context, client *cdp.Client, width int64) error {
+	const op string = "printer.screenshotPrinter.emulateViewport"
+	resolver := func() error {
+		if err := client.Emulation.SetDeviceMetricsOverride(
+			ctx,
+			emulation.NewSetDeviceMetricsOverrideArgs(int(width), int(p.chrome.opts.ViewportHeight), p.opts.DeviceScaleFactor, false),
+		); err != nil {
+			return err
+		}
+		if err := waitForLayout(ctx, client); err != nil {
+			return err
+		}
+		// the new viewport may select
+		// other responsive images.
+		return waitForImages(ctx, client)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+func (p screenshotPrinter) screenshot(ctx context.Context, client *cdp.Client, destination string) error {
+	const op string = "printer.screenshotPrinter.screenshot"
+	if p.opts.FullPage {
+		if err := p.fullPageScreenshot(ctx, client, destination); err != nil {
+			return xerror.New(op, err)
+		}
+		return nil
+	}
+	args := screenshotArgs(p.opts.Format, p.opts.Quality)
+	if p.opts.Clip != nil {
+		// the device scale factor applies on top
+		// of the scale of the clip.
+		args.SetClip(page.Viewport{
+			X:      p.opts.Clip.X,
+			Y:      p.opts.Clip.Y,
+			Width:  p.opts.Clip.Width,
+			Height: p.opts.Clip.Height,
+			Scale:  1,
+		})
+	}
+	if err := p.chrome.capture(ctx, client, destination, args); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+fullPageScreenshot captures the whole page tile
+by tile: for each tile, the viewport is resized to
+the height of the tile and scrolled to its top.
+*/
+func (p screenshotPrinter) fullPageScreenshot(ctx context.Context, client *cdp.Client, destination string) error {
+	const op string = "printer.screenshotPrinter.fullPageScreenshot"
+	resolver := func() error {
+		metrics, err := client.Page.GetLayoutMetrics(ctx)
+		if err != nil {
+			return err
+		}
+		width := metrics.LayoutViewport.ClientWidth
+		height := int(math.Ceil(metrics.ContentSize.Height))
+		// the maximum tile height is in pixels of the
+		// resulting image, not in CSS pixels.
+		maxTileHeight := int(float64(p.opts.MaxTileHeight) / p.opts.DeviceScaleFactor)
+		tiles := screenshotTiles(height, maxTileHeight)
+		p.chrome.logger.DebugfOp(op, "capturing a page of '%dpx' in '%d' tile(s)...", height, len(tiles))
+		images := make([][]byte, len(tiles))
+		for i, tile := range tiles {
+			if err := client.Emulation.SetDeviceMetricsOverride(
+				ctx,
+				emulation.NewSetDeviceMetricsOverrideArgs(width, tile.height, p.opts.DeviceScaleFactor, false),
+			); err != nil {
+				return err
+			}
+			if _, err := evaluate(ctx, client, fmt.Sprintf("window.scrollTo(0, %d)", tile.y)); err != nil {
+				return err
+			}
+			if err := waitForLayout(ctx, client); err != nil {
+				return err
+			}
+			screenshot, err := client.Page.CaptureScreenshot(ctx, screenshotArgs(p.opts.Format, p.opts.Quality))
+			if err != nil {
+				return err
+			}
+			images[i] = screenshot.Data
+		}
+		if len(images) == 1 {
+			return p.chrome.write(ctx, destination, images[0])
+		}
+		data, err := stitch(images, p.opts.Format, p.opts.Quality)
+		if err != nil {
+			return err
+		}
+		return p.chrome.write(ctx, destination, data)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+// screenshotTile is a vertical slice of a page.
+type screenshotTile struct {
+	y      int
+	height int
+}
+
+/*
+screenshotTiles splits a page of the given height
+into tiles of at most maxHeight. A page without
+height still has a single tile of 1 pixel.
+*/
+func screenshotTiles(height, maxHeight int) []screenshotTile {
+	if height <= 0 {
+		height = 1
+	}
+	if maxHeight <= 0 {
+		maxHeight = 1
+	}
+	var tiles []screenshotTile
+	for y := 0; y < height; y += maxHeight {
+		tileHeight := maxHeight
+		if y+tileHeight > height {
+			tileHeight = height - y
+		}
+		tiles = append(tiles, screenshotTile{y: y, height: tileHeight})
+	}
+	return tiles
+}
+
+/*
+stitch decodes the given images and stacks them
+vertically into a single image encoded in the
+given format.
+*/
+func stitch(images [][]byte, format string, quality int64) ([]byte, error) {
+	const op string = "printer.stitch"
+	resolver := func() ([]byte, error) {
+		decoded := make([]image.Image, len(images))
+		width, height := 0, 0
+		for i, data := range images {
+			img, _, err := image.Decode(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			decoded[i] = img
+			if img.Bounds().Dx() > width {
+				width = img.Bounds().Dx()
+			}
+			height += img.Bounds().Dy()
+		}
+		canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+		y := 0
+		for _, img := range decoded {
+			bounds := img.Bounds()
+			draw.Draw(canvas, image.Rect(0, y, bounds.Dx(), y+bounds.Dy()), img, bounds.Min, draw.Src)
+			y += bounds.Dy()
+		}
+		var buffer bytes.Buffer
+		if format == JPEGScreenshotFormat {
+			if err := jpeg.Encode(&buffer, canvas, &jpeg.Options{Quality: int(quality)}); err != nil {
+				return nil, err
+			}
+			return buffer.Bytes(), nil
+		}
+		if err := png.Encode(&buffer, canvas); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	}
+	result, err := resolver()
+	if err != nil {
+		return nil, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+// screenshotArgs returns the arguments for
+// capturing a screenshot in the given format.
+func screenshotArgs(format string, quality int64) *page.CaptureScreenshotArgs {
+	args := page.NewCaptureScreenshotArgs().SetFormat(format)
+	if format == JPEGScreenshotFormat {
+		args.SetQuality(int(quality))
+	}
+	return args
+}
+
+/*
+waitForLayout waits for the page to paint
+twice, so that the layout reflects a viewport
+change before capturing it.
+*/
+func waitForLayout(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.waitForLayout"
+	const expression string = "new Promise(resolve => requestAnimationFrame(() => requestAnimationFrame(() => resolve(true))))"
+	if _, err := evaluate(ctx, client, expression); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+waitForImages waits until the images of the
+page which are loading have either loaded or
+failed to load.
+*/
+func waitForImages(ctx context.Context, client *cdp.Client) error {
+	const op string = "printer.waitForImages"
+	const expression string = "Promise.all(Array.from(document.images).filter(img => !img.complete).map(img => new Promise(resolve => { img.addEventListener('load', resolve); img.addEventListener('error', resolve); }))).then(() => true)"
+	if _, err := evaluate(ctx, client, expression); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+numberedDestination adds a numbered suffix
+to the given destination, before its file
+extension.
+*/
+func numberedDestination(destination string, number int) string {
+	ext := filepath.Ext(destination)
+	return fmt.Sprintf("%s_%d%s", strings.TrimSuffix(destination, ext), number, ext)
+}
+
+// Compile-time checks to ensure type implements desired interfaces.
+var (
+	_ = Printer(new(screenshotPrinter))
+)
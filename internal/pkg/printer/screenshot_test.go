@@ -0,0 +1,254 @@
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestScreenshotPrinter(t *testing.T) {
+	var (
+		logger     xlog.Logger = test.DebugLogger()
+		config     conf.Config = conf.DefaultConfig()
+		URL                    = "https://google.com"
+		chromeOpts ChromePrinterOptions
+		opts       ScreenshotPrinterOptions
+		dest       string
+		p          Printer
+		err        error
+	)
+	// default options.
+	chromeOpts = DefaultChromePrinterOptions(config)
+	opts = DefaultScreenshotPrinterOptions()
+	p = NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	assert.FileExists(t, dest)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with JPEG format.
+	chromeOpts = DefaultChromePrinterOptions(config)
+	opts = DefaultScreenshotPrinterOptions()
+	opts.Format = JPEGScreenshotFormat
+	opts.Quality = 50
+	p = NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	assert.FileExists(t, dest)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with many viewport widths.
+	chromeOpts = DefaultChromePrinterOptions(config)
+	opts = DefaultScreenshotPrinterOptions()
+	opts.ViewportWidths = []int64{375, 768, 1280}
+	p = NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	for i := range opts.ViewportWidths {
+		numbered := numberedDestination(dest, i+1)
+		assert.FileExists(t, numbered)
+		err = os.RemoveAll(numbered)
+		assert.Nil(t, err)
+	}
+	// options with a device scale factor:
+	// the screenshot should be twice as large
+	// as the viewport.
+	chromeOpts = DefaultChromePrinterOptions(config)
+	opts = DefaultScreenshotPrinterOptions()
+	opts.ViewportWidths = []int64{800}
+	opts.DeviceScaleFactor = 2.0
+	p = NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	numbered := numberedDestination(dest, 1)
+	f, err := os.Open(numbered)
+	assert.Nil(t, err)
+	imgConfig, err := png.DecodeConfig(f)
+	assert.Nil(t, err)
+	assert.Equal(t, 1600, imgConfig.Width)
+	f.Close() // nolint: errcheck
+	err = os.RemoveAll(numbered)
+	assert.Nil(t, err)
+	// should not be OK as the device
+	// scale factor is invalid.
+	chromeOpts = DefaultChromePrinterOptions(config)
+	opts = DefaultScreenshotPrinterOptions()
+	opts.DeviceScaleFactor = 0.0
+	p = NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as format
+	// is invalid.
+	chromeOpts = DefaultChromePrinterOptions(config)
+	opts = DefaultScreenshotPrinterOptions()
+	opts.Format = "gif"
+	p = NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as a viewport
+	// width is invalid.
+	chromeOpts = DefaultChromePrinterOptions(config)
+	opts = DefaultScreenshotPrinterOptions()
+	opts.ViewportWidths = []int64{375, 0}
+	p = NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// options with a clip.
+	chromeOpts = DefaultChromePrinterOptions(config)
+	opts = DefaultScreenshotPrinterOptions()
+	opts.Clip = &ScreenshotClip{X: 10, Y: 20, Width: 300, Height: 200}
+	p = NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	assert.FileExists(t, dest)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the clip is empty.
+	chromeOpts = DefaultChromePrinterOptions(config)
+	opts = DefaultScreenshotPrinterOptions()
+	opts.Clip = &ScreenshotClip{X: 10, Y: 20, Width: 0, Height: 200}
+	p = NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as the clip comes
+	// with a full page.
+	chromeOpts = DefaultChromePrinterOptions(config)
+	opts = DefaultScreenshotPrinterOptions()
+	opts.FullPage = true
+	opts.Clip = &ScreenshotClip{X: 10, Y: 20, Width: 300, Height: 200}
+	p = NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// options with a full page captured
+	// in many tiles.
+	chromeOpts = DefaultChromePrinterOptions(config)
+	opts = DefaultScreenshotPrinterOptions()
+	opts.FullPage = true
+	opts.MaxTileHeight = 200
+	p = NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	assert.FileExists(t, dest)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the maximum
+	// tile height is invalid.
+	chromeOpts = DefaultChromePrinterOptions(config)
+	opts = DefaultScreenshotPrinterOptions()
+	opts.FullPage = true
+	opts.MaxTileHeight = 0
+	p = NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as context.Context
+	// should timeout.
+	chromeOpts = DefaultChromePrinterOptions(config)
+	chromeOpts.WaitTimeout = 0.0
+	opts = DefaultScreenshotPrinterOptions()
+	p = NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+}
+
+func TestScreenshotPrinterDeviceScaleFactor(t *testing.T) {
+	var (
+		logger     xlog.Logger = test.DebugLogger()
+		config     conf.Config = conf.DefaultConfig()
+		URL                    = fmt.Sprintf("file://%s", test.DPRFpaths(t)[0])
+		chromeOpts             = DefaultChromePrinterOptions(config)
+	)
+	// centerColor returns the color of the center
+	// of the screenshot of the page, which displays
+	// a red image or a blue image if the device
+	// pixel ratio is at least 2.
+	centerColor := func(deviceScaleFactor float64) color.Color {
+		opts := DefaultScreenshotPrinterOptions()
+		opts.DeviceScaleFactor = deviceScaleFactor
+		p := NewScreenshotPrinter(logger, URL, chromeOpts, opts)
+		dest := test.GenerateDestination()
+		defer os.RemoveAll(dest) // nolint: errcheck
+		assert.Nil(t, p.Print(dest))
+		f, err := os.Open(dest)
+		assert.Nil(t, err)
+		defer f.Close() // nolint: errcheck
+		img, err := png.Decode(f)
+		assert.Nil(t, err)
+		bounds := img.Bounds()
+		return img.At(bounds.Dx()/2, bounds.Dy()/2)
+	}
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	assert.Equal(t, red, color.RGBAModel.Convert(centerColor(1.0)))
+	// the page should use the 2x image.
+	assert.Equal(t, blue, color.RGBAModel.Convert(centerColor(2.0)))
+}
+
+func TestNumberedDestination(t *testing.T) {
+	assert.Equal(t, "/tmp/foo_1.png", numberedDestination("/tmp/foo.png", 1))
+	assert.Equal(t, "/tmp/foo_12", numberedDestination("/tmp/foo", 12))
+}
+
+func TestScreenshotTiles(t *testing.T) {
+	assert.Equal(t, []screenshotTile{{y: 0, height: 100}}, screenshotTiles(100, 200))
+	assert.Equal(t, []screenshotTile{
+		{y: 0, height: 200},
+		{y: 200, height: 200},
+		{y: 400, height: 50},
+	}, screenshotTiles(450, 200))
+	assert.Equal(t, []screenshotTile{{y: 0, height: 1}}, screenshotTiles(0, 200))
+}
+
+func TestStitch(t *testing.T) {
+	tile := func(width, height int, c color.Color) []byte {
+		img := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+		var buffer bytes.Buffer
+		assert.Nil(t, png.Encode(&buffer, img))
+		return buffer.Bytes()
+	}
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	data, err := stitch([][]byte{tile(10, 20, red), tile(10, 5, blue)}, PNGScreenshotFormat, 100)
+	assert.Nil(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, image.Rect(0, 0, 10, 25), img.Bounds())
+	assert.Equal(t, red, color.RGBAModel.Convert(img.At(5, 19)))
+	assert.Equal(t, blue, color.RGBAModel.Convert(img.At(5, 20)))
+	// should not be OK as a tile is not an image.
+	_, err = stitch([][]byte{[]byte("foo")}, PNGScreenshotFormat, 100)
+	test.AssertError(t, err)
+}
@@ -0,0 +1,74 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mafredri/cdp"
+	"github.com/mafredri/cdp/protocol/dom"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+// sourceHTMLFilename is the name of the
+// source HTML attached to the PDF.
+const sourceHTMLFilename string = "source.html"
+
+/*
+sourceHTMLFpath returns the path where the source
+HTML of the PDF file written to the given
+destination is captured, until it is attached.
+*/
+func sourceHTMLFpath(destination string) string {
+	ext := filepath.Ext(destination)
+	return filepath.Join(fmt.Sprintf("%s_source", strings.TrimSuffix(destination, ext)), sourceHTMLFilename)
+}
+
+/*
+captureSourceHTML writes the HTML of the page, as
+rendered, to the given path.
+*/
+func (p chromePrinter) captureSourceHTML(ctx context.Context, client *cdp.Client, fpath string) error {
+	const op string = "printer.chromePrinter.captureSourceHTML"
+	p.logger.DebugOp(op, "capturing the source HTML...")
+	resolver := func() error {
+		doc, err := client.DOM.GetDocument(ctx, dom.NewGetDocumentArgs())
+		if err != nil {
+			return err
+		}
+		html, err := client.DOM.GetOuterHTML(ctx, dom.NewGetOuterHTMLArgs().SetNodeID(doc.Root.NodeID))
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(fpath), 0755); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(fpath, []byte(html.OuterHTML), 0644)
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+attachSourceHTML attaches the source HTML captured
+for the PDF file located at fpath to this PDF
+thanks to PDFtk.
+*/
+func attachSourceHTML(ctx context.Context, logger xlog.Logger, fpath string) error {
+	const op string = "printer.attachSourceHTML"
+	logger.DebugOp(op, "attaching the source HTML...")
+	err := postProcess(fpath, func(tmpDest string) error {
+		return xexec.Run(ctx, logger, "pdftk", fpath, "attach_files", sourceHTMLFpath(fpath), "output", tmpDest)
+	})
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
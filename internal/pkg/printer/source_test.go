@@ -0,0 +1,44 @@
+package printer
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestSourceHTMLFpath(t *testing.T) {
+	assert.Equal(t, "/tmp/foo_source/source.html", sourceHTMLFpath("/tmp/foo.pdf"))
+}
+
+func TestEmbedSourceHTML(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+	)
+	opts := DefaultChromePrinterOptions(config)
+	opts.EmbedSourceHTML = true
+	p := NewHTMLPrinter(logger, test.HTMLFpaths(t)[0], opts)
+	dest := test.GenerateDestination()
+	err := p.Print(dest)
+	assert.Nil(t, err)
+	defer os.RemoveAll(dest) // nolint: errcheck
+	// the captured HTML should have been removed.
+	_, err = os.Stat(filepath.Dir(sourceHTMLFpath(dest)))
+	assert.True(t, os.IsNotExist(err))
+	// the attachment should be readable.
+	dirPath, err := ioutil.TempDir("", "gotenberg")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dirPath) // nolint: errcheck
+	err = exec.Command("pdftk", dest, "unpack_files", "output", dirPath).Run()
+	assert.Nil(t, err)
+	content, err := ioutil.ReadFile(filepath.Join(dirPath, sourceHTMLFilename))
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "<html")
+}
@@ -0,0 +1,212 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/mafredri/cdp"
+	cdpio "github.com/mafredri/cdp/protocol/io"
+	"github.com/mafredri/cdp/protocol/network"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+/*
+writeStream reads the given Google Chrome stream
+chunk by chunk and writes it to the destination,
+or streams it to the Uploader (if any).
+
+As with write, the data goes through a temporary
+file which is removed on error, e.g. once the
+MaxOutputBytes ceiling has been crossed.
+*/
+func (p chromePrinter) writeStream(ctx context.Context, client *cdp.Client, destination string, handle cdpio.StreamHandle) error {
+	const op string = "printer.chromePrinter.writeStream"
+	defer client.IO.Close(ctx, cdpio.NewCloseArgs(handle)) // nolint: errcheck
+	if p.opts.Uploader != nil {
+		var buffer bytes.Buffer
+		if err := p.readStream(ctx, client, handle, &buffer); err != nil {
+			return xerror.New(op, err)
+		}
+		return p.write(ctx, destination, buffer.Bytes())
+	}
+	tmpDest := fmt.Sprintf("%s/%s%s", filepath.Dir(destination), xrand.Get(), filepath.Ext(destination))
+	resolver := func() error {
+		f, err := os.Create(tmpDest)
+		if err != nil {
+			return err
+		}
+		if err := p.readStream(ctx, client, handle, newRateLimitedWriter(ctx, f, p.opts.WriteRateLimit)); err != nil {
+			f.Close() // nolint: errcheck
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return os.Rename(tmpDest, destination)
+	}
+	if err := resolver(); err != nil {
+		os.Remove(tmpDest) // nolint: errcheck
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+readStream copies the given Google Chrome stream
+to w. It stops as soon as more than MaxOutputBytes
+(if any) have been read, returning a xerror.Error
+with xerror.InvalidCode.
+
+If the stream is empty, returns a xerror.Error
+with xerror.InternalCode.
+*/
+func (p chromePrinter) readStream(ctx context.Context, client *cdp.Client, handle cdpio.StreamHandle, w io.Writer) error {
+	const op string = "printer.chromePrinter.readStream"
+	resolver := func() error {
+		var read int64
+		for {
+			reply, err := client.IO.Read(ctx, cdpio.NewReadArgs(handle))
+			if err != nil {
+				return err
+			}
+			data := []byte(reply.Data)
+			if reply.Base64Encoded != nil && *reply.Base64Encoded {
+				data, err = base64.StdEncoding.DecodeString(reply.Data)
+				if err != nil {
+					return err
+				}
+			}
+			read += int64(len(data))
+			if p.opts.MaxOutputBytes > 0 && read > p.opts.MaxOutputBytes {
+				return xerror.Invalid(
+					op,
+					fmt.Sprintf("the PDF exceeds the maximum output size of '%d' bytes", p.opts.MaxOutputBytes),
+					nil,
+				)
+			}
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+			if !reply.EOF {
+				continue
+			}
+			if read == 0 {
+				return xerror.Internal(op, "Google Chrome returned an empty PDF", nil)
+			}
+			return nil
+		}
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+/*
+loadedBytes sums the bytes loaded by a page, as
+an estimate of the size of the resulting PDF: its
+images and fonts are embedded in the PDF.
+*/
+type loadedBytes struct {
+	mu      sync.Mutex
+	total   float64
+	closers []func() error
+}
+
+/*
+startLoadedBytes starts summing the bytes loaded
+by the page. The sum stops once closed.
+*/
+func startLoadedBytes(client *cdp.Client) (*loadedBytes, error) {
+	const op string = "printer.startLoadedBytes"
+	lb := &loadedBytes{}
+	resolver := func() error {
+		// see startDiagnostics.
+		loadingFinished, err := client.Network.LoadingFinished(context.Background())
+		if err != nil {
+			return err
+		}
+		lb.closers = append(lb.closers, loadingFinished.Close)
+		// the goroutine stops once the stream is closed.
+		go func() {
+			for {
+				ev, err := loadingFinished.Recv()
+				if err != nil {
+					return
+				}
+				lb.add(ev)
+			}
+		}()
+		return nil
+	}
+	if err := resolver(); err != nil {
+		lb.close()
+		return nil, xerror.New(op, err)
+	}
+	return lb, nil
+}
+
+func (lb *loadedBytes) add(ev *network.LoadingFinishedReply) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	lb.total += ev.EncodedDataLength
+}
+
+func (lb *loadedBytes) get() int64 {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	return int64(lb.total)
+}
+
+func (lb *loadedBytes) close() {
+	for _, closer := range lb.closers {
+		closer() // nolint: errcheck
+	}
+}
+
+type loadedBytesKey struct{}
+
+// withLoadedBytes returns a copy of the given
+// context.Context carrying the loaded bytes.
+func withLoadedBytes(ctx context.Context, lb *loadedBytes) context.Context {
+	return context.WithValue(ctx, loadedBytesKey{}, lb)
+}
+
+/*
+streamTransferMode returns true if the PDF should
+be returned as a stream: either the MaxOutputBytes
+ceiling requires to read it chunk by chunk, or the
+page has loaded more than StreamThresholdBytes, so
+that the PDF is likely too large to stay in memory.
+*/
+func (p chromePrinter) streamTransferMode(ctx context.Context) bool {
+	const op string = "printer.chromePrinter.streamTransferMode"
+	if p.opts.MaxOutputBytes > 0 {
+		return true
+	}
+	lb, ok := ctx.Value(loadedBytesKey{}).(*loadedBytes)
+	if p.opts.StreamThresholdBytes <= 0 || !ok {
+		return false
+	}
+	loaded := lb.get()
+	if loaded <= p.opts.StreamThresholdBytes {
+		return false
+	}
+	p.logger.DebugfOp(
+		op,
+		"page has loaded '%d' bytes, above the stream threshold of '%d' bytes: switching to stream transfer mode",
+		loaded,
+		p.opts.StreamThresholdBytes,
+	)
+	return true
+}
@@ -0,0 +1,34 @@
+package printer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mafredri/cdp/protocol/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestChromePrinterStreamTransferMode(t *testing.T) {
+	logger := test.DebugLogger()
+	lb := &loadedBytes{}
+	lb.add(&network.LoadingFinishedReply{EncodedDataLength: 600})
+	lb.add(&network.LoadingFinishedReply{EncodedDataLength: 500})
+	assert.Equal(t, int64(1100), lb.get())
+	ctx := withLoadedBytes(context.Background(), lb)
+	// should stream as there is a
+	// maximum output size.
+	p := chromePrinter{logger: logger, opts: ChromePrinterOptions{MaxOutputBytes: 1}}
+	assert.True(t, p.streamTransferMode(context.Background()))
+	// should stream as the page has loaded
+	// more bytes than the threshold.
+	p = chromePrinter{logger: logger, opts: ChromePrinterOptions{StreamThresholdBytes: 1000}}
+	assert.True(t, p.streamTransferMode(ctx))
+	// should not stream as the page has loaded
+	// less bytes than the threshold.
+	p = chromePrinter{logger: logger, opts: ChromePrinterOptions{StreamThresholdBytes: 2000}}
+	assert.False(t, p.streamTransferMode(ctx))
+	// should not stream as there is no threshold.
+	p = chromePrinter{logger: logger, opts: ChromePrinterOptions{}}
+	assert.False(t, p.streamTransferMode(ctx))
+}
@@ -0,0 +1,215 @@
+package printer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+// SVGPrinterOptions helps customizing the
+// SVG Printer behaviour.
+type SVGPrinterOptions struct {
+	Scale   float64
+	Padding float64
+}
+
+// DefaultSVGPrinterOptions returns the default
+// SVG Printer options.
+func DefaultSVGPrinterOptions() SVGPrinterOptions {
+	return SVGPrinterOptions{
+		Scale:   1.0,
+		Padding: 0.0,
+	}
+}
+
+/*
+cssPixelsPerInch is the number of CSS
+pixels Google Chrome prints per inch.
+*/
+const cssPixelsPerInch float64 = 96.0
+
+/*
+NewSVGPrinter returns a Printer which is able
+to convert an SVG file to PDF.
+
+The page is sized to the SVG (according to its
+width and height, or its viewBox) multiplied by
+the scale, plus the padding (in inches) on
+each side. The paper size, margins and
+orientation of the given Chrome options are
+overridden accordingly.
+*/
+func NewSVGPrinter(logger xlog.Logger, fpath string, opts ChromePrinterOptions, svgOpts SVGPrinterOptions) (Printer, error) {
+	const op string = "printer.NewSVGPrinter"
+	logger = requestLogger(logger, opts.RequestID)
+	resolver := func() (string, error) {
+		if err := svgOpts.validate(); err != nil {
+			return "", err
+		}
+		f, err := os.Open(fpath)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close() // nolint: errcheck
+		width, height, err := svgSize(f)
+		if err != nil {
+			return "", err
+		}
+		width, height = width*svgOpts.Scale, height*svgOpts.Scale
+		logger.DebugfOp(op, "SVG size is '%.2fpx' x '%.2fpx'", width, height)
+		opts.PaperWidth = width/cssPixelsPerInch + 2*svgOpts.Padding
+		opts.PaperHeight = height/cssPixelsPerInch + 2*svgOpts.Padding
+		opts.MarginTop, opts.MarginBottom, opts.MarginLeft, opts.MarginRight = 0, 0, 0, 0
+		opts.Landscape, opts.AutoLandscape = false, false
+		dirPath := filepath.Dir(fpath)
+		dst := fmt.Sprintf("%s/%s.html", dirPath, xrand.Get())
+		logger.DebugOp(op, "wrapping the SVG into an HTML file...")
+		if err := ioutil.WriteFile(dst, []byte(svgHTML(filepath.Base(fpath), width, height, svgOpts.Padding)), 0644); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("file://%s", dst), nil
+	}
+	URL, err := resolver()
+	if err != nil {
+		return chromePrinter{}, xerror.New(op, err)
+	}
+	return chromePrinter{
+		logger: logger,
+		url:    URL,
+		opts:   opts,
+	}, nil
+}
+
+func (opts SVGPrinterOptions) validate() error {
+	const op string = "printer.SVGPrinterOptions.validate"
+	if opts.Scale <= 0 {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("scale should be > '0', got '%.2f'", opts.Scale),
+			nil,
+		)
+	}
+	if opts.Padding < 0 {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("padding should be >= '0', got '%.2f'", opts.Padding),
+			nil,
+		)
+	}
+	return nil
+}
+
+/*
+svgHTML returns a minimal HTML document which
+displays the given SVG file at the given size
+(in CSS pixels) with the given padding (in inches).
+*/
+func svgHTML(filename string, width, height, padding float64) string {
+	return fmt.Sprintf(
+		`<!doctype html><html><head><style>html, body { margin: 0; overflow: hidden; } body { padding: %.4fin; } img { display: block; width: %.4fpx; height: %.4fpx; }</style></head><body><img src="%s"></body></html>`,
+		padding,
+		width,
+		height,
+		html.EscapeString(url.PathEscape(filename)),
+	)
+}
+
+type svgRoot struct {
+	XMLName xml.Name `xml:"svg"`
+	Width   string   `xml:"width,attr"`
+	Height  string   `xml:"height,attr"`
+	ViewBox string   `xml:"viewBox,attr"`
+}
+
+/*
+svgSize returns the size (in CSS pixels) of
+the SVG read from r.
+
+It relies on the width and height attributes of
+the root element if they are absolute lengths,
+or on its viewBox otherwise.
+*/
+func svgSize(r io.Reader) (float64, float64, error) {
+	const op string = "printer.svgSize"
+	resolver := func() (float64, float64, error) {
+		var root svgRoot
+		if err := xml.NewDecoder(r).Decode(&root); err != nil {
+			return 0, 0, xerror.Invalid(op, "unable to parse the SVG", err)
+		}
+		width, widthOK := svgLength(root.Width)
+		height, heightOK := svgLength(root.Height)
+		if widthOK && heightOK {
+			return width, height, nil
+		}
+		fields := strings.FieldsFunc(root.ViewBox, func(r rune) bool {
+			return r == ',' || r == ' '
+		})
+		if len(fields) != 4 {
+			return 0, 0, xerror.Invalid(
+				op,
+				"the SVG should have either absolute width and height attributes or a viewBox",
+				nil,
+			)
+		}
+		width, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return 0, 0, xerror.Invalid(op, fmt.Sprintf("invalid viewBox '%s'", root.ViewBox), err)
+		}
+		height, err = strconv.ParseFloat(fields[3], 64)
+		if err != nil {
+			return 0, 0, xerror.Invalid(op, fmt.Sprintf("invalid viewBox '%s'", root.ViewBox), err)
+		}
+		if width <= 0 || height <= 0 {
+			return 0, 0, xerror.Invalid(op, fmt.Sprintf("invalid viewBox '%s'", root.ViewBox), nil)
+		}
+		return width, height, nil
+	}
+	width, height, err := resolver()
+	if err != nil {
+		return 0, 0, xerror.New(op, err)
+	}
+	return width, height, nil
+}
+
+/*
+svgLength converts an absolute SVG length
+(e.g. "100", "100px" or "2in") to CSS pixels.
+
+It returns false for missing, relative
+(e.g. "100%") or invalid lengths.
+*/
+func svgLength(length string) (float64, bool) {
+	units := map[string]float64{
+		"px": 1,
+		"in": cssPixelsPerInch,
+		"cm": cssPixelsPerInch / 2.54,
+		"mm": cssPixelsPerInch / 25.4,
+		"pt": cssPixelsPerInch / 72,
+		"pc": cssPixelsPerInch / 6,
+	}
+	length = strings.TrimSpace(length)
+	factor := 1.0
+	for unit, f := range units {
+		if strings.HasSuffix(length, unit) {
+			length = strings.TrimSuffix(length, unit)
+			factor = f
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(length, 64)
+	if err != nil || value <= 0 {
+		return 0, false
+	}
+	return value * factor, true
+}
@@ -0,0 +1,70 @@
+package printer
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestSVGPrinter(t *testing.T) {
+	var (
+		logger  xlog.Logger = test.DebugLogger()
+		config  conf.Config = conf.DefaultConfig()
+		fpath   string      = test.SVGFpaths(t)[0]
+		opts    ChromePrinterOptions
+		svgOpts SVGPrinterOptions
+		dest    string
+		p       Printer
+		err     error
+	)
+	// default options.
+	opts = DefaultChromePrinterOptions(config)
+	svgOpts = DefaultSVGPrinterOptions()
+	p, err = NewSVGPrinter(logger, fpath, opts, svgOpts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with a scale and a padding.
+	svgOpts.Scale = 2.0
+	svgOpts.Padding = 0.5
+	p, err = NewSVGPrinter(logger, fpath, opts, svgOpts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the scale is invalid.
+	svgOpts.Scale = 0.0
+	_, err = NewSVGPrinter(logger, fpath, opts, svgOpts)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
+
+func TestSVGSize(t *testing.T) {
+	// absolute width and height.
+	width, height, err := svgSize(strings.NewReader(`<svg width="2in" height="100" viewBox="0 0 10 10"></svg>`))
+	assert.Nil(t, err)
+	assert.Equal(t, 192.0, width)
+	assert.Equal(t, 100.0, height)
+	// relative width and height fallback to the viewBox.
+	width, height, err = svgSize(strings.NewReader(`<svg xmlns="http://www.w3.org/2000/svg" width="100%" height="100%" viewBox="0,0,400,300"></svg>`))
+	assert.Nil(t, err)
+	assert.Equal(t, 400.0, width)
+	assert.Equal(t, 300.0, height)
+	// should not be OK as there is no size.
+	_, _, err = svgSize(strings.NewReader(`<svg></svg>`))
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as it is not an SVG.
+	_, _, err = svgSize(strings.NewReader(`<html></html>`))
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
@@ -0,0 +1,153 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mafredri/cdp"
+	"github.com/mafredri/cdp/protocol/network"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+// resourceTiming is the time taken by a
+// subresource, from its request to the end of
+// its loading.
+type resourceTiming struct {
+	URL      string
+	Duration time.Duration
+}
+
+type pendingResource struct {
+	url       string
+	timestamp network.MonotonicTime
+}
+
+/*
+resourceTimings records the timings of the
+subresources of a page while it is loaded.
+*/
+type resourceTimings struct {
+	mu      sync.Mutex
+	pending map[network.RequestID]pendingResource
+	timings []resourceTiming
+	closers []func() error
+}
+
+/*
+startResourceTimings starts recording the timings
+of the subresources of the page. The recording
+stops once closed.
+*/
+func startResourceTimings(client *cdp.Client) (*resourceTimings, error) {
+	const op string = "printer.startResourceTimings"
+	rt := &resourceTimings{pending: make(map[network.RequestID]pendingResource)}
+	resolver := func() error {
+		// see startDiagnostics.
+		ctx := context.Background()
+		requestWillBeSent, err := client.Network.RequestWillBeSent(ctx)
+		if err != nil {
+			return err
+		}
+		rt.closers = append(rt.closers, requestWillBeSent.Close)
+		loadingFinished, err := client.Network.LoadingFinished(ctx)
+		if err != nil {
+			return err
+		}
+		rt.closers = append(rt.closers, loadingFinished.Close)
+		loadingFailed, err := client.Network.LoadingFailed(ctx)
+		if err != nil {
+			return err
+		}
+		rt.closers = append(rt.closers, loadingFailed.Close)
+		// each goroutine stops once its stream is closed.
+		go func() {
+			for {
+				ev, err := requestWillBeSent.Recv()
+				if err != nil {
+					return
+				}
+				rt.start(ev.RequestID, ev.Request.URL, ev.Timestamp)
+			}
+		}()
+		go func() {
+			for {
+				ev, err := loadingFinished.Recv()
+				if err != nil {
+					return
+				}
+				rt.finish(ev.RequestID, ev.Timestamp)
+			}
+		}()
+		go func() {
+			for {
+				ev, err := loadingFailed.Recv()
+				if err != nil {
+					return
+				}
+				rt.finish(ev.RequestID, ev.Timestamp)
+			}
+		}()
+		return nil
+	}
+	if err := resolver(); err != nil {
+		rt.close()
+		return nil, xerror.New(op, err)
+	}
+	return rt, nil
+}
+
+func (rt *resourceTimings) start(ID network.RequestID, URL string, timestamp network.MonotonicTime) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.pending[ID] = pendingResource{url: URL, timestamp: timestamp}
+}
+
+func (rt *resourceTimings) finish(ID network.RequestID, timestamp network.MonotonicTime) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	pending, ok := rt.pending[ID]
+	if !ok {
+		return
+	}
+	delete(rt.pending, ID)
+	rt.timings = append(rt.timings, resourceTiming{
+		URL:      pending.url,
+		Duration: time.Duration(float64(timestamp-pending.timestamp) * float64(time.Second)),
+	})
+}
+
+// slowest returns at most n timings,
+// the slowest first.
+func (rt *resourceTimings) slowest(n int) []resourceTiming {
+	rt.mu.Lock()
+	timings := make([]resourceTiming, len(rt.timings))
+	copy(timings, rt.timings)
+	rt.mu.Unlock()
+	sort.SliceStable(timings, func(i, j int) bool {
+		return timings[i].Duration > timings[j].Duration
+	})
+	if len(timings) > n {
+		timings = timings[:n]
+	}
+	return timings
+}
+
+func (rt *resourceTimings) close() {
+	for _, closer := range rt.closers {
+		closer() // nolint: errcheck
+	}
+}
+
+// formatResourceTimings returns a one-line
+// summary of the given timings.
+func formatResourceTimings(timings []resourceTiming) string {
+	parts := make([]string, len(timings))
+	for i, timing := range timings {
+		parts[i] = fmt.Sprintf("%s (%s)", timing.URL, timing.Duration.Round(time.Millisecond))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,32 @@
+package printer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mafredri/cdp/protocol/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceTimings(t *testing.T) {
+	rt := &resourceTimings{pending: make(map[network.RequestID]pendingResource)}
+	rt.start("1", "https://foo.com/a.js", 10)
+	rt.start("2", "https://foo.com/b.css", 10)
+	rt.start("3", "https://foo.com/c.png", 11)
+	rt.finish("1", 10.5)
+	rt.finish("2", 13)
+	rt.finish("3", 11.25)
+	// unknown requests are ignored.
+	rt.finish("4", 12)
+	slowest := rt.slowest(2)
+	assert.Equal(t, []resourceTiming{
+		{URL: "https://foo.com/b.css", Duration: 3 * time.Second},
+		{URL: "https://foo.com/a.js", Duration: 500 * time.Millisecond},
+	}, slowest)
+	assert.Len(t, rt.slowest(10), 3)
+	assert.Equal(
+		t,
+		"https://foo.com/b.css (3s), https://foo.com/a.js (500ms)",
+		formatResourceTimings(slowest),
+	)
+}
@@ -0,0 +1,64 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+)
+
+/*
+Uploader is a type that can store the result
+of a Printer somewhere else than on the local
+disk, e.g. in an object storage.
+
+The name is the destination given to the Printer.
+*/
+type Uploader interface {
+	Upload(ctx context.Context, name string, r io.Reader) error
+}
+
+type directoryUploader struct {
+	dirPath string
+}
+
+/*
+NewDirectoryUploader returns an Uploader which
+writes the uploaded objects into the given
+directory, using their base name.
+
+It is mostly useful as a reference implementation
+and for testing purposes.
+*/
+func NewDirectoryUploader(dirPath string) Uploader {
+	return directoryUploader{dirPath: dirPath}
+}
+
+func (u directoryUploader) Upload(ctx context.Context, name string, r io.Reader) error {
+	const op string = "printer.directoryUploader.Upload"
+	resolver := func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fpath := fmt.Sprintf("%s/%s", u.dirPath, filepath.Base(name))
+		f, err := os.Create(fpath)
+		if err != nil {
+			return err
+		}
+		defer f.Close() // nolint: errcheck
+		_, err = io.Copy(f, r)
+		return err
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+// Compile-time checks to ensure type implements desired interfaces.
+var (
+	_ = Uploader(new(directoryUploader))
+)
@@ -0,0 +1,33 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestDirectoryUploader(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "uploader")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dirPath) // nolint: errcheck
+	u := NewDirectoryUploader(dirPath)
+	// the object should be written
+	// using its base name.
+	err = u.Upload(context.Background(), "/foo/bar.pdf", strings.NewReader("foo"))
+	assert.Nil(t, err)
+	content, err := ioutil.ReadFile(fmt.Sprintf("%s/bar.pdf", dirPath))
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", string(content))
+	// should not be OK as context.Context
+	// is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = u.Upload(ctx, "baz.pdf", strings.NewReader("baz"))
+	test.AssertError(t, err)
+}
@@ -1,15 +1,94 @@
 package printer
 
 import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
 )
 
-// NewURLPrinter returns a Printer which
-// is able to convert a URL to PDF.
-func NewURLPrinter(logger xlog.Logger, url string, opts ChromePrinterOptions) Printer {
+/*
+NewURLPrinter returns a Printer which
+is able to convert a URL to PDF.
+
+The URL defaults to the http scheme if it has
+none, e.g. "google.com". If it is not a valid
+http, https, file or data URL, returns a
+xerror.Error with xerror.InvalidCode, so that
+it fails before Google Chrome navigates to it.
+*/
+func NewURLPrinter(logger xlog.Logger, url string, opts ChromePrinterOptions) (Printer, error) {
+	const op string = "printer.NewURLPrinter"
+	URL, err := normalizeURL(url)
+	if err != nil {
+		return chromePrinter{}, xerror.New(op, err)
+	}
 	return chromePrinter{
-		logger: logger,
-		url:    url,
+		logger: requestLogger(logger, opts.RequestID),
+		url:    URL,
 		opts:   opts,
+	}, nil
+}
+
+// nolint: gochecknoglobals
+var urlSchemeRegexp = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*):`)
+
+/*
+hasURLScheme returns true if the given URL
+starts with a scheme, i.e., either one followed
+by "//" or the "data" scheme. For instance,
+"localhost:3000" would otherwise be parsed with
+the "localhost" scheme.
+*/
+func hasURLScheme(rawURL string) bool {
+	matches := urlSchemeRegexp.FindStringSubmatch(rawURL)
+	if matches == nil {
+		return false
+	}
+	return strings.HasPrefix(rawURL[len(matches[0]):], "//") || strings.EqualFold(matches[1], "data")
+}
+
+/*
+normalizeURL returns the given URL with the http
+scheme if it has none, or a xerror.Error with
+xerror.InvalidCode if it is not a URL Google
+Chrome is able to print.
+*/
+func normalizeURL(rawURL string) (string, error) {
+	const op string = "printer.normalizeURL"
+	rawURL = strings.TrimSpace(rawURL)
+	invalid := func(reason string, err error) error {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("'%s' is not a valid URL: %s", rawURL, reason),
+			err,
+		)
+	}
+	if rawURL == "" {
+		return "", invalid("it is empty", nil)
+	}
+	if !hasURLScheme(rawURL) {
+		rawURL = fmt.Sprintf("http://%s", rawURL)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", invalid("unable to parse it", err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return "", invalid("it has no host", nil)
+		}
+	case "file":
+		if u.Path == "" {
+			return "", invalid("it has no path", nil)
+		}
+	case "data":
+	default:
+		return "", invalid(fmt.Sprintf("scheme should be one of '[http https file data]', got '%s'", u.Scheme), nil)
 	}
+	return rawURL, nil
 }
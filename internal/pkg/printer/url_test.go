@@ -1,8 +1,15 @@
 package printer
 
 import (
+	"image/png"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/conf"
@@ -23,7 +30,8 @@ func TestURLPrinter(t *testing.T) {
 	)
 	// default options.
 	opts = DefaultChromePrinterOptions(config)
-	p = NewURLPrinter(logger, URL, opts)
+	p, err = NewURLPrinter(logger, URL, opts)
+	assert.Nil(t, err)
 	dest = test.GenerateDestination()
 	err = p.Print(dest)
 	assert.Nil(t, err)
@@ -32,21 +40,541 @@ func TestURLPrinter(t *testing.T) {
 	// options with a wait delay.
 	opts = DefaultChromePrinterOptions(config)
 	opts.WaitDelay = 0.5
-	p = NewURLPrinter(logger, URL, opts)
+	p, err = NewURLPrinter(logger, URL, opts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with reader mode.
+	opts = DefaultChromePrinterOptions(config)
+	opts.ReaderMode = true
+	p, err = NewURLPrinter(logger, URL, opts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with a selector to wait for.
+	opts = DefaultChromePrinterOptions(config)
+	opts.WaitForSelector = "body"
+	p, err = NewURLPrinter(logger, URL, opts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the selector
+	// never appears.
+	opts = DefaultChromePrinterOptions(config)
+	opts.WaitTimeout = 2.0
+	opts.WaitForSelector = "#gotenberg-does-not-exist"
+	p, err = NewURLPrinter(logger, URL, opts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the URL
+	// returns a 404 status.
+	opts = DefaultChromePrinterOptions(config)
+	opts.FailOnHTTPError = true
+	p, err = NewURLPrinter(logger, "https://google.com/gotenberg-does-not-exist", opts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with a 404 status
+	// considered as acceptable.
+	opts = DefaultChromePrinterOptions(config)
+	opts.FailOnHTTPError = true
+	opts.AcceptableStatusCodes = []int64{404}
+	p, err = NewURLPrinter(logger, "https://google.com/gotenberg-does-not-exist", opts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with an Uploader.
+	opts = DefaultChromePrinterOptions(config)
+	opts.Uploader = NewDirectoryUploader(os.TempDir())
+	p, err = NewURLPrinter(logger, URL, opts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	assert.FileExists(t, dest)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// options with auto landscape.
+	opts = DefaultChromePrinterOptions(config)
+	opts.AutoLandscape = true
+	p, err = NewURLPrinter(logger, URL, opts)
+	assert.Nil(t, err)
 	dest = test.GenerateDestination()
 	err = p.Print(dest)
 	assert.Nil(t, err)
 	err = os.RemoveAll(dest)
 	assert.Nil(t, err)
+	// options with a thumbnail.
+	opts = DefaultChromePrinterOptions(config)
+	opts.Thumbnail = true
+	opts.ThumbnailWidth = 200
+	p, err = NewURLPrinter(logger, URL, opts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	thumbnail := ThumbnailDestination(dest, opts.ThumbnailFormat)
+	f, err := os.Open(thumbnail)
+	assert.Nil(t, err)
+	imgConfig, err := png.DecodeConfig(f)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, imgConfig.Width)
+	f.Close() // nolint: errcheck
+	err = os.RemoveAll(thumbnail)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the thumbnail
+	// width is invalid.
+	opts = DefaultChromePrinterOptions(config)
+	opts.Thumbnail = true
+	opts.ThumbnailWidth = 0
+	p, err = NewURLPrinter(logger, URL, opts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
 	// should not be OK as context.Context
 	// should timeout.
 	opts = DefaultChromePrinterOptions(config)
 	opts.WaitTimeout = 0.0
-	p = NewURLPrinter(logger, URL, opts)
+	p, err = NewURLPrinter(logger, URL, opts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+}
+
+func TestURLPrinterAcceptEncoding(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+	)
+	encodings := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			encodings <- r.Header.Get("Accept-Encoding")
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>Gutenberg</body></html>")) // nolint: errcheck
+	}))
+	defer srv.Close()
+	opts := DefaultChromePrinterOptions(config)
+	opts.AcceptEncoding = "identity"
+	p, err := NewURLPrinter(logger, srv.URL, opts)
+	assert.Nil(t, err)
+	dest := test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	// the document request carries the header.
+	select {
+	case encoding := <-encodings:
+		assert.Equal(t, "identity", encoding)
+	default:
+		t.Error("document request not received")
+	}
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+}
+
+func TestURLPrinterExtraHTTPHeaders(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+	)
+	tenants := make(chan string, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenants <- r.Header.Get("X-Tenant-ID")
+		if r.URL.Path == "/style.css" {
+			w.Header().Set("Content-Type", "text/css")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><link rel="stylesheet" href="/style.css"></head><body>Gutenberg</body></html>`)) // nolint: errcheck
+	}))
+	defer srv.Close()
+	opts := DefaultChromePrinterOptions(config)
+	opts.ExtraHTTPHeaders = map[string]string{"X-Tenant-ID": " foo, bar "}
+	p, err := NewURLPrinter(logger, srv.URL, opts)
+	assert.Nil(t, err)
+	dest := test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	// both the document and the subresource
+	// requests carry the header verbatim.
+	for i := 0; i < 2; i++ {
+		select {
+		case tenant := <-tenants:
+			assert.Equal(t, " foo, bar ", tenant)
+		default:
+			t.Error("request not received")
+		}
+	}
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+}
+
+func TestURLPrinterCookies(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+	)
+	sessions := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			cookie, err := r.Cookie("session")
+			if err == nil {
+				sessions <- cookie.Value
+			}
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>Gutenberg</body></html>")) // nolint: errcheck
+	}))
+	defer srv.Close()
+	opts := DefaultChromePrinterOptions(config)
+	opts.Cookies = []Cookie{{Name: "session", Value: "foo"}}
+	p, err := NewURLPrinter(logger, srv.URL, opts)
+	assert.Nil(t, err)
+	dest := test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	// the document request carries the cookie,
+	// which defaults to the domain of the URL.
+	select {
+	case session := <-sessions:
+		assert.Equal(t, "foo", session)
+	default:
+		t.Error("cookie not received")
+	}
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+}
+
+func TestURLPrinterBasicAuth(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "foo" || password != "bar" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="gotenberg"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>Gutenberg</body></html>")) // nolint: errcheck
+	}))
+	defer srv.Close()
+	opts := DefaultChromePrinterOptions(config)
+	opts.Username = "foo"
+	opts.Password = "bar"
+	p, err := NewURLPrinter(logger, srv.URL, opts)
+	assert.Nil(t, err)
+	dest := test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as the credentials
+	// are wrong.
+	opts = DefaultChromePrinterOptions(config)
+	opts.Username = "foo"
+	opts.Password = "baz"
+	opts.FailOnHTTPError = true
+	p, err = NewURLPrinter(logger, srv.URL, opts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+}
+
+func TestURLPrinterPrintMediaListeners(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><body><p id="variant">screen variant</p><script>
+			matchMedia('print').addListener(e => {
+				document.getElementById('variant').textContent = e.matches ? 'print variant' : 'screen variant';
+			});
+		</script></body></html>`)) // nolint: errcheck
+	}))
+	defer srv.Close()
+	opts := DefaultChromePrinterOptions(config)
+	p, err := NewURLPrinter(logger, srv.URL, opts)
+	assert.Nil(t, err)
+	dest := test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	// the listener has rendered the print variant.
+	text, err := exec.Command("pdftotext", dest, "-").Output()
+	assert.Nil(t, err)
+	assert.Contains(t, string(text), "print variant")
+	assert.NotContains(t, string(text), "screen variant")
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+}
+
+func TestURLPrinterBypassCSP(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "default-src 'none'")
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body><main>Gutenberg</main></body></html>")) // nolint: errcheck
+	}))
+	defer srv.Close()
+	// the print area style sheet is
+	// not blocked by the page policy.
+	opts := DefaultChromePrinterOptions(config)
+	opts.BypassCSP = true
+	opts.PrintAreaSelector = "main"
+	p, err := NewURLPrinter(logger, srv.URL, opts)
+	assert.Nil(t, err)
+	dest := test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+}
+
+func TestURLPrinterWaitForSubsequentNavigation(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+	)
+	var finalRequests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte("<html><body><script>window.onload = () => location.replace('/final')</script></body></html>")) // nolint: errcheck
+		case "/late":
+			w.Write([]byte("<html><body><script>window.onload = () => setTimeout(() => location.replace('/final'), 3000)</script></body></html>")) // nolint: errcheck
+		default:
+			atomic.AddInt32(&finalRequests, 1)
+			w.Write([]byte("<html><body>Gutenberg</body></html>")) // nolint: errcheck
+		}
+	}))
+	defer srv.Close()
+	// a client-side redirect.
+	opts := DefaultChromePrinterOptions(config)
+	opts.WaitForSubsequentNavigation = true
+	p, err := NewURLPrinter(logger, srv.URL, opts)
+	assert.Nil(t, err)
+	dest := test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// a client-side redirect later than the
+	// subsequent navigation timeout, which
+	// is not awaited.
+	opts = DefaultChromePrinterOptions(config)
+	opts.WaitForSubsequentNavigation = true
+	p, err = NewURLPrinter(logger, srv.URL+"/late", opts)
+	assert.Nil(t, err)
+	atomic.StoreInt32(&finalRequests, 0)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&finalRequests))
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// the same redirect, awaited thanks to a
+	// larger subsequent navigation timeout.
+	opts = DefaultChromePrinterOptions(config)
+	opts.WaitForSubsequentNavigation = true
+	opts.SubsequentNavigationTimeout = 5.0
+	p, err = NewURLPrinter(logger, srv.URL+"/late", opts)
+	assert.Nil(t, err)
+	atomic.StoreInt32(&finalRequests, 0)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&finalRequests))
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// no client-side redirect: the page is
+	// printed after the subsequent navigation
+	// timeout.
+	opts = DefaultChromePrinterOptions(config)
+	opts.WaitForSubsequentNavigation = true
+	p, err = NewURLPrinter(logger, srv.URL+"/final", opts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+	// should not be OK as context.Context
+	// times out before the grace period ends.
+	opts = DefaultChromePrinterOptions(config)
+	opts.WaitForSubsequentNavigation = true
+	opts.WaitTimeout = 1.0
+	p, err = NewURLPrinter(logger, srv.URL+"/final", opts)
+	assert.Nil(t, err)
 	dest = test.GenerateDestination()
 	err = p.Print(dest)
 	test.AssertError(t, err)
 	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+}
+
+func TestURLPrinterWaitForExpression(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body><script>setTimeout(() => { window.app = { ready: true } }, 500)</script></body></html>")) // nolint: errcheck
+	}))
+	defer srv.Close()
+	// an expression which throws an exception
+	// until it becomes true.
+	opts := DefaultChromePrinterOptions(config)
+	opts.WaitForExpression = "window.app.ready"
+	p, err := NewURLPrinter(logger, srv.URL, opts)
+	assert.Nil(t, err)
+	dest := test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
 	err = os.RemoveAll(dest)
 	assert.Nil(t, err)
+	// should not be OK as the expression
+	// keeps throwing an exception.
+	opts = DefaultChromePrinterOptions(config)
+	opts.WaitTimeout = 2.0
+	opts.WaitForExpression = "window.app.missing.ready"
+	p, err = NewURLPrinter(logger, srv.URL, opts)
+	assert.Nil(t, err)
+	dest = test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+}
+
+func TestURLPrinterWaitForNetworkIdle(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/data" {
+			time.Sleep(time.Second)
+			w.Write([]byte("Gutenberg")) // nolint: errcheck
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body><script>setTimeout(() => fetch('/data').then(r => r.text()).then(t => document.body.textContent = t), 200)</script></body></html>")) // nolint: errcheck
+	}))
+	defer srv.Close()
+	opts := DefaultChromePrinterOptions(config)
+	opts.WaitForNetworkIdle = true
+	p, err := NewURLPrinter(logger, srv.URL, opts)
+	assert.Nil(t, err)
+	dest := test.GenerateDestination()
+	err = p.Print(dest)
+	assert.Nil(t, err)
+	err = os.RemoveAll(dest)
+	assert.Nil(t, err)
+}
+
+func TestURLPrinterWaitForFrameCount(t *testing.T) {
+	var (
+		logger xlog.Logger = test.DebugLogger()
+		config conf.Config = conf.DefaultConfig()
+	)
+	// the frames load fast, so that their events
+	// are all buffered before being received.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/frame" {
+			w.Write([]byte("<html><body>Gutenberg</body></html>")) // nolint: errcheck
+			return
+		}
+		w.Write([]byte(strings.Repeat(`<iframe src="/frame"></iframe>`, 4))) // nolint: errcheck
+	}))
+	defer srv.Close()
+	for i := 0; i < 5; i++ {
+		opts := DefaultChromePrinterOptions(config)
+		opts.WaitForFrameCount = 4
+		opts.WaitTimeout = 5.0
+		p, err := NewURLPrinter(logger, srv.URL, opts)
+		assert.Nil(t, err)
+		dest := test.GenerateDestination()
+		err = p.Print(dest)
+		assert.Nil(t, err)
+		err = os.RemoveAll(dest)
+		assert.Nil(t, err)
+	}
+	// should not be OK as there are
+	// not enough frames.
+	opts := DefaultChromePrinterOptions(config)
+	opts.WaitForFrameCount = 5
+	opts.WaitTimeout = 2.0
+	p, err := NewURLPrinter(logger, srv.URL, opts)
+	assert.Nil(t, err)
+	dest := test.GenerateDestination()
+	err = p.Print(dest)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.TimeoutCode, xerror.Code(err))
+}
+
+func TestNormalizeURL(t *testing.T) {
+	for rawURL, expected := range map[string]string{
+		"https://google.com":               "https://google.com",
+		" google.com/foo?a=b ":             "http://google.com/foo?a=b",
+		"localhost:3000":                   "http://localhost:3000",
+		"file:///tmp/index.html":           "file:///tmp/index.html",
+		"data:text/html,foo":               "data:text/html,foo",
+		"google.com/?next=https://foo.com": "http://google.com/?next=https://foo.com",
+		"localhost:3000/?next=data:foo":    "http://localhost:3000/?next=data:foo",
+	} {
+		URL, err := normalizeURL(rawURL)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, URL)
+	}
+	// should not be OK as the URLs are invalid.
+	for _, rawURL := range []string{"", "ftp://google.com", "javascript://alert(1)", "http://", "file://", "http://[::1", "javascript:alert(1)"} {
+		_, err := normalizeURL(rawURL)
+		test.AssertError(t, err)
+		assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	}
+	// should not be OK as the URL is invalid.
+	_, err := NewURLPrinter(test.DebugLogger(), "ftp://google.com", DefaultChromePrinterOptions(conf.DefaultConfig()))
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
 }
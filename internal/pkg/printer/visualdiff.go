@@ -0,0 +1,229 @@
+package printer
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+
+	"github.com/orisano/pixelmatch"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xassert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xrand"
+)
+
+const (
+	// PixelDiffAlgorithm counts every different
+	// pixel, including the anti-aliased ones.
+	PixelDiffAlgorithm string = "pixel"
+	// PerceptualDiffAlgorithm ignores the pixels
+	// which only differ because of anti-aliasing.
+	PerceptualDiffAlgorithm string = "perceptual"
+)
+
+// VisualDiffAlgorithms returns a slice containing
+// all available visual diff algorithms.
+func VisualDiffAlgorithms() []string {
+	return []string{
+		PixelDiffAlgorithm,
+		PerceptualDiffAlgorithm,
+	}
+}
+
+/*
+VisualDiffOptions helps customizing how a
+rendering is compared against a baseline.
+
+Both algorithms rely on pixelmatch, which compares
+the pixels in the YIQ color space, i.e. as the
+human eye perceives the differences.
+
+Tolerance (between 0 and 1) is the color
+difference below which two pixels are considered
+identical, whatever the algorithm. Threshold
+(between 0 and 1) is the ratio of different pixels
+above which the comparison fails. If
+DiffImagePath is set, an image highlighting the
+different pixels in red is written there.
+*/
+type VisualDiffOptions struct {
+	Algorithm     string
+	Tolerance     float64
+	Threshold     float64
+	DiffImagePath string
+}
+
+// DefaultVisualDiffOptions returns the
+// default visual diff options.
+func DefaultVisualDiffOptions() VisualDiffOptions {
+	return VisualDiffOptions{
+		Algorithm:     PerceptualDiffAlgorithm,
+		Tolerance:     0.1,
+		Threshold:     0.0,
+		DiffImagePath: "",
+	}
+}
+
+// VisualDiff is the result of the
+// comparison against a baseline.
+type VisualDiff struct {
+	// Score is the ratio of different pixels.
+	Score  float64
+	Passed bool
+}
+
+/*
+CompareWithBaseline renders the given Printer into
+a temporary PNG image, e.g. thanks to a screenshot
+Printer, and compares it against the baseline PNG
+image.
+*/
+func CompareWithBaseline(p Printer, baselinePath string, opts VisualDiffOptions) (VisualDiff, error) {
+	const op string = "printer.CompareWithBaseline"
+	resolver := func() (VisualDiff, error) {
+		if err := opts.validate(); err != nil {
+			return VisualDiff{}, err
+		}
+		dest := fmt.Sprintf("%s/%s.png", os.TempDir(), xrand.Get())
+		defer os.Remove(dest) // nolint: errcheck
+		if err := p.Print(dest); err != nil {
+			return VisualDiff{}, err
+		}
+		return CompareImages(dest, baselinePath, opts)
+	}
+	result, err := resolver()
+	if err != nil {
+		return result, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+/*
+CompareImages compares the PNG image located at
+imagePath against the baseline PNG image. Images
+of different sizes are compared on their union,
+the pixels outside of one of them being different.
+*/
+func CompareImages(imagePath, baselinePath string, opts VisualDiffOptions) (VisualDiff, error) {
+	const op string = "printer.CompareImages"
+	resolver := func() (VisualDiff, error) {
+		if err := opts.validate(); err != nil {
+			return VisualDiff{}, err
+		}
+		img, err := readPNG(imagePath)
+		if err != nil {
+			return VisualDiff{}, err
+		}
+		baseline, err := readPNG(baselinePath)
+		if err != nil {
+			return VisualDiff{}, xerror.Invalid(
+				op,
+				fmt.Sprintf("unable to read baseline '%s'", baselinePath),
+				err,
+			)
+		}
+		score, diff, err := compareImages(img, baseline, opts)
+		if err != nil {
+			return VisualDiff{}, err
+		}
+		if opts.DiffImagePath != "" {
+			if err := writePNG(opts.DiffImagePath, diff); err != nil {
+				return VisualDiff{}, err
+			}
+		}
+		return VisualDiff{
+			Score:  score,
+			Passed: score <= opts.Threshold,
+		}, nil
+	}
+	result, err := resolver()
+	if err != nil {
+		return result, xerror.New(op, err)
+	}
+	return result, nil
+}
+
+func (opts VisualDiffOptions) validate() error {
+	const op string = "printer.VisualDiffOptions.validate"
+	if _, err := xassert.String(
+		"algorithm",
+		opts.Algorithm,
+		"",
+		xassert.StringOneOf(VisualDiffAlgorithms()),
+	); err != nil {
+		return xerror.New(op, err)
+	}
+	if opts.Tolerance < 0 || opts.Tolerance > 1 || opts.Threshold < 0 || opts.Threshold > 1 {
+		return xerror.Invalid(
+			op,
+			fmt.Sprintf("tolerance and threshold should be between '0' and '1', got '%.2f' and '%.2f'", opts.Tolerance, opts.Threshold),
+			nil,
+		)
+	}
+	return nil
+}
+
+/*
+compareImages returns the ratio of different pixels
+and an image of the rendering, faded, with the
+different pixels in red.
+*/
+func compareImages(img, baseline image.Image, opts VisualDiffOptions) (float64, image.Image, error) {
+	bounds := img.Bounds().Union(baseline.Bounds())
+	common := img.Bounds().Intersect(baseline.Bounds())
+	// pixelmatch compares images of the same size.
+	crop := func(src image.Image) *image.RGBA {
+		dst := image.NewRGBA(common)
+		draw.Draw(dst, common, src, common.Min, draw.Src)
+		return dst
+	}
+	matchOpts := []pixelmatch.MatchOption{pixelmatch.Threshold(opts.Tolerance)}
+	if opts.Algorithm == PixelDiffAlgorithm {
+		matchOpts = append(matchOpts, pixelmatch.IncludeAntiAlias)
+	}
+	var commonDiff image.Image
+	matchOpts = append(matchOpts, pixelmatch.WriteTo(&commonDiff))
+	croppedBaseline := crop(baseline)
+	different, err := pixelmatch.MatchPixel(crop(img), croppedBaseline, matchOpts...)
+	if err != nil {
+		return 0, nil, err
+	}
+	// pixelmatch does not write the diff image
+	// of identical images.
+	if commonDiff == nil {
+		commonDiff = croppedBaseline
+	}
+	// the pixels outside of one of the images are different.
+	diff := image.NewRGBA(bounds)
+	draw.Draw(diff, bounds, &image.Uniform{C: color.RGBA{R: 255, A: 255}}, image.Point{}, draw.Src)
+	draw.Draw(diff, common, commonDiff, common.Min, draw.Src)
+	different += bounds.Dx()*bounds.Dy() - common.Dx()*common.Dy()
+	total := bounds.Dx() * bounds.Dy()
+	if total == 0 {
+		return 0, diff, nil
+	}
+	return float64(different) / float64(total), diff, nil
+}
+
+func readPNG(fpath string) (image.Image, error) {
+	f, err := os.Open(fpath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // nolint: errcheck
+	return png.Decode(f)
+}
+
+func writePNG(fpath string, img image.Image) error {
+	f, err := os.Create(fpath)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	return f.Close()
+}
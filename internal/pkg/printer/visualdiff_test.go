@@ -0,0 +1,122 @@
+package printer
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestCompareImages(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "visualdiff")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dirPath) // nolint: errcheck
+	writeImage := func(name string, width int, fn func(x, y int) color.Color) string {
+		img := image.NewRGBA(image.Rect(0, 0, width, 10))
+		for y := 0; y < 10; y++ {
+			for x := 0; x < width; x++ {
+				img.Set(x, y, fn(x, y))
+			}
+		}
+		fpath := dirPath + "/" + name
+		assert.Nil(t, writePNG(fpath, img))
+		return fpath
+	}
+	white := func(x, y int) color.Color { return color.White }
+	baseline := writeImage("baseline.png", 10, white)
+	// identical images.
+	opts := DefaultVisualDiffOptions()
+	result, err := CompareImages(writeImage("same.png", 10, white), baseline, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, VisualDiff{Score: 0, Passed: true}, result)
+	// a tenth of the pixels are black.
+	changed := writeImage("changed.png", 10, func(x, y int) color.Color {
+		if y == 0 {
+			return color.Black
+		}
+		return color.White
+	})
+	opts.DiffImagePath = dirPath + "/diff.png"
+	result, err = CompareImages(changed, baseline, opts)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.1, result.Score, 0.0001)
+	assert.False(t, result.Passed)
+	f, err := os.Open(opts.DiffImagePath)
+	assert.Nil(t, err)
+	diff, err := png.Decode(f)
+	f.Close() // nolint: errcheck
+	assert.Nil(t, err)
+	r, g, _, _ := diff.At(0, 0).RGBA()
+	assert.Equal(t, uint32(0xffff), r)
+	assert.Equal(t, uint32(0), g)
+	// the threshold allows the difference.
+	opts = DefaultVisualDiffOptions()
+	opts.Threshold = 0.1
+	result, err = CompareImages(changed, baseline, opts)
+	assert.Nil(t, err)
+	assert.True(t, result.Passed)
+	// a slight difference is within the tolerance.
+	slight := writeImage("slight.png", 10, func(x, y int) color.Color { return color.Gray{Y: 250} })
+	for _, algorithm := range VisualDiffAlgorithms() {
+		opts = DefaultVisualDiffOptions()
+		opts.Algorithm = algorithm
+		result, err = CompareImages(slight, baseline, opts)
+		assert.Nil(t, err)
+		assert.True(t, result.Passed)
+	}
+	// an anti-aliased pixel only counts
+	// with the pixel algorithm.
+	half := func(x, y int) color.Color {
+		if x < 5 {
+			return color.Black
+		}
+		return color.White
+	}
+	halfBaseline := writeImage("half.png", 10, half)
+	antiAliased := writeImage("antialiased.png", 10, func(x, y int) color.Color {
+		if x == 5 && y == 5 {
+			return color.Gray{Y: 128}
+		}
+		return half(x, y)
+	})
+	opts = DefaultVisualDiffOptions()
+	opts.Algorithm = PerceptualDiffAlgorithm
+	result, err = CompareImages(antiAliased, halfBaseline, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, VisualDiff{Score: 0, Passed: true}, result)
+	opts.Algorithm = PixelDiffAlgorithm
+	result, err = CompareImages(antiAliased, halfBaseline, opts)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.01, result.Score, 0.0001)
+	assert.False(t, result.Passed)
+	// the pixels outside of one of the
+	// images are different.
+	result, err = CompareImages(writeImage("wider.png", 20, white), baseline, DefaultVisualDiffOptions())
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.5, result.Score, 0.0001)
+	// should not be OK as the
+	// baseline does not exist.
+	_, err = CompareImages(changed, dirPath+"/foo.png", DefaultVisualDiffOptions())
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as the
+	// algorithm is unknown.
+	opts = DefaultVisualDiffOptions()
+	opts.Algorithm = "foo"
+	_, err = CompareImages(changed, baseline, opts)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as the
+	// threshold is out of range.
+	opts = DefaultVisualDiffOptions()
+	opts.Threshold = 2
+	_, err = CompareImages(changed, baseline, opts)
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+}
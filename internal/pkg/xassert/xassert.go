@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/dustin/go-humanize"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
@@ -47,6 +48,37 @@ func StringFromEnv(envVar, defaultValue string, rules ...RuleString) (string, er
 	return result, nil
 }
 
+/*
+StringSlice splits a comma separated string
+and applies validation on each of its values.
+
+If string is empty or validation fails,
+returns the default value.
+
+The key is used to identify the value.
+*/
+func StringSlice(key, value string, defaultValue []string, rules ...RuleString) ([]string, error) {
+	const op string = "xassert.StringSlice"
+	if value == "" {
+		return defaultValue, nil
+	}
+	var result []string
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		for _, rule := range rules {
+			rule.with(key, v)
+			if err := rule.validate(); err != nil {
+				return defaultValue, xerror.New(op, err)
+			}
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
 /*
 Int64 tries to convert a string to an int64.
 
@@ -95,6 +127,35 @@ func Int64FromEnv(envVar string, defaultValue int64, rules ...RuleInt64) (int64,
 	return result, nil
 }
 
+/*
+Int64Slice splits a comma separated string and
+tries to convert each of its values to an int64.
+
+If string is empty, conversion or validation fails,
+returns the default value.
+
+The key is used to identify the value.
+*/
+func Int64Slice(key, value string, defaultValue []int64, rules ...RuleInt64) ([]int64, error) {
+	const op string = "xassert.Int64Slice"
+	if value == "" {
+		return defaultValue, nil
+	}
+	var result []int64
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		parsedValue, err := Int64(key, v, 0, rules...)
+		if err != nil {
+			return defaultValue, xerror.New(op, err)
+		}
+		result = append(result, parsedValue)
+	}
+	return result, nil
+}
+
 /*
 Float64 tries to convert a string to a float64.
 
@@ -65,6 +65,32 @@ func TestStringFromEnv(t *testing.T) {
 	os.Unsetenv(envVar)
 }
 
+func TestStringSlice(t *testing.T) {
+	var (
+		defaultValue []string
+		expected     []string
+	)
+	rule := StringOneOf([]string{"FOO", "BAR"})
+	// empty value, result should be equal
+	// to the default value.
+	v, err := StringSlice("foo", "", defaultValue)
+	expected = defaultValue
+	assert.Equal(t, expected, v)
+	assert.Nil(t, err)
+	// result should be equal to given values
+	// as they are one of "FOO" and "BAR".
+	expected = []string{"FOO", "BAR"}
+	v, err = StringSlice("foo", "FOO, BAR,", defaultValue, rule)
+	assert.Equal(t, expected, v)
+	assert.Nil(t, err)
+	// should not be OK as one of the given values
+	// is not one of "FOO" and "BAR".
+	v, err = StringSlice("foo", "FOO,BAZ", defaultValue, rule)
+	expected = defaultValue
+	assert.Equal(t, expected, v)
+	test.AssertError(t, err)
+}
+
 func TestInt64(t *testing.T) {
 	const (
 		defaultValue int64 = 10
@@ -97,6 +123,37 @@ func TestInt64(t *testing.T) {
 	test.AssertError(t, err)
 }
 
+func TestInt64Slice(t *testing.T) {
+	var (
+		defaultValue []int64
+		expected     []int64
+	)
+	rule := Int64NotInferiorTo(0)
+	// empty value, result should be equal
+	// to the default value.
+	v, err := Int64Slice("foo", "", defaultValue)
+	expected = defaultValue
+	assert.Equal(t, expected, v)
+	assert.Nil(t, err)
+	// result should be equal to given values.
+	expected = []int64{1, 2}
+	v, err = Int64Slice("foo", "1, 2,", defaultValue, rule)
+	assert.Equal(t, expected, v)
+	assert.Nil(t, err)
+	// should not be OK as one of the given
+	// values is not an integer.
+	v, err = Int64Slice("foo", "1,bar", defaultValue, rule)
+	expected = defaultValue
+	assert.Equal(t, expected, v)
+	test.AssertError(t, err)
+	// should not be OK as one of the given
+	// values is < 0.
+	v, err = Int64Slice("foo", "1,-1", defaultValue, rule)
+	expected = defaultValue
+	assert.Equal(t, expected, v)
+	test.AssertError(t, err)
+}
+
 func TestInt64FromEnv(t *testing.T) {
 	const (
 		envVar       string = "FOO"
@@ -30,6 +30,10 @@ error inside an xerror.Error with xerror.TimeoutCode.
 Otherwise wraps the previous error inside an
 xerror.Error.
 
+In both cases, errors.Is reports the error of the
+Context, even if the previous error does not wrap
+it, e.g. the error of a killed process.
+
 It panics if no previous error.
 */
 func MustHandleError(ctx context.Context, previousErr error) error {
@@ -43,6 +47,7 @@ func MustHandleError(ctx context.Context, previousErr error) error {
 		// as it should be wrapped by the caller.
 		return previousErr
 	}
+	previousErr = contextError{error: previousErr, ctxErr: err}
 	// context has timed out
 	if strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
 		return xerror.Timeout(op, "context has timed out", previousErr)
@@ -54,3 +59,23 @@ func MustHandleError(ctx context.Context, previousErr error) error {
 	*/
 	return xerror.New(op, previousErr)
 }
+
+/*
+contextError is a previous error which also
+matches the error of its Context.
+*/
+type contextError struct {
+	error
+	ctxErr error
+}
+
+// Unwrap returns the previous error.
+func (e contextError) Unwrap() error {
+	return e.error
+}
+
+// Is returns true if target is the error
+// of the Context.
+func (e contextError) Is(target error) bool {
+	return target == e.ctxErr
+}
@@ -1,6 +1,7 @@
 package xcontext
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -32,6 +33,12 @@ func TestMustHandleError(t *testing.T) {
 	err = MustHandleError(ctx, previousErr)
 	xerr := test.AssertError(t, err)
 	assert.Equal(t, xerror.TimeoutCode, xerror.Code(xerr))
+	// the previous error does not wrap
+	// context.DeadlineExceeded, but the
+	// error should still match it.
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, errors.Is(err, previousErr))
+	assert.Equal(t, previousErr.Error(), err.Error())
 	// context should have an error different
 	// than context.DeadlineExceeded.
 	ctx, cancel = WithTimeout(logger, 5)
@@ -39,4 +46,6 @@ func TestMustHandleError(t *testing.T) {
 	err = MustHandleError(ctx, previousErr)
 	xerr = test.AssertError(t, err)
 	assert.Equal(t, xerror.InternalCode, xerror.Code(xerr))
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, errors.Is(err, context.DeadlineExceeded))
 }
@@ -2,6 +2,7 @@ package xerror
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -18,6 +19,9 @@ const (
 	// TimeoutCode occurs when something
 	// timed out.
 	TimeoutCode ErrorCode = "timeout"
+	// CanceledCode occurs when something
+	// has been canceled on purpose.
+	CanceledCode ErrorCode = "canceled"
 )
 
 // Error defines our standard application
@@ -45,6 +49,11 @@ func (e Error) Error() string {
 	return buf.String()
 }
 
+// Unwrap returns the wrapped error, if any.
+func (e Error) Unwrap() error {
+	return e.err
+}
+
 /*
 New returns a xerror.Error.
 
@@ -58,6 +67,22 @@ func New(op string, previous error) error {
 	}
 }
 
+/*
+Internal returns a xerror.Error.
+
+Should be used when something went
+wrong on our side, or on the side of
+one of our tools.
+*/
+func Internal(op, message string, previous error) error {
+	return &Error{
+		code:    InternalCode,
+		message: message,
+		op:      op,
+		err:     previous,
+	}
+}
+
 /*
 Invalid returns a xerror.Error.
 
@@ -87,6 +112,21 @@ func Timeout(op, message string, previous error) error {
 	}
 }
 
+/*
+Canceled returns a xerror.Error.
+
+Should be used when something has
+been canceled on purpose.
+*/
+func Canceled(op, message string, previous error) error {
+	return &Error{
+		code:    CanceledCode,
+		message: message,
+		op:      op,
+		err:     previous,
+	}
+}
+
 // Code returns the code of the root error, if available.
 // Otherwise returns InternalCode.
 func Code(err error) ErrorCode {
@@ -94,10 +134,17 @@ func Code(err error) ErrorCode {
 		return ""
 	}
 	e, ok := err.(*Error)
-	if ok && e.code != "" {
+	if !ok {
+		// follow the errors wrapped by other types.
+		if unwrapped := errors.Unwrap(err); unwrapped != nil {
+			return Code(unwrapped)
+		}
+		return InternalCode
+	}
+	if e.code != "" {
 		return e.code
 	}
-	if ok && e.err != nil {
+	if e.err != nil {
 		return Code(e.err)
 	}
 	return InternalCode
@@ -112,10 +159,17 @@ func Message(err error) string {
 		return ""
 	}
 	e, ok := err.(*Error)
-	if ok && e.message != "" {
+	if !ok {
+		// follow the errors wrapped by other types.
+		if unwrapped := errors.Unwrap(err); unwrapped != nil {
+			return Message(unwrapped)
+		}
+		return defaultMessage
+	}
+	if e.message != "" {
 		return e.message
 	}
-	if ok && e.err != nil {
+	if e.err != nil {
 		return Message(e.err)
 	}
 	return defaultMessage
@@ -129,6 +183,10 @@ func Op(err error) string {
 	}
 	e, ok := err.(*Error)
 	if !ok {
+		// follow the errors wrapped by other types.
+		if unwrapped := errors.Unwrap(err); unwrapped != nil {
+			return Op(unwrapped)
+		}
 		return ""
 	}
 	var buf bytes.Buffer
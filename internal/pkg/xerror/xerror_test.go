@@ -48,6 +48,19 @@ func TestError(t *testing.T) {
 	assert.Equal(t, "<timeout> nested error", err.Error())
 }
 
+func TestUnwrap(t *testing.T) {
+	rootErr := errors.New("root error")
+	err := New("foo", Invalid("bar", "nested error", rootErr))
+	assert.True(t, errors.Is(err, rootErr))
+	assert.Nil(t, errors.Unwrap(scenario3()))
+	// should follow the errors wrapped
+	// by other types.
+	err = fmt.Errorf("wrapped: %w", Invalid("bar", "nested error", nil))
+	assert.Equal(t, InvalidCode, Code(err))
+	assert.Equal(t, "nested error", Message(err))
+	assert.Equal(t, "bar", Op(err))
+}
+
 func TestCode(t *testing.T) {
 	// should be an empty code if no error.
 	assert.Equal(t, "", fmt.Sprintf("%s", Code(nil)))
@@ -57,6 +70,12 @@ func TestCode(t *testing.T) {
 	// should be the code of Error 2.2.
 	err = scenario2()
 	assert.Equal(t, TimeoutCode, Code(err))
+	// should be the canceled code.
+	err = New("foo", Canceled("bar", "canceled error", nil))
+	assert.Equal(t, CanceledCode, Code(err))
+	// should be the internal code.
+	err = New("foo", Internal("bar", "internal error", nil))
+	assert.Equal(t, InternalCode, Code(err))
 	// should be the default code.
 	err = scenario3()
 	assert.Equal(t, InternalCode, Code(err))
@@ -0,0 +1,101 @@
+package xexec
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
+)
+
+// nolint: gochecknoglobals
+var versionRegexp = regexp.MustCompile(`\d+(\.\d+)*`)
+
+/*
+CheckVersion checks that the version printed by
+"<binary> --version" is at least the given
+minimum, e.g. "9.50". An empty minimum disables
+the check.
+
+Returns a xerror.Error with xerror.InvalidCode
+describing the issue if the version is too old
+or cannot be found.
+*/
+func CheckVersion(logger xlog.Logger, binary, minimum string) error {
+	const op string = "xexec.CheckVersion"
+	if minimum == "" {
+		return nil
+	}
+	resolver := func() error {
+		if parseVersion(minimum) == "" {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("'%s' is not a valid minimum version for '%s'", minimum, binary),
+				nil,
+			)
+		}
+		cmd := exec.Command(binary, "--version")
+		LogBeforeExecute(logger, cmd)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return err
+		}
+		version := parseVersion(string(out))
+		if version == "" {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("unable to find the version of '%s' in '%s'", binary, strings.TrimSpace(string(out))),
+				nil,
+			)
+		}
+		if compareVersions(version, minimum) < 0 {
+			return xerror.Invalid(
+				op,
+				fmt.Sprintf("'%s' version '%s' is older than the required minimum '%s'", binary, version, minimum),
+				nil,
+			)
+		}
+		logger.DebugfOp(op, "'%s' version '%s' satisfies the required minimum '%s'", binary, version, minimum)
+		return nil
+	}
+	if err := resolver(); err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+// parseVersion returns the first dotted version
+// number found in the given string, if any.
+func parseVersion(s string) string {
+	return versionRegexp.FindString(s)
+}
+
+/*
+compareVersions compares two dotted version
+numbers component by component, and returns
+-1, 0 or 1 if a is respectively older than,
+equal to or newer than b. Missing components
+count as 0, so that "9.5" equals "9.5.0".
+*/
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
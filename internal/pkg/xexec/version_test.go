@@ -0,0 +1,40 @@
+package xexec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
+	"github.com/thecodingmachine/gotenberg/test"
+)
+
+func TestCheckVersion(t *testing.T) {
+	logger := test.DebugLogger()
+	// no minimum.
+	assert.Nil(t, CheckVersion(logger, "gotenberg-does-not-exist", ""))
+	// should not be OK as the
+	// minimum is invalid.
+	err := CheckVersion(logger, "gotenberg-does-not-exist", "foo")
+	test.AssertError(t, err)
+	assert.Equal(t, xerror.InvalidCode, xerror.Code(err))
+	// should not be OK as the
+	// binary does not exist.
+	err = CheckVersion(logger, "gotenberg-does-not-exist", "1.0")
+	test.AssertError(t, err)
+}
+
+func TestParseVersion(t *testing.T) {
+	assert.Equal(t, "3.0.9", parseVersion("pdftk port to java 3.0.9 a Handy Tool for Manipulating PDF Documents"))
+	assert.Equal(t, "2.02", parseVersion("pdftk 2.02 a Handy Tool for Manipulating PDF Documents"))
+	assert.Equal(t, "10.1.0", parseVersion("qpdf version 10.1.0\nRun qpdf --copyright"))
+	assert.Equal(t, "9.53.3", parseVersion("9.53.3\n"))
+	assert.Equal(t, "", parseVersion("foo"))
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("9.50", "9.50"))
+	assert.Equal(t, 0, compareVersions("9.5", "9.5.0"))
+	assert.Equal(t, -1, compareVersions("9.27", "9.50"))
+	assert.Equal(t, 1, compareVersions("10.1.0", "9.50"))
+	assert.Equal(t, -1, compareVersions("2.02", "3"))
+}
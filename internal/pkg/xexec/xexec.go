@@ -4,8 +4,11 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	"os/exec"
 	"strings"
 	"syscall"
@@ -44,6 +47,36 @@ func CommandContext(ctx context.Context, logger xlog.Logger, binary string, args
 	return cmd, nil
 }
 
+/*
+maxStderrBytes is the maximum number of bytes
+of the standard error of a failed command kept
+by a CommandError.
+*/
+const maxStderrBytes int64 = 4096
+
+/*
+CommandError is returned by Run if a command
+fails. Stderr holds the end of what the command
+wrote to its standard error, e.g. the reason
+PDFtk could not read a file.
+*/
+type CommandError struct {
+	Stderr string
+	err    error
+}
+
+func (e CommandError) Error() string {
+	if e.Stderr == "" {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.err.Error(), e.Stderr)
+}
+
+// Unwrap returns the error of the command.
+func (e CommandError) Unwrap() error {
+	return e.err
+}
+
 /*
 Run runs a command.
 
@@ -51,18 +84,38 @@ If command finishes or fails to finish
 before context.Context deadline, kill the
 corresponding process in a way which does
 not leak orphan processes.
+
+If the command fails, returns a CommandError
+with its standard error.
 */
 func Run(ctx context.Context, logger xlog.Logger, binary string, args ...string) error {
 	const op string = "xexec.Run"
 	resolver := func() error {
-		cmd, err := Command(
-			logger,
-			binary,
-			args...,
-		)
+		if isDryRun(ctx) {
+			command := redact(append([]string{binary}, args...))
+			logger.DebugfOp(op, "dry run, not executing command: %s", command)
+			return &DryRunError{Command: command}
+		}
+		cmd := exec.Command(binary, args...)
+		/*
+			the standard error goes to a file rather than
+			a pipe, so that the processes the command may
+			leave behind cannot block its reading.
+		*/
+		stderr, err := ioutil.TempFile("", "stderr")
 		if err != nil {
 			return err
 		}
+		defer os.Remove(stderr.Name()) // nolint: errcheck
+		defer stderr.Close()           // nolint: errcheck
+		cmd.Stderr = stderr
+		if logger.Level() == xlog.DebugLevel {
+			stdout, err := cmd.StdoutPipe()
+			if err != nil {
+				return err
+			}
+			go logCommandOutput(logger, stdout, "stdout", cmd)
+		}
 		LogBeforeExecute(logger, cmd)
 		// see https://medium.com/@felixge/killing-a-child-process-and-all-of-its-children-in-go-54079af94773.
 		kill := func() {
@@ -84,11 +137,15 @@ func Run(ctx context.Context, logger xlog.Logger, binary string, args ...string)
 		}()
 		select {
 		case err := <-result:
-			logger.DebugfOp(op, "command '%s' finished", strings.Join(cmd.Args, " "))
+			logger.DebugfOp(op, "command '%s' finished", redact(cmd.Args))
 			kill()
-			return err
+			output := commandStderr(logger, stderr, cmd)
+			if err != nil {
+				return &CommandError{Stderr: output, err: err}
+			}
+			return nil
 		case <-ctx.Done():
-			logger.DebugfOp(op, "command '%s' failed to finish before context.Context deadline", strings.Join(cmd.Args, " "))
+			logger.DebugfOp(op, "command '%s' failed to finish before context.Context deadline", redact(cmd.Args))
 			kill()
 			return ctx.Err()
 		}
@@ -102,7 +159,116 @@ func Run(ctx context.Context, logger xlog.Logger, binary string, args ...string)
 // LogBeforeExecute logs a command before its execution.
 func LogBeforeExecute(logger xlog.Logger, cmd *exec.Cmd) {
 	const op string = "xexec.LogBeforeExecute"
-	logger.DebugfOp(op, "executing command: %s", strings.Join(cmd.Args, " "))
+	logger.DebugfOp(op, "executing command: %s", redact(cmd.Args))
+}
+
+type dryRunKey struct{}
+
+/*
+WithDryRun returns a copy of the given
+context.Context in which Run does not execute
+the commands, but returns a DryRunError
+holding them instead.
+*/
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+func isDryRun(ctx context.Context) bool {
+	dryRun, ok := ctx.Value(dryRunKey{}).(bool)
+	return ok && dryRun
+}
+
+/*
+DryRunError is returned by Run in dry-run mode.
+
+Command is the command line which would have
+been executed, with sensitive values redacted.
+*/
+type DryRunError struct {
+	Command string
+}
+
+func (e DryRunError) Error() string {
+	return fmt.Sprintf("dry run: %s", e.Command)
+}
+
+/*
+DryRunCommand returns the command line of the
+DryRunError wrapped in the given error, if any.
+*/
+func DryRunCommand(err error) (string, bool) {
+	var dryRunErr *DryRunError
+	if !errors.As(err, &dryRunErr) {
+		return "", false
+	}
+	return dryRunErr.Command, true
+}
+
+const redacted string = "***"
+
+/*
+redact returns the given command line as a
+string which may be copy-pasted into a shell,
+with passwords replaced by "***".
+
+Passwords are either the argument following
+a PDFtk password keyword (e.g. "user_pw") or
+the value of a password flag (e.g.
+"-sPDFPassword=foo" for Ghostscript or
+"--password=foo" for qpdf).
+*/
+func redact(args []string) string {
+	quoted := redactArgs(args)
+	for i, arg := range quoted {
+		// a redacted password is left unquoted.
+		if arg == redacted && args[i] != redacted {
+			continue
+		}
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+/*
+redactArgs returns a copy of the given
+arguments with passwords replaced by "***"
+(see redact).
+*/
+func redactArgs(args []string) []string {
+	keywords := map[string]bool{
+		"user_pw":  true,
+		"owner_pw": true,
+		"input_pw": true,
+	}
+	flags := []string{
+		"-sPDFPassword=",
+		"-sUserPassword=",
+		"-sOwnerPassword=",
+		"--password=",
+	}
+	redactedArgs := make([]string, len(args))
+	for i, arg := range args {
+		if i > 0 && keywords[args[i-1]] {
+			redactedArgs[i] = redacted
+			continue
+		}
+		for _, flag := range flags {
+			if strings.HasPrefix(arg, flag) {
+				arg = flag + redacted
+				break
+			}
+		}
+		redactedArgs[i] = arg
+	}
+	return redactedArgs
+}
+
+func shellQuote(arg string) string {
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=+,./:@%") == "" {
+		return arg
+	}
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(arg, "'", `'\''`))
 }
 
 func pipe(logger xlog.Logger, cmd *exec.Cmd) error {
@@ -131,10 +297,40 @@ func pipe(logger xlog.Logger, cmd *exec.Cmd) error {
 	return nil
 }
 
+/*
+commandStderr logs the standard error written by
+the given command into the given file (if
+xlog.DebugLevel) and returns its last
+maxStderrBytes bytes, trimmed.
+*/
+func commandStderr(logger xlog.Logger, f *os.File, cmd *exec.Cmd) string {
+	const op string = "xexec.commandStderr"
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		logger.DebugfOp(op, "unable to read the standard error: %s", err.Error())
+		return ""
+	}
+	if logger.Level() == xlog.DebugLevel {
+		logCommandOutput(logger, ioutil.NopCloser(f), "stderr", cmd)
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return ""
+		}
+	}
+	if info, err := f.Stat(); err == nil && info.Size() > maxStderrBytes {
+		if _, err := f.Seek(-maxStderrBytes, io.SeekEnd); err != nil {
+			return ""
+		}
+	}
+	output, err := ioutil.ReadAll(f)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 func logCommandOutput(logger xlog.Logger, reader io.ReadCloser, outputType string, cmd *exec.Cmd) {
 	var buf bytes.Buffer
 	buf.WriteString(outputType)
-	for _, arg := range cmd.Args {
+	for _, arg := range redactArgs(cmd.Args) {
 		buf.WriteString(fmt.Sprintf(".%s", arg))
 	}
 	op := buf.String()
@@ -2,10 +2,16 @@ package xexec
 
 import (
 	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xcontext"
+	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
 	"github.com/thecodingmachine/gotenberg/test"
 )
 
@@ -50,4 +56,68 @@ func TestRun(t *testing.T) {
 	defer cancel()
 	err = Run(ctx, logger, "echo", "Hello", "World")
 	assert.NotNil(t, err)
+	// should not run the command as
+	// context.Context is in dry-run mode.
+	err = Run(WithDryRun(context.Background()), logger, "foo", "Hello World")
+	assert.NotNil(t, err)
+	command, ok := DryRunCommand(err)
+	assert.True(t, ok)
+	assert.Equal(t, "foo 'Hello World'", command)
+	// should not be a dry-run error.
+	_, ok = DryRunCommand(Run(context.Background(), logger, "false"))
+	assert.False(t, ok)
+}
+
+func TestRunStderr(t *testing.T) {
+	for _, logger := range []xlog.Logger{test.DebugLogger(), test.InfoLogger()} {
+		// should return the standard error
+		// of the failed command.
+		err := Run(context.Background(), logger, "sh", "-c", "echo foo >&2; echo bar >&2; exit 1")
+		var commandErr *CommandError
+		assert.True(t, errors.As(err, &commandErr))
+		assert.Equal(t, "foo\nbar", commandErr.Stderr)
+		assert.Contains(t, err.Error(), "exit status 1: foo\nbar")
+		// should only keep the end of
+		// the standard error.
+		err = Run(context.Background(), logger, "sh", "-c", "head -c 10000 /dev/zero | tr '\\0' a >&2; echo foo >&2; exit 1")
+		assert.True(t, errors.As(err, &commandErr))
+		assert.Len(t, commandErr.Stderr, int(maxStderrBytes)-1)
+		assert.True(t, strings.HasSuffix(commandErr.Stderr, "aaafoo"))
+		// should not return the standard
+		// error of a successful command.
+		err = Run(context.Background(), logger, "sh", "-c", "echo foo >&2")
+		assert.Nil(t, err)
+	}
+}
+
+func TestRunRedact(t *testing.T) {
+	// the logger writes to the standard error.
+	f, err := ioutil.TempFile("", "logs")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name()) // nolint: errcheck
+	defer f.Close()           // nolint: errcheck
+	stderr := os.Stderr
+	os.Stderr = f
+	logger := test.DebugLogger()
+	os.Stderr = stderr
+	// the arguments following "-c" are ignored by the script.
+	err = Run(context.Background(), logger, "sh", "-c", "echo foo; echo bar >&2", "user_pw", "secret", "owner_pw", "secret")
+	assert.Nil(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = Run(ctx, logger, "sh", "-c", "echo foo; sleep 1", "user_pw", "secret")
+	assert.NotNil(t, err)
+	logs, err := ioutil.ReadFile(f.Name())
+	assert.Nil(t, err)
+	assert.Contains(t, string(logs), "foo")
+	assert.Contains(t, string(logs), "bar")
+	assert.NotContains(t, string(logs), "secret")
+}
+
+func TestRedact(t *testing.T) {
+	assert.Equal(t, "pdftk a.pdf output b.pdf", redact([]string{"pdftk", "a.pdf", "output", "b.pdf"}))
+	assert.Equal(t, "pdftk a.pdf output b.pdf user_pw *** owner_pw ***", redact([]string{"pdftk", "a.pdf", "output", "b.pdf", "user_pw", "foo", "owner_pw", "bar"}))
+	assert.Equal(t, "gs '-sPDFPassword=***' a.pdf", redact([]string{"gs", "-sPDFPassword=foo", "a.pdf"}))
+	assert.Equal(t, "qpdf '--password=***' a.pdf b.pdf", redact([]string{"qpdf", "--password=foo", "a.pdf", "b.pdf"}))
+	assert.Equal(t, `echo 'it'\''s' ''`, redact([]string{"echo", "it's", ""}))
 }
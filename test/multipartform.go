@@ -51,6 +51,26 @@ func MarkdownMultipartForm(t *testing.T, formValues map[string]string) (*bytes.B
 	return multipartForm(t, "markdown", formValues, fpaths)
 }
 
+/*
+SVGMultipartForm returns the body
+for a multipart/form-data request with all
+files under "testdata/svg" folder.
+*/
+func SVGMultipartForm(t *testing.T, formValues map[string]string) (*bytes.Buffer, string) {
+	fpaths := SVGFpaths(t)
+	return multipartForm(t, "svg", formValues, fpaths)
+}
+
+/*
+EPUBMultipartForm returns the body
+for a multipart/form-data request with all
+files under "testdata/epub" folder.
+*/
+func EPUBMultipartForm(t *testing.T, formValues map[string]string) (*bytes.Buffer, string) {
+	fpaths := EPUBFpaths(t)
+	return multipartForm(t, "epub", formValues, fpaths)
+}
+
 /*
 OfficeMultipartForm returns the body
 for a multipart/form-data request with all
@@ -68,6 +68,32 @@ func MarkdownFpaths(t *testing.T) []string {
 	}
 }
 
+// SVGFpaths return the paths of all
+// files under "testdata/svg" folder.
+func SVGFpaths(t *testing.T) []string {
+	return []string{
+		fpath(t, "svg", "chart.svg"),
+	}
+}
+
+// EPUBFpaths return the paths of all
+// files under "testdata/epub" folder.
+func EPUBFpaths(t *testing.T) []string {
+	return []string{
+		fpath(t, "epub", "book.epub"),
+	}
+}
+
+// DPRFpaths return the paths of all
+// files under "testdata/dpr" folder.
+func DPRFpaths(t *testing.T) []string {
+	return []string{
+		fpath(t, "dpr", "index.html"),
+		fpath(t, "dpr", "1x.png"),
+		fpath(t, "dpr", "2x.png"),
+	}
+}
+
 // OfficeFpaths return the paths of all
 // files under "testdata/office" folder.
 func OfficeFpaths(t *testing.T) []string {
@@ -78,7 +104,13 @@ func OfficeFpaths(t *testing.T) []string {
 	}
 }
 
-func fpath(t *testing.T, kind, filename string) string {
+// RasterizeFpath returns the path of
+// a PDF file under "testdata/pdf" folder.
+func RasterizeFpath(tb testing.TB) string {
+	return fpath(tb, "pdf", "gotenberg.pdf")
+}
+
+func fpath(t testing.TB, kind, filename string) string {
 	require.NotEmpty(t, kind)
 	require.NotEmpty(t, filename)
 	fpath := fmt.Sprintf("%s/%s/%s", testdataDirectoryPath, kind, filename)